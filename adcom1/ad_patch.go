@@ -0,0 +1,80 @@
+package adcom1
+
+import "encoding/json"
+
+// AdPatch is an all-pointer equivalent of Ad, used as a sparse Ad object (e.g., Audit.Corr).
+// Nil fields are absent, while non-nil fields (including pointers to zero values and empty arrays) are present,
+// so that a correction to “secure”: 0 or to an empty “cat” array survives marshaling.
+//
+// Display, Video and Audio are corrected as a whole: a non-nil object replaces the original one.
+// Audit is not part of a correction.
+type AdPatch struct {
+	ID      *string              `json:"id,omitempty"`      // Ad.ID
+	ADomain *[]string            `json:"adomain,omitempty"` // Ad.ADomain
+	Bundle  *[]string            `json:"bundle,omitempty"`  // Ad.Bundle
+	IURL    *string              `json:"iurl,omitempty"`    // Ad.IURL
+	Cat     *[]string            `json:"cat,omitempty"`     // Ad.Cat
+	CatTax  *CategoryTaxonomy    `json:"cattax,omitempty"`  // Ad.CatTax
+	Lang    *string              `json:"lang,omitempty"`    // Ad.Lang
+	Attr    *[]CreativeAttribute `json:"attr,omitempty"`    // Ad.Attr
+	Secure  *int8                `json:"secure,omitempty"`  // Ad.Secure
+	MRating *MediaRating         `json:"mrating,omitempty"` // Ad.MRating
+	Init    *int64               `json:"init,omitempty"`    // Ad.Init
+	LastMod *int64               `json:"lastmod,omitempty"` // Ad.LastMod
+	Display *Display             `json:"display,omitempty"` // Ad.Display
+	Video   *Video               `json:"video,omitempty"`   // Ad.Video
+	Audio   *Audio               `json:"audio,omitempty"`   // Ad.Audio
+	Ext     json.RawMessage      `json:"ext,omitempty"`     // Ad.Ext
+}
+
+// Apply sets fields of ad, present in the patch.
+func (p *AdPatch) Apply(ad *Ad) {
+	if p.ID != nil {
+		ad.ID = *p.ID
+	}
+	if p.ADomain != nil {
+		ad.ADomain = *p.ADomain
+	}
+	if p.Bundle != nil {
+		ad.Bundle = *p.Bundle
+	}
+	if p.IURL != nil {
+		ad.IURL = *p.IURL
+	}
+	if p.Cat != nil {
+		ad.Cat = *p.Cat
+	}
+	if p.CatTax != nil {
+		ad.CatTax = *p.CatTax
+	}
+	if p.Lang != nil {
+		ad.Lang = *p.Lang
+	}
+	if p.Attr != nil {
+		ad.Attr = *p.Attr
+	}
+	if p.Secure != nil {
+		ad.Secure = *p.Secure
+	}
+	if p.MRating != nil {
+		ad.MRating = *p.MRating
+	}
+	if p.Init != nil {
+		ad.Init = *p.Init
+	}
+	if p.LastMod != nil {
+		ad.LastMod = *p.LastMod
+	}
+	if p.Display != nil {
+		ad.Display = p.Display
+	}
+	if p.Video != nil {
+		ad.Video = p.Video
+	}
+	if p.Audio != nil {
+		ad.Audio = p.Audio
+	}
+	if p.Ext != nil {
+		ad.Ext = p.Ext
+	}
+}
@@ -0,0 +1,45 @@
+package adcom1_test
+
+import (
+	"encoding/json"
+
+	. "github.com/prebid/openrtb/v20/adcom1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AdPatch", func() {
+	It("should keep zero values of a sparse correction", func() {
+		var audit Audit
+		Expect(json.Unmarshal([]byte(`{"status":3,"corr":{"cat":["IAB13"],"secure":0,"attr":[]}}`), &audit)).To(Succeed())
+		Expect(audit.Corr).To(Equal(&AdPatch{
+			Cat:    &[]string{"IAB13"},
+			Secure: Int8Ptr(0),
+			Attr:   &[]CreativeAttribute{},
+		}))
+	})
+
+	It("should apply present fields only", func() {
+		ad := &Ad{
+			ID:     "ad",
+			Cat:    []string{"IAB3"},
+			Secure: 1,
+			Attr:   []CreativeAttribute{AttrAudioAuto},
+			Lang:   "en",
+		}
+		patch := &AdPatch{
+			Cat:    &[]string{"IAB13"},
+			Secure: Int8Ptr(0),
+			Attr:   &[]CreativeAttribute{},
+		}
+		patch.Apply(ad)
+		Expect(ad).To(Equal(&Ad{
+			ID:     "ad",
+			Cat:    []string{"IAB13"},
+			Secure: 0,
+			Attr:   []CreativeAttribute{},
+			Lang:   "en",
+		}))
+	})
+})
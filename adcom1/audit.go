@@ -45,7 +45,9 @@ type Audit struct {
 	// Definition:
 	//   Correction object wherein the auditor can specify changes to attributes of the Ad object or its children they believe to be proper.
 	//   For example, if the original Ad indicated a category of “IAB3”, but the auditor deems the correct category to be “IAB13”, then corr could include a sparse Ad object including just the cat array indicating “IAB13”.
-	Corr *Ad `json:"corr,omitempty"` // TODO: probably, this won't work due to "omitempty" stuff. Probably, will need an all-pointer Ad equivalent.
+	// Dev note:
+	//   AdPatch keeps zero values (e.g., "secure": 0) of the sparse object, which Ad would omit.
+	Corr *AdPatch `json:"corr,omitempty"`
 
 	// Attribute:
 	//   ext
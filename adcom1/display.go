@@ -60,7 +60,8 @@ type Display struct {
 	//   Note that mixing absolute and relative sizes is not recommended.
 	// Dev note:
 	//   This is kept as `int8` because ratio values are expected to be quite small (like 16:9).
-	WRatio int8 `json:"wratio,omitempty"`
+	//   It is a pointer, so that an explicit 0 round-trips, rather than being dropped as absent.
+	WRatio *int8 `json:"wratio,omitempty"`
 
 	// Attribute:
 	//   hratio
@@ -71,7 +72,8 @@ type Display struct {
 	//   Note that mixing absolute and relative sizes is not recommended.
 	// Dev note:
 	//   This is kept as `int8` because ratio values are expected to be quite small (like 16:9).
-	HRatio int8 `json:"hratio,omitempty"`
+	//   It is a pointer, so that an explicit 0 round-trips, rather than being dropped as absent.
+	HRatio *int8 `json:"hratio,omitempty"`
 
 	// Attribute:
 	//   priv
@@ -0,0 +1,16 @@
+package adcom1
+
+// Int8Ptr returns pointer to passed argument.
+func Int8Ptr(n int8) *int8 {
+	return &n
+}
+
+// Int64Ptr returns pointer to passed argument.
+func Int64Ptr(n int64) *int64 {
+	return &n
+}
+
+// StringPtr returns pointer to passed argument.
+func StringPtr(s string) *string {
+	return &s
+}
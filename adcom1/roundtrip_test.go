@@ -0,0 +1,60 @@
+package adcom1_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+
+	. "github.com/prebid/openrtb/v20/adcom1"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON round-trip", func() {
+	DescribeTable(
+		"of random values",
+
+		func(subject interface{}) {
+			rnd := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 200; i++ {
+				v := quickjson.New(rnd, reflect.TypeOf(subject).Elem()).Addr().Interface()
+				actual, err := quickjson.RoundTrip(v)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(v))
+			}
+		},
+
+		Entry("Ad", new(Ad)),
+		Entry("AdPatch", new(AdPatch)),
+		Entry("Placement", new(Placement)),
+		Entry("Site", new(Site)),
+		Entry("App", new(App)),
+		Entry("DOOH", new(DOOH)),
+		Entry("User", new(User)),
+		Entry("Device", new(Device)),
+		Entry("Regs", new(Regs)),
+		Entry("Restrictions", new(Restrictions)),
+	)
+
+	DescribeTable(
+		"of pointers to zero values",
+
+		func(subject interface{}, expected string) {
+			actual, err := quickjson.RoundTrip(subject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(subject))
+			Expect(json.Marshal(subject)).To(MatchJSON(expected))
+		},
+
+		Entry("Regs.COPPA", &Regs{COPPA: Int8Ptr(0)}, `{"coppa":0}`),
+		Entry("Regs.GDPR", &Regs{GDPR: Int8Ptr(0)}, `{"gdpr":0}`),
+		Entry("Display.WRatio", &Display{WRatio: Int8Ptr(0), HRatio: Int8Ptr(0)}, `{"wratio":0,"hratio":0}`),
+		Entry("AdPatch.Secure", &AdPatch{Secure: Int8Ptr(0)}, `{"secure":0}`),
+		Entry("AdPatch.Cat", &AdPatch{Cat: &[]string{}}, `{"cat":[]}`),
+		Entry("Audit.Corr", &Audit{Corr: &AdPatch{Secure: Int8Ptr(0)}}, `{"corr":{"secure":0}}`),
+	)
+})
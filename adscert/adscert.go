@@ -0,0 +1,214 @@
+// Package adscert provides signing and verification of bid requests by Ads.cert (Signed Bid Requests, version 1):
+// digest of immutable attributes (Source.DSMap), ECDSA P-256 signature (Source.DS) and certificate (Source.Cert),
+// for OpenRTB 3.0 Source and for OpenRTB 2.x Source.Ext
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb/blob/master/OpenRTB%20v3.0%20FINAL.md#inventory-authentication
+package adscert
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Errors.
+var (
+	ErrUnsigned     = errors.New("adscert: request is not signed")
+	ErrUnknownField = errors.New("adscert: unknown field of dsmap")
+	ErrSignature    = errors.New("adscert: signature mismatch")
+	ErrCurve        = errors.New("adscert: key is not ECDSA P-256")
+	ErrNoKey        = errors.New("adscert: no key found")
+)
+
+// Error is an error of verification, with no-bid reason to be reported
+// (openrtb3.NoBidAuthenticationUnavailable or openrtb3.NoBidAuthenticationViolation).
+type Error struct {
+	Reason openrtb3.NoBidReason
+	Err    error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (no-bid reason %d)", e.Err, e.Reason)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// DefaultDSMap lists fields of the digest, used by Signer unless set otherwise.
+const DefaultDSMap = "tid=&ts=&cert=&domain=&bundle=&consent=&ft=&ip=&ipv6=&ifa=&w=&h="
+
+// Format types (ft field).
+const (
+	FormatDisplay = "d"
+	FormatVideo   = "v"
+	FormatAudio   = "a"
+)
+
+// known fields of the digest.
+var known = map[string]bool{
+	"tid": true, "ts": true, "cert": true,
+	"domain": true, "bundle": true, "consent": true,
+	"ft": true, "ip": true, "ipv6": true, "ifa": true, "w": true, "h": true,
+}
+
+// Fields holds values of digest fields by identifier (see DefaultDSMap); absent fields are empty.
+type Fields map[string]string
+
+// Digest returns digest string of fields, listed by dsmap: identifiers separated by "&", optionally followed by "="
+// (e.g., "tid=&ts=&cert="); the digest lists them in the same order, followed by (query-escaped) values (e.g., "tid=ABC&ts=1530925123&cert=ads-cert.1.txt").
+//
+// ErrUnknownField is returned for a field, not listed in DefaultDSMap.
+func (f Fields) Digest(dsmap string) (string, error) {
+	var sb strings.Builder
+	for _, id := range strings.Split(dsmap, "&") {
+		id = strings.TrimSuffix(strings.TrimSpace(id), "=")
+		if id == "" {
+			continue
+		}
+		if !known[id] {
+			return "", fmt.Errorf("%w: %q", ErrUnknownField, id)
+		}
+		if sb.Len() != 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(id)
+		sb.WriteByte('=')
+		sb.WriteString(url.QueryEscape(f[id]))
+	}
+	return sb.String(), nil
+}
+
+// RequestFields returns digest fields of OpenRTB 3.0 request: Source, Context (site, app, user and device)
+// and placement of the first item (format type and size).
+func RequestFields(req *openrtb3.Request) (Fields, error) {
+	f := make(Fields)
+	if src := req.Source; src != nil {
+		f.set("tid", src.TID)
+		f.setInt("ts", src.TS)
+		f.set("cert", src.Cert)
+	}
+
+	if len(req.Context) != 0 {
+		var ctx adcom1.RequestContext
+		if err := json.Unmarshal(req.Context, &ctx); err != nil {
+			return nil, err
+		}
+		if ctx.Site != nil {
+			f.set("domain", ctx.Site.Domain)
+		}
+		if ctx.App != nil {
+			f.set("domain", ctx.App.Domain)
+			f.set("bundle", ctx.App.Bundle)
+		}
+		if ctx.User != nil {
+			f.set("consent", ctx.User.Consent)
+		}
+		if ctx.Device != nil {
+			f.set("ip", ctx.Device.IP)
+			f.set("ipv6", ctx.Device.IPv6)
+			f.set("ifa", ctx.Device.IFA)
+		}
+	}
+
+	if len(req.Item) != 0 && len(req.Item[0].Spec) != 0 {
+		var spec adcom1.ItemSpec
+		if err := json.Unmarshal(req.Item[0].Spec, &spec); err != nil {
+			return nil, err
+		}
+		if p := spec.Placement; p != nil {
+			switch {
+			case p.Display != nil:
+				f.set("ft", FormatDisplay)
+				f.setInt("w", p.Display.W)
+				f.setInt("h", p.Display.H)
+			case p.Video != nil:
+				f.set("ft", FormatVideo)
+				f.setInt("w", p.Video.W)
+				f.setInt("h", p.Video.H)
+			case p.Audio != nil:
+				f.set("ft", FormatAudio)
+			}
+		}
+	}
+	return f, nil
+}
+
+// BidRequestFields returns digest fields of OpenRTB 2.x request: Source (Source.TID and ads.cert attributes of Source.Ext, see SourceExt),
+// site, app, user and device, and the first impression (format type and size).
+func BidRequestFields(req *openrtb2.BidRequest) (Fields, error) {
+	f := make(Fields)
+	if src := req.Source; src != nil {
+		ext, err := ReadSourceExt(src)
+		if err != nil {
+			return nil, err
+		}
+		f.set("tid", src.TID)
+		f.setInt("ts", ext.TS)
+		f.set("cert", ext.Cert)
+	}
+
+	if req.Site != nil {
+		f.set("domain", req.Site.Domain)
+	}
+	if req.App != nil {
+		f.set("domain", req.App.Domain)
+		f.set("bundle", req.App.Bundle)
+	}
+	if req.User != nil {
+		f.set("consent", req.User.Consent)
+	}
+	if req.Device != nil {
+		f.set("ip", req.Device.IP)
+		f.set("ipv6", req.Device.IPv6)
+		f.set("ifa", req.Device.IFA)
+	}
+
+	if len(req.Imp) != 0 {
+		imp := &req.Imp[0]
+		switch {
+		case imp.Banner != nil:
+			f.set("ft", FormatDisplay)
+			w, h := imp.Banner.W, imp.Banner.H
+			if (w == nil || h == nil) && len(imp.Banner.Format) != 0 {
+				w, h = &imp.Banner.Format[0].W, &imp.Banner.Format[0].H
+			}
+			f.setIntPtr("w", w)
+			f.setIntPtr("h", h)
+		case imp.Video != nil:
+			f.set("ft", FormatVideo)
+			f.setIntPtr("w", imp.Video.W)
+			f.setIntPtr("h", imp.Video.H)
+		case imp.Audio != nil:
+			f.set("ft", FormatAudio)
+		}
+	}
+	return f, nil
+}
+
+func (f Fields) set(id, value string) {
+	if value != "" {
+		f[id] = value
+	}
+}
+
+func (f Fields) setInt(id string, value int64) {
+	if value != 0 {
+		f[id] = strconv.FormatInt(value, 10)
+	}
+}
+
+func (f Fields) setIntPtr(id string, value *int64) {
+	if value != nil {
+		f.setInt(id, *value)
+	}
+}
@@ -0,0 +1,13 @@
+package adscert_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAdscert(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Adscert Suite")
+}
@@ -0,0 +1,205 @@
+package adscert_test
+
+import (
+	"encoding/json"
+	"errors"
+
+	. "github.com/prebid/openrtb/v20/adscert"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable(
+	"Fields.Digest",
+	func(f Fields, dsmap, expected string, expectedErr error) {
+		digest, err := f.Digest(dsmap)
+		if expectedErr != nil {
+			Expect(errors.Is(err, expectedErr)).To(BeTrue())
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		Expect(digest).To(Equal(expected))
+	},
+	Entry("in order of dsmap", Fields{"tid": "ABC", "ts": "1530925123", "cert": "ads-cert.1.txt"},
+		"cert=&tid=&ts=", "cert=ads-cert.1.txt&tid=ABC&ts=1530925123", nil),
+	Entry("without =", Fields{"tid": "ABC", "w": "300"}, "tid&w", "tid=ABC&w=300", nil),
+	Entry("absent field", Fields{"tid": "ABC"}, "tid=&ifa=", "tid=ABC&ifa=", nil),
+	Entry("escaped value", Fields{"consent": "a&b=c"}, "consent=", "consent=a%26b%3Dc", nil),
+	Entry("unknown field", Fields{}, "tid=&foo=", "", ErrUnknownField),
+)
+
+var _ = Describe("BidRequestFields", func() {
+	It("should read fields", func() {
+		req := &openrtb2.BidRequest{
+			Source: &openrtb2.Source{TID: "ABC", Ext: json.RawMessage(`{"ts":1530925123,"cert":"ads-cert.1.txt"}`)},
+			Site:   &openrtb2.Site{Domain: "newsite.com"},
+			User:   &openrtb2.User{Consent: "CO"},
+			Device: &openrtb2.Device{IP: "192.0.2.1", IFA: "ifa"},
+			Imp:    []openrtb2.Imp{{Banner: &openrtb2.Banner{Format: []openrtb2.Format{{W: 300, H: 250}}}}},
+		}
+		Expect(BidRequestFields(req)).To(Equal(Fields{
+			"tid": "ABC", "ts": "1530925123", "cert": "ads-cert.1.txt",
+			"domain": "newsite.com", "consent": "CO", "ip": "192.0.2.1", "ifa": "ifa",
+			"ft": "d", "w": "300", "h": "250",
+		}))
+	})
+})
+
+var _ = Describe("RequestFields", func() {
+	It("should read fields", func() {
+		req := &openrtb3.Request{
+			Source:  &openrtb3.Source{TID: "ABC", TS: 1530925123, Cert: "ads-cert.1.txt"},
+			Context: json.RawMessage(`{"app":{"domain":"newsite.com","bundle":"com.newsite"},"device":{"ipv6":"2001:db8::1"}}`),
+			Item:    []openrtb3.Item{{ID: "1", Spec: json.RawMessage(`{"placement":{"video":{"w":640,"h":480}}}`)}},
+		}
+		Expect(RequestFields(req)).To(Equal(Fields{
+			"tid": "ABC", "ts": "1530925123", "cert": "ads-cert.1.txt",
+			"domain": "newsite.com", "bundle": "com.newsite", "ipv6": "2001:db8::1",
+			"ft": "v", "w": "640", "h": "480",
+		}))
+	})
+})
+
+var _ = Describe("WriteSourceExt", func() {
+	It("should preserve other attributes", func() {
+		src := &openrtb2.Source{Ext: json.RawMessage(`{"schain":{"ver":"1.0"},"ds":"old"}`)}
+		Expect(WriteSourceExt(src, SourceExt{DS: "new", Cert: "c"})).To(Succeed())
+		Expect(src.Ext).To(MatchJSON(`{"schain":{"ver":"1.0"},"ds":"new","cert":"c"}`))
+		Expect(ReadSourceExt(src)).To(Equal(SourceExt{DS: "new", Cert: "c"}))
+
+		Expect(WriteSourceExt(src, SourceExt{})).To(Succeed())
+		Expect(src.Ext).To(MatchJSON(`{"schain":{"ver":"1.0"}}`))
+		src.Ext = nil
+		Expect(WriteSourceExt(src, SourceExt{})).To(Succeed())
+		Expect(src.Ext).To(BeNil())
+	})
+})
+
+var _ = Describe("keys", func() {
+	It("should load keys", func() {
+		_, err := LoadPrivateKey("testdata/private.pem")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = Dir("testdata").PublicKey("newsite.com", "ads-cert.1.txt")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should reject keys of other curves", func() {
+		_, err := LoadPrivateKey("testdata/p384.pem")
+		Expect(err).To(Equal(ErrCurve))
+	})
+
+	It("should reject paths", func() {
+		_, err := Dir("testdata").PublicKey("..", "adscert/testdata/newsite.com/ads-cert.1.txt")
+		Expect(err).To(Equal(ErrNoKey))
+		_, err = Dir("testdata").PublicKey("newsite.com", "../newsite.com/ads-cert.1.txt")
+		Expect(err).To(Equal(ErrNoKey))
+	})
+})
+
+var _ = Describe("Signer", func() {
+	var signer *Signer
+	verifier := &Verifier{Certs: Dir("testdata")}
+
+	BeforeEach(func() {
+		key, err := LoadPrivateKey("testdata/private.pem")
+		Expect(err).NotTo(HaveOccurred())
+		signer = &Signer{Key: key, Cert: "ads-cert.1.txt"}
+	})
+
+	reason := func(err error) openrtb3.NoBidReason {
+		var e *Error
+		ExpectWithOffset(1, errors.As(err, &e)).To(BeTrue())
+		return e.Reason
+	}
+
+	Context("OpenRTB 3.0", func() {
+		var req *openrtb3.Request
+
+		BeforeEach(func() {
+			req = &openrtb3.Request{
+				ID:      "1",
+				Source:  &openrtb3.Source{TID: "ABC", TS: 1530925123},
+				Context: json.RawMessage(`{"site":{"domain":"newsite.com"},"device":{"ip":"192.0.2.1"}}`),
+				Item:    []openrtb3.Item{{ID: "1", Spec: json.RawMessage(`{"placement":{"display":{"w":300,"h":250}}}`)}},
+			}
+		})
+
+		It("should sign and verify", func() {
+			signer.Digest = true
+			Expect(signer.SignRequest(req)).To(Succeed())
+			Expect(req.Source.Cert).To(Equal("ads-cert.1.txt"))
+			Expect(req.Source.DSMap).To(Equal(DefaultDSMap))
+			Expect(req.Source.DS).NotTo(BeEmpty())
+			Expect(req.Source.Digest).To(Equal("tid=ABC&ts=1530925123&cert=ads-cert.1.txt&domain=newsite.com&bundle=&consent=&ft=d&ip=192.0.2.1&ipv6=&ifa=&w=300&h=250"))
+			Expect(verifier.VerifyRequest(req)).To(Succeed())
+		})
+
+		It("should detect tampering", func() {
+			Expect(signer.SignRequest(req)).To(Succeed())
+			req.Context = json.RawMessage(`{"site":{"domain":"newsite.com"},"device":{"ip":"192.0.2.2"}}`)
+			err := verifier.VerifyRequest(req)
+			Expect(errors.Is(err, ErrSignature)).To(BeTrue())
+			Expect(reason(err)).To(Equal(openrtb3.NoBidAuthenticationViolation))
+		})
+
+		It("should reject unknown fields", func() {
+			Expect(signer.SignRequest(req)).To(Succeed())
+			req.Source.DSMap += "&foo="
+			err := verifier.VerifyRequest(req)
+			Expect(errors.Is(err, ErrUnknownField)).To(BeTrue())
+			Expect(reason(err)).To(Equal(openrtb3.NoBidAuthenticationViolation))
+		})
+
+		It("should report unsigned requests", func() {
+			err := verifier.VerifyRequest(req)
+			Expect(errors.Is(err, ErrUnsigned)).To(BeTrue())
+			Expect(reason(err)).To(Equal(openrtb3.NoBidAuthenticationUnavailable))
+		})
+
+		It("should report unknown certificates", func() {
+			signer.Cert = "ads-cert.2.txt"
+			Expect(signer.SignRequest(req)).To(Succeed())
+			Expect(reason(verifier.VerifyRequest(req))).To(Equal(openrtb3.NoBidAuthenticationUnavailable))
+		})
+	})
+
+	Context("OpenRTB 2.x", func() {
+		var req *openrtb2.BidRequest
+
+		BeforeEach(func() {
+			req = &openrtb2.BidRequest{
+				ID:     "1",
+				Source: &openrtb2.Source{TID: "ABC", Ext: json.RawMessage(`{"ts":1530925123,"schain":{"ver":"1.0"}}`)},
+				Site:   &openrtb2.Site{Domain: "newsite.com"},
+				Imp:    []openrtb2.Imp{{ID: "1", Video: &openrtb2.Video{W: openrtb2.Int64Ptr(640), H: openrtb2.Int64Ptr(480)}}},
+			}
+		})
+
+		It("should sign and verify", func() {
+			Expect(signer.SignBidRequest(req)).To(Succeed())
+			ext, err := ReadSourceExt(req.Source)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ext.TS).To(BeEquivalentTo(1530925123))
+			Expect(ext.Cert).To(Equal("ads-cert.1.txt"))
+			Expect(ext.DS).NotTo(BeEmpty())
+			Expect(ext.Digest).To(BeEmpty())
+			Expect(string(req.Source.Ext)).To(ContainSubstring(`"schain":{"ver":"1.0"}`))
+			Expect(verifier.VerifyBidRequest(req)).To(Succeed())
+		})
+
+		It("should detect tampering", func() {
+			Expect(signer.SignBidRequest(req)).To(Succeed())
+			req.Imp[0].Video.W = openrtb2.Int64Ptr(320)
+			Expect(reason(verifier.VerifyBidRequest(req))).To(Equal(openrtb3.NoBidAuthenticationViolation))
+		})
+
+		It("should report unsigned requests", func() {
+			Expect(reason(verifier.VerifyBidRequest(req))).To(Equal(openrtb3.NoBidAuthenticationUnavailable))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package adscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// ParsePrivateKey parses PEM-encoded ECDSA P-256 private key ("EC PRIVATE KEY", or PKCS #8 "PRIVATE KEY").
+func ParsePrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrNoKey
+	}
+
+	var key interface{}
+	var err error
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("adscert: unexpected PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ec, ok := key.(*ecdsa.PrivateKey)
+	if !ok || ec.Curve != elliptic.P256() {
+		return nil, ErrCurve
+	}
+	return ec, nil
+}
+
+// ParsePublicKey parses PEM-encoded ECDSA P-256 public key ("PUBLIC KEY"), or X.509 certificate ("CERTIFICATE") of one.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrNoKey
+	}
+
+	var key interface{}
+	switch block.Type {
+	case "PUBLIC KEY":
+		var err error
+		if key, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, err
+		}
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		key = cert.PublicKey
+	default:
+		return nil, fmt.Errorf("adscert: unexpected PEM block %q", block.Type)
+	}
+
+	ec, ok := key.(*ecdsa.PublicKey)
+	if !ok || ec.Curve != elliptic.P256() {
+		return nil, ErrCurve
+	}
+	return ec, nil
+}
+
+// LoadPrivateKey reads private key file (see ParsePrivateKey).
+func LoadPrivateKey(path string) (*ecdsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKey(data)
+}
+
+// LoadPublicKey reads certificate file (see ParsePublicKey).
+func LoadPublicKey(path string) (*ecdsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(data)
+}
+
+// Certs resolves certificates (Source.Cert), published by domain (Site.Domain or App.Domain), to public keys.
+type Certs interface {
+	PublicKey(domain, cert string) (*ecdsa.PublicKey, error)
+}
+
+// CertsFunc is an adapter to use a function as Certs.
+type CertsFunc func(domain, cert string) (*ecdsa.PublicKey, error)
+
+// PublicKey implements Certs.
+func (f CertsFunc) PublicKey(domain, cert string) (*ecdsa.PublicKey, error) {
+	return f(domain, cert)
+}
+
+// Dir is Certs, reading certificate files from a local directory: file cert in subdirectory domain,
+// or in the directory itself, for requests without domain (e.g., "certs/newsite.com/ads-cert.1.txt").
+//
+// Files are read on every call; wrap Dir with a cache for production traffic.
+type Dir string
+
+// PublicKey implements Certs; ErrNoKey is returned for domain or cert, which are not plain file names.
+func (d Dir) PublicKey(domain, cert string) (*ecdsa.PublicKey, error) {
+	if !plainName(cert) || (domain != "" && !plainName(domain)) {
+		return nil, ErrNoKey
+	}
+	return LoadPublicKey(filepath.Join(string(d), domain, cert))
+}
+
+func plainName(s string) bool {
+	return s != "" && s != "." && s != ".." && !strings.ContainsAny(s, `/\`)
+}
@@ -0,0 +1,155 @@
+package adscert
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Signer signs bid requests with private key of certificate Cert.
+type Signer struct {
+	Key    *ecdsa.PrivateKey
+	Cert   string // file name of the certificate (Source.Cert)
+	DSMap  string // fields of the digest (Source.DSMap); DefaultDSMap, if empty
+	Digest bool   // whether to set Source.Digest (for debugging)
+}
+
+// Sign returns base64-encoded ECDSA signature (ASN.1 DER) of SHA-256 hash of digest.
+func (s *Signer) Sign(digest string) (string, error) {
+	hash := sha256.Sum256([]byte(digest))
+	sig, err := ecdsa.SignASN1(rand.Reader, s.Key, hash[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+func (s *Signer) dsmap() string {
+	if s.DSMap == "" {
+		return DefaultDSMap
+	}
+	return s.DSMap
+}
+
+// SignRequest sets Source.Cert, DSMap and DS (and Digest, if requested) of OpenRTB 3.0 request;
+// Source is added if absent. Other fields (e.g., Source.TID and TS) must be final.
+func (s *Signer) SignRequest(req *openrtb3.Request) error {
+	if req.Source == nil {
+		req.Source = &openrtb3.Source{}
+	}
+	req.Source.Cert, req.Source.DSMap = s.Cert, s.dsmap()
+
+	digest, ds, err := s.sign(RequestFields(req))
+	if err != nil {
+		return err
+	}
+	req.Source.DS, req.Source.Digest = ds, ""
+	if s.Digest {
+		req.Source.Digest = digest
+	}
+	return nil
+}
+
+// SignBidRequest sets cert, dsmap and ds (and digest, if requested) of Source.Ext (see SourceExt) of OpenRTB 2.x request;
+// Source is added if absent. Other fields (e.g., Source.TID and ts of Source.Ext) must be final.
+func (s *Signer) SignBidRequest(req *openrtb2.BidRequest) error {
+	if req.Source == nil {
+		req.Source = &openrtb2.Source{}
+	}
+	ext, err := ReadSourceExt(req.Source)
+	if err != nil {
+		return err
+	}
+	ext.Cert, ext.DSMap, ext.DS, ext.Digest = s.Cert, s.dsmap(), "", ""
+	if err := WriteSourceExt(req.Source, ext); err != nil {
+		return err
+	}
+
+	digest, ds, err := s.sign(BidRequestFields(req))
+	if err != nil {
+		return err
+	}
+	ext.DS = ds
+	if s.Digest {
+		ext.Digest = digest
+	}
+	return WriteSourceExt(req.Source, ext)
+}
+
+func (s *Signer) sign(f Fields, err error) (digest, ds string, _ error) {
+	if err != nil {
+		return "", "", err
+	}
+	if digest, err = f.Digest(s.dsmap()); err != nil {
+		return "", "", err
+	}
+	ds, err = s.Sign(digest)
+	return digest, ds, err
+}
+
+// Verifier verifies signed bid requests against certificates.
+type Verifier struct {
+	Certs Certs
+}
+
+// Verify checks signature ds of digest against certificate cert of domain.
+//
+// *Error is returned on failure: openrtb3.NoBidAuthenticationUnavailable, if the certificate can not be resolved,
+// or openrtb3.NoBidAuthenticationViolation (with ErrSignature), if the signature does not match.
+func (v *Verifier) Verify(domain, cert, digest, ds string) error {
+	key, err := v.Certs.PublicKey(domain, cert)
+	if err != nil {
+		return &Error{Reason: openrtb3.NoBidAuthenticationUnavailable, Err: err}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(ds)
+	hash := sha256.Sum256([]byte(digest))
+	if err != nil || !ecdsa.VerifyASN1(key, hash[:], sig) {
+		return &Error{Reason: openrtb3.NoBidAuthenticationViolation, Err: ErrSignature}
+	}
+	return nil
+}
+
+// VerifyRequest checks Source.DS of OpenRTB 3.0 request against the digest of Source.DSMap fields and Source.Cert (see Verify).
+//
+// Unsigned requests (without DS or DSMap) fail with openrtb3.NoBidAuthenticationUnavailable (ErrUnsigned),
+// and requests, whose digest can not be built (e.g., ErrUnknownField), with openrtb3.NoBidAuthenticationViolation.
+// Source.Digest is ignored.
+func (v *Verifier) VerifyRequest(req *openrtb3.Request) error {
+	if req.Source == nil || req.Source.DS == "" || req.Source.DSMap == "" {
+		return &Error{Reason: openrtb3.NoBidAuthenticationUnavailable, Err: ErrUnsigned}
+	}
+	f, err := RequestFields(req)
+	return v.verify(f, err, req.Source.DSMap, req.Source.DS)
+}
+
+// VerifyBidRequest checks ds of Source.Ext (see SourceExt) of OpenRTB 2.x request, as VerifyRequest does.
+func (v *Verifier) VerifyBidRequest(req *openrtb2.BidRequest) error {
+	if req.Source == nil {
+		return &Error{Reason: openrtb3.NoBidAuthenticationUnavailable, Err: ErrUnsigned}
+	}
+	ext, err := ReadSourceExt(req.Source)
+	if err != nil {
+		return &Error{Reason: openrtb3.NoBidAuthenticationViolation, Err: err}
+	}
+	if ext.DS == "" || ext.DSMap == "" {
+		return &Error{Reason: openrtb3.NoBidAuthenticationUnavailable, Err: ErrUnsigned}
+	}
+	f, err := BidRequestFields(req)
+	return v.verify(f, err, ext.DSMap, ext.DS)
+}
+
+func (v *Verifier) verify(f Fields, err error, dsmap, ds string) error {
+	if err != nil {
+		return &Error{Reason: openrtb3.NoBidAuthenticationViolation, Err: err}
+	}
+	digest, err := f.Digest(dsmap)
+	if err != nil {
+		return &Error{Reason: openrtb3.NoBidAuthenticationViolation, Err: err}
+	}
+	return v.Verify(f["domain"], f["cert"], digest, ds)
+}
@@ -0,0 +1,54 @@
+package adscert
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// SourceExt holds ads.cert attributes of OpenRTB 2.x Source.Ext, named after OpenRTB 3.0 Source.
+type SourceExt struct {
+	TS     int64  `json:"ts,omitempty"`     // openrtb3.Source.TS
+	DS     string `json:"ds,omitempty"`     // openrtb3.Source.DS
+	DSMap  string `json:"dsmap,omitempty"`  // openrtb3.Source.DSMap
+	Cert   string `json:"cert,omitempty"`   // openrtb3.Source.Cert
+	Digest string `json:"digest,omitempty"` // openrtb3.Source.Digest
+}
+
+// ReadSourceExt returns ads.cert attributes of src.Ext; other attributes are ignored.
+func ReadSourceExt(src *openrtb2.Source) (SourceExt, error) {
+	var ext SourceExt
+	if len(src.Ext) == 0 {
+		return ext, nil
+	}
+	err := json.Unmarshal(src.Ext, &ext)
+	return ext, err
+}
+
+// WriteSourceExt sets ads.cert attributes of src.Ext, preserving other attributes; empty attributes are removed.
+func WriteSourceExt(src *openrtb2.Source, ext SourceExt) error {
+	attrs := make(map[string]json.RawMessage)
+	if len(src.Ext) != 0 {
+		if err := json.Unmarshal(src.Ext, &attrs); err != nil {
+			return err
+		}
+	}
+	for _, k := range []string{"ts", "ds", "dsmap", "cert", "digest"} {
+		delete(attrs, k)
+	}
+
+	data, err := json.Marshal(ext)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &attrs); err != nil {
+		return err
+	}
+
+	if len(attrs) == 0 {
+		src.Ext = nil
+		return nil
+	}
+	src.Ext, err = json.Marshal(attrs)
+	return err
+}
@@ -0,0 +1,468 @@
+// Package auction provides a reference OpenRTB 2.x auction implementation
+//
+// It resolves openrtb2.BidResponse objects received for a given openrtb2.BidRequest into winners,
+// clearing prices and loss reasons (openrtb3.LossReason) for every losing bid.
+//
+// https://iabtechlab.com/standards/openrtb/
+package auction
+
+import (
+	"sort"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// DefaultIncrement is the amount added to the runner-up price in a second price plus auction,
+// unless overridden by Options.Increment.
+const DefaultIncrement = 0.01
+
+// Options configure an auction run.
+type Options struct {
+	// Increment added to the runner-up price in second price plus auctions.
+	// Zero means DefaultIncrement.
+	Increment float64
+
+	// PrioritizeDeals makes every deal bid outrank open market bids.
+	// Guaranteed deals (Deal.Guar = 1) always outrank everything else.
+	PrioritizeDeals bool
+
+	// Pricer computes clearing price for exchange-specific auction types (values 500 and greater).
+	// When nil (or for unknown auction types below 500), the winner pays its bid price (first price).
+	Pricer func(at openrtb3.AuctionType, winner, runnerUp *Candidate) float64
+}
+
+// Result is an outcome of the auction.
+type Result struct {
+	// Winners, at most one per impression, in request impression order.
+	Winners []Winner
+
+	// Losers, one per every losing bid, in response order.
+	Losers []Loser
+}
+
+// Winner describes a winning bid.
+type Winner struct {
+	Candidate
+
+	// AT is the auction type the clearing price was computed with.
+	AT openrtb3.AuctionType
+
+	// Price is the clearing price.
+	Price float64
+}
+
+// Loser describes a losing bid.
+type Loser struct {
+	Candidate
+
+	// Reason is the loss reason to be reported to the bidder (e.g., via ${AUCTION_LOSS} macro).
+	Reason openrtb3.LossReason
+}
+
+// Candidate is a bid, participating in the auction, along with its context.
+type Candidate struct {
+	// Response the bid originates from.
+	Response *openrtb2.BidResponse
+
+	// SeatBid the bid originates from.
+	SeatBid *openrtb2.SeatBid
+
+	// Bid itself.
+	Bid *openrtb2.Bid
+
+	// Imp the bid is placed on; nil if bid refers to unknown impression.
+	Imp *openrtb2.Imp
+
+	// Deal the bid is placed on; nil for open market bids.
+	Deal *openrtb2.Deal
+}
+
+// Seat returns bidder seat ID.
+func (c *Candidate) Seat() string {
+	return c.SeatBid.Seat
+}
+
+// Floor returns effective floor for the candidate: deal floor for deal bids, impression floor otherwise.
+func (c *Candidate) Floor() float64 {
+	if c.Deal != nil {
+		return c.Deal.BidFloor
+	}
+	if c.Imp != nil && c.Imp.BidFloor != nil {
+		return *c.Imp.BidFloor
+	}
+	return 0
+}
+
+// at returns auction type applicable to the candidate: Deal.AT for deal bids (when set), BidRequest.AT otherwise.
+// BidRequest.AT defaults to openrtb3.SecondPricePlus.
+func (c *Candidate) at(req *openrtb2.BidRequest) openrtb3.AuctionType {
+	if c.Deal != nil && c.Deal.AT != 0 {
+		return openrtb3.AuctionType(c.Deal.AT)
+	}
+	if req.AT != 0 {
+		return openrtb3.AuctionType(req.AT)
+	}
+	return openrtb3.SecondPricePlus
+}
+
+// Run runs the auction for req over bids from resps.
+//
+// Each impression is won by at most one bid.
+// Bids are ranked by priority (guaranteed deals first, then, if Options.PrioritizeDeals is set, other deals) and then by price;
+// ties are resolved in favour of the bid received first.
+// Bids of a SeatBid with Group = 1 are won or lost together;
+// if BidRequest.AllImps = 1, such group must also cover every impression of the request.
+//
+// Currency conversion is out of scope: bid prices and floors are compared as is.
+func Run(req *openrtb2.BidRequest, resps []*openrtb2.BidResponse, opts Options) *Result {
+	a := &auction{
+		req:  req,
+		opts: opts,
+		imps: make(map[string]*openrtb2.Imp, len(req.Imp)),
+		lost: make(map[*openrtb2.Bid]openrtb3.LossReason),
+	}
+	if a.opts.Increment == 0 {
+		a.opts.Increment = DefaultIncrement
+	}
+	for i := range req.Imp {
+		a.imps[req.Imp[i].ID] = &req.Imp[i]
+	}
+
+	a.collect(resps)
+	a.resolve()
+	return a.result()
+}
+
+type auction struct {
+	req  *openrtb2.BidRequest
+	opts Options
+	imps map[string]*openrtb2.Imp
+
+	all   []*Candidate                          // all candidates, in response order
+	valid []*Candidate                          // candidates, that passed validation
+	lost  map[*openrtb2.Bid]openrtb3.LossReason // loss reasons
+	won   map[string]*Candidate                 // winners by impression ID
+}
+
+func (a *auction) collect(resps []*openrtb2.BidResponse) {
+	for _, resp := range resps {
+		if resp == nil {
+			continue
+		}
+		for i := range resp.SeatBid {
+			sb := &resp.SeatBid[i]
+			group := make([]*Candidate, 0, len(sb.Bid))
+			groupInvalid := false
+
+			for j := range sb.Bid {
+				c := &Candidate{
+					Response: resp,
+					SeatBid:  sb,
+					Bid:      &sb.Bid[j],
+					Imp:      a.imps[sb.Bid[j].ImpID],
+				}
+				a.all = append(a.all, c)
+				group = append(group, c)
+
+				if reason, ok := a.validate(c); !ok {
+					a.lost[c.Bid] = reason
+					groupInvalid = true
+				}
+			}
+
+			if sb.Group == 1 && !groupInvalid && a.req.AllImps == 1 && !coversAllImps(a.req, sb) {
+				groupInvalid = true
+				for _, c := range group {
+					a.lost[c.Bid] = openrtb3.LossInvalidResponse
+				}
+			}
+
+			for _, c := range group {
+				if _, ok := a.lost[c.Bid]; ok {
+					continue
+				}
+				if sb.Group == 1 && groupInvalid {
+					// one invalid bid disqualifies the whole group
+					a.lost[c.Bid] = openrtb3.LossInvalidResponse
+					continue
+				}
+				a.valid = append(a.valid, c)
+			}
+		}
+	}
+}
+
+// validate checks candidate eligibility, returning loss reason for ineligible ones.
+func (a *auction) validate(c *Candidate) (openrtb3.LossReason, bool) {
+	if c.Response.ID != a.req.ID {
+		return openrtb3.LossInvalidAuctionID, false
+	}
+	if c.Imp == nil {
+		return openrtb3.LossInvalidResponse, false
+	}
+	if !currencyAllowed(a.req, c.Response.Cur) {
+		return openrtb3.LossInvalidResponse, false
+	}
+	if c.Bid.Price <= 0 {
+		return openrtb3.LossMissingBidPrice, false
+	}
+	if !seatAllowed(a.req.WSeat, a.req.BSeat, c.Seat()) {
+		return openrtb3.LossSeatBlocked, false
+	}
+
+	if c.Bid.DealID == "" {
+		if c.Imp.PMP != nil && c.Imp.PMP.PrivateAuction == 1 {
+			return openrtb3.LossInvalidDealID, false
+		}
+		if c.Imp.BidFloor != nil && c.Bid.Price < *c.Imp.BidFloor {
+			return openrtb3.LossBelowAuctionFloor, false
+		}
+		return openrtb3.LossWon, true
+	}
+
+	c.Deal = findDeal(c.Imp, c.Bid.DealID)
+	if c.Deal == nil {
+		return openrtb3.LossInvalidDealID, false
+	}
+	if !seatAllowed(c.Deal.WSeat, nil, c.Seat()) {
+		return openrtb3.LossSeatBlocked, false
+	}
+	if len(c.Deal.WADomain) != 0 && !intersects(c.Deal.WADomain, c.Bid.ADomain) {
+		return openrtb3.LossNotAllowedInDeal, false
+	}
+	if c.Bid.Price < c.Deal.BidFloor {
+		return openrtb3.LossBelowDealFloor, false
+	}
+	return openrtb3.LossWon, true
+}
+
+// resolve picks winners, repeating the selection while all-or-nothing groups get disqualified.
+func (a *auction) resolve() {
+	for {
+		a.won = make(map[string]*Candidate, len(a.imps))
+
+		byImp := make(map[string][]*Candidate, len(a.imps))
+		for _, c := range a.valid {
+			if _, ok := a.lost[c.Bid]; ok {
+				continue
+			}
+			byImp[c.Imp.ID] = append(byImp[c.Imp.ID], c)
+		}
+
+		for impID, cs := range byImp {
+			sort.SliceStable(cs, func(i, j int) bool {
+				pi, pj := a.priority(cs[i]), a.priority(cs[j])
+				if pi != pj {
+					return pi < pj
+				}
+				return cs[i].Bid.Price > cs[j].Bid.Price
+			})
+			a.won[impID] = cs[0]
+		}
+
+		if !a.dropIncompleteGroups() {
+			return
+		}
+	}
+}
+
+// dropIncompleteGroups disqualifies bids of group seat bids, which did not win all of their impressions.
+// It reports whether anything was disqualified.
+func (a *auction) dropIncompleteGroups() bool {
+	dropped := false
+	seen := make(map[*openrtb2.SeatBid]bool)
+
+	for _, c := range a.valid {
+		sb := c.SeatBid
+		if sb.Group != 1 || seen[sb] {
+			continue
+		}
+		seen[sb] = true
+
+		complete := true
+		for i := range sb.Bid {
+			if _, ok := a.lost[&sb.Bid[i]]; ok {
+				complete = false
+				break
+			}
+			// several group bids may target the same impression; it is enough for one of them to win
+			if w := a.won[sb.Bid[i].ImpID]; w == nil || w.SeatBid != sb {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			continue
+		}
+
+		for i := range sb.Bid {
+			b := &sb.Bid[i]
+			if _, ok := a.lost[b]; ok {
+				continue
+			}
+			reason := openrtb3.LossLostToHigherBid
+			if w := a.won[b.ImpID]; w != nil && w.SeatBid != sb && w.Deal != nil {
+				reason = openrtb3.LossLostToDealBid
+			}
+			a.lost[b] = reason
+			dropped = true
+		}
+	}
+	return dropped
+}
+
+func (a *auction) priority(c *Candidate) int {
+	switch {
+	case c.Deal != nil && c.Deal.Guar == 1:
+		return 0
+	case c.Deal != nil && a.opts.PrioritizeDeals:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (a *auction) result() *Result {
+	res := &Result{}
+
+	for i := range a.req.Imp {
+		w := a.won[a.req.Imp[i].ID]
+		if w == nil {
+			continue
+		}
+		at := w.at(a.req)
+		res.Winners = append(res.Winners, Winner{
+			Candidate: *w,
+			AT:        at,
+			Price:     a.clearingPrice(at, w),
+		})
+	}
+
+	for _, c := range a.all {
+		reason, ok := a.lost[c.Bid]
+		if !ok {
+			w := a.won[c.Bid.ImpID]
+			if w == c {
+				continue
+			}
+			reason = openrtb3.LossLostToHigherBid
+			if w.Deal != nil && c.Deal == nil {
+				reason = openrtb3.LossLostToDealBid
+			}
+		}
+		res.Losers = append(res.Losers, Loser{
+			Candidate: *c,
+			Reason:    reason,
+		})
+	}
+
+	return res
+}
+
+func (a *auction) clearingPrice(at openrtb3.AuctionType, w *Candidate) float64 {
+	switch at {
+	case openrtb3.FirstPrice:
+		return w.Bid.Price
+	case openrtb3.SecondPricePlus:
+		price := w.Floor()
+		if r := a.runnerUp(w); r != nil && r.Bid.Price > price {
+			price = r.Bid.Price
+		}
+		price += a.opts.Increment
+		if price > w.Bid.Price {
+			price = w.Bid.Price
+		}
+		return price
+	case openrtb3.DealPrice:
+		if w.Deal != nil {
+			return w.Deal.BidFloor
+		}
+		return w.Bid.Price
+	}
+
+	if at >= 500 && a.opts.Pricer != nil {
+		return a.opts.Pricer(at, w, a.runnerUp(w))
+	}
+	return w.Bid.Price
+}
+
+// runnerUp returns the highest priced eligible bid for the same impression, other than the winner.
+func (a *auction) runnerUp(w *Candidate) *Candidate {
+	var r *Candidate
+	for _, c := range a.valid {
+		if c == w || c.Imp != w.Imp {
+			continue
+		}
+		if _, ok := a.lost[c.Bid]; ok {
+			continue
+		}
+		if r == nil || c.Bid.Price > r.Bid.Price {
+			r = c
+		}
+	}
+	return r
+}
+
+func findDeal(imp *openrtb2.Imp, id string) *openrtb2.Deal {
+	if imp.PMP == nil {
+		return nil
+	}
+	for i := range imp.PMP.Deals {
+		if imp.PMP.Deals[i].ID == id {
+			return &imp.PMP.Deals[i]
+		}
+	}
+	return nil
+}
+
+func currencyAllowed(req *openrtb2.BidRequest, cur string) bool {
+	if len(req.Cur) == 0 {
+		return true
+	}
+	if cur == "" {
+		cur = "USD"
+	}
+	return contains(req.Cur, cur)
+}
+
+func seatAllowed(wseat, bseat []string, seat string) bool {
+	if len(wseat) != 0 && !contains(wseat, seat) {
+		return false
+	}
+	return !contains(bseat, seat)
+}
+
+func coversAllImps(req *openrtb2.BidRequest, sb *openrtb2.SeatBid) bool {
+	for i := range req.Imp {
+		found := false
+		for j := range sb.Bid {
+			if sb.Bid[j].ImpID == req.Imp[i].ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func intersects(a, b []string) bool {
+	for _, s := range b {
+		if contains(a, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+package auction_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAuction(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Auction Suite")
+}
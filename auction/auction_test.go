@@ -0,0 +1,206 @@
+package auction_test
+
+import (
+	. "github.com/prebid/openrtb/v20/auction"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Run", func() {
+	var req *openrtb2.BidRequest
+
+	BeforeEach(func() {
+		req = &openrtb2.BidRequest{
+			ID: "req",
+			Imp: []openrtb2.Imp{
+				{ID: "1", BidFloor: openrtb2.Float64Ptr(1)},
+				{ID: "2", BidFloor: openrtb2.Float64Ptr(1), PMP: &openrtb2.PMP{
+					Deals: []openrtb2.Deal{
+						{ID: "open-deal", BidFloor: 2, WADomain: []string{"brand.com"}},
+						{ID: "guar-deal", BidFloor: 1.5, Guar: 1, AT: 3, WSeat: []string{"seat-g"}},
+					},
+				}},
+			},
+		}
+	})
+
+	response := func(seat string, group int8, bids ...openrtb2.Bid) *openrtb2.BidResponse {
+		return &openrtb2.BidResponse{
+			ID:      "req",
+			SeatBid: []openrtb2.SeatBid{{Seat: seat, Group: group, Bid: bids}},
+		}
+	}
+
+	reasons := func(res *Result) map[string]openrtb3.LossReason {
+		m := make(map[string]openrtb3.LossReason, len(res.Losers))
+		for _, l := range res.Losers {
+			m[l.Bid.ID] = l.Reason
+		}
+		return m
+	}
+
+	It("should clear second price plus at runner-up price plus increment", func() {
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 3}),
+			response("b", 0, openrtb2.Bid{ID: "b1", ImpID: "1", Price: 2}),
+			response("c", 0, openrtb2.Bid{ID: "c1", ImpID: "1", Price: 0.5}),
+		}, Options{})
+
+		Expect(res.Winners).To(HaveLen(1))
+		Expect(res.Winners[0].Bid.ID).To(Equal("a1"))
+		Expect(res.Winners[0].AT).To(Equal(openrtb3.AuctionType(openrtb3.SecondPricePlus)))
+		Expect(res.Winners[0].Price).To(BeNumerically("~", 2.01, 1e-9))
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"b1": openrtb3.LossLostToHigherBid,
+			"c1": openrtb3.LossBelowAuctionFloor,
+		}))
+	})
+
+	It("should clear single bid at floor plus increment, capped by bid price", func() {
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 1.005}),
+		}, Options{})
+
+		Expect(res.Winners).To(HaveLen(1))
+		Expect(res.Winners[0].Price).To(BeNumerically("~", 1.005, 1e-9))
+	})
+
+	It("should clear first price at bid price", func() {
+		req.AT = 1
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 3}),
+			response("b", 0, openrtb2.Bid{ID: "b1", ImpID: "1", Price: 2}),
+		}, Options{})
+
+		Expect(res.Winners[0].Price).To(Equal(3.0))
+		Expect(res.Winners[0].AT).To(Equal(openrtb3.FirstPrice))
+	})
+
+	It("should apply seat restrictions", func() {
+		req.BSeat = []string{"b"}
+		res := Run(req, []*openrtb2.BidResponse{
+			response("b", 0, openrtb2.Bid{ID: "b1", ImpID: "1", Price: 5}),
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 2}),
+		}, Options{})
+
+		Expect(res.Winners[0].Bid.ID).To(Equal("a1"))
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"b1": openrtb3.LossSeatBlocked,
+		}))
+	})
+
+	It("should give priority to guaranteed deals and clear them at deal price", func() {
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "2", Price: 10}),
+			response("seat-g", 0, openrtb2.Bid{ID: "g1", ImpID: "2", Price: 2, DealID: "guar-deal"}),
+		}, Options{})
+
+		Expect(res.Winners).To(HaveLen(1))
+		Expect(res.Winners[0].Bid.ID).To(Equal("g1"))
+		Expect(res.Winners[0].AT).To(Equal(openrtb3.AuctionType(openrtb3.DealPrice)))
+		Expect(res.Winners[0].Price).To(Equal(1.5))
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"a1": openrtb3.LossLostToDealBid,
+		}))
+	})
+
+	It("should validate deal bids", func() {
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "unknown", ImpID: "2", Price: 10, DealID: "nope"}),
+			response("a", 0, openrtb2.Bid{ID: "seat", ImpID: "2", Price: 10, DealID: "guar-deal"}),
+			response("a", 0, openrtb2.Bid{ID: "adomain", ImpID: "2", Price: 10, DealID: "open-deal", ADomain: []string{"other.com"}}),
+			response("a", 0, openrtb2.Bid{ID: "floor", ImpID: "2", Price: 1.5, DealID: "open-deal", ADomain: []string{"brand.com"}}),
+			response("a", 0, openrtb2.Bid{ID: "ok", ImpID: "2", Price: 2.5, DealID: "open-deal", ADomain: []string{"brand.com"}}),
+		}, Options{})
+
+		Expect(res.Winners).To(HaveLen(1))
+		Expect(res.Winners[0].Bid.ID).To(Equal("ok"))
+		Expect(res.Winners[0].Price).To(BeNumerically("~", 2.01, 1e-9))
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"unknown": openrtb3.LossInvalidDealID,
+			"seat":    openrtb3.LossSeatBlocked,
+			"adomain": openrtb3.LossNotAllowedInDeal,
+			"floor":   openrtb3.LossBelowDealFloor,
+		}))
+	})
+
+	It("should reject open market bids in private auctions", func() {
+		req.Imp[1].PMP.PrivateAuction = 1
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "2", Price: 10}),
+		}, Options{})
+
+		Expect(res.Winners).To(BeEmpty())
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"a1": openrtb3.LossInvalidDealID,
+		}))
+	})
+
+	It("should reject invalid responses", func() {
+		req.Cur = []string{"EUR"}
+		res := Run(req, []*openrtb2.BidResponse{
+			{ID: "other", Cur: "EUR", SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "auction", ImpID: "1", Price: 2}}}}},
+			{ID: "req", Cur: "USD", SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "currency", ImpID: "1", Price: 2}}}}},
+			{ID: "req", Cur: "EUR", SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "imp", ImpID: "3", Price: 2}}}}},
+			{ID: "req", Cur: "EUR", SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "price", ImpID: "1"}}}}},
+		}, Options{})
+
+		Expect(res.Winners).To(BeEmpty())
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"auction":  openrtb3.LossInvalidAuctionID,
+			"currency": openrtb3.LossInvalidResponse,
+			"imp":      openrtb3.LossInvalidResponse,
+			"price":    openrtb3.LossMissingBidPrice,
+		}))
+	})
+
+	It("should win or lose group bids together", func() {
+		res := Run(req, []*openrtb2.BidResponse{
+			response("g", 1,
+				openrtb2.Bid{ID: "g1", ImpID: "1", Price: 5},
+				openrtb2.Bid{ID: "g2", ImpID: "2", Price: 3},
+			),
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 4}),
+			response("a", 0, openrtb2.Bid{ID: "a2", ImpID: "2", Price: 4}),
+		}, Options{})
+
+		Expect(res.Winners).To(HaveLen(2))
+		Expect(res.Winners[0].Bid.ID).To(Equal("a1"))
+		Expect(res.Winners[0].Price).To(BeNumerically("~", 1.01, 1e-9))
+		Expect(res.Winners[1].Bid.ID).To(Equal("a2"))
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"g1": openrtb3.LossLostToHigherBid,
+			"g2": openrtb3.LossLostToHigherBid,
+		}))
+	})
+
+	It("should require road-blocking groups to cover all impressions", func() {
+		req.AllImps = 1
+		res := Run(req, []*openrtb2.BidResponse{
+			response("g", 1, openrtb2.Bid{ID: "g1", ImpID: "1", Price: 5}),
+		}, Options{})
+
+		Expect(res.Winners).To(BeEmpty())
+		Expect(reasons(res)).To(Equal(map[string]openrtb3.LossReason{
+			"g1": openrtb3.LossInvalidResponse,
+		}))
+	})
+
+	It("should use custom pricer for exchange-specific auction types", func() {
+		req.AT = 500
+		res := Run(req, []*openrtb2.BidResponse{
+			response("a", 0, openrtb2.Bid{ID: "a1", ImpID: "1", Price: 3}),
+			response("b", 0, openrtb2.Bid{ID: "b1", ImpID: "1", Price: 2}),
+		}, Options{
+			Pricer: func(at openrtb3.AuctionType, winner, runnerUp *Candidate) float64 {
+				return (winner.Bid.Price + runnerUp.Bid.Price) / 2
+			},
+		})
+
+		Expect(res.Winners[0].Price).To(Equal(2.5))
+	})
+})
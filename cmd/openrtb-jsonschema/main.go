@@ -0,0 +1,87 @@
+// Command openrtb-jsonschema writes JSON Schemas (draft 2020-12) of OpenRTB, AdCOM and Native objects.
+//
+// Usage:
+//
+//	openrtb-jsonschema [-id base-url] -out dir   # writes <object>.schema.json for every root object
+//	openrtb-jsonschema [-id base-url] object     # writes schema of object (e.g., openrtb2.BidRequest) to stdout
+//	openrtb-jsonschema -list                     # lists root objects
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/jsonschema"
+)
+
+func main() {
+	var (
+		out  = flag.String("out", "", "directory to write schemas of all root objects to")
+		id   = flag.String("id", "", "base URL for $id of schemas (e.g., https://example.com/schemas/)")
+		list = flag.Bool("list", false, "list root objects")
+	)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [-id base-url] (-out dir | -list | object)\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	names := make([]string, 0, len(jsonschema.Roots))
+	for name := range jsonschema.Roots {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	switch {
+	case *list:
+		fmt.Println(strings.Join(names, "\n"))
+	case *out != "":
+		if err := os.MkdirAll(*out, 0755); err != nil {
+			fail(err)
+		}
+		for _, name := range names {
+			buf, err := marshal(name, *id)
+			if err != nil {
+				fail(err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(*out, name+".schema.json"), buf, 0644); err != nil {
+				fail(err)
+			}
+		}
+	case flag.NArg() == 1:
+		if _, ok := jsonschema.Roots[flag.Arg(0)]; !ok {
+			fail(fmt.Errorf("unknown object %q, see -list", flag.Arg(0)))
+		}
+		buf, err := marshal(flag.Arg(0), *id)
+		if err != nil {
+			fail(err)
+		}
+		_, _ = os.Stdout.Write(buf)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+func marshal(name, baseURL string) ([]byte, error) {
+	s := jsonschema.For(jsonschema.Roots[name])
+	if baseURL != "" {
+		s.ID = strings.TrimSuffix(baseURL, "/") + "/" + name + ".schema.json"
+	}
+	buf, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, '\n'), nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "openrtb-jsonschema:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,68 @@
+// Command openrtb-trafficgen writes synthetic OpenRTB 2.6 bid requests (and bid responses) as newline-delimited JSON.
+//
+// Usage:
+//
+//	openrtb-trafficgen [-n count] [-seed seed] [-config config.json] [-responses file] > requests.ndjson
+//
+// Config file holds JSON of trafficgen.Config, overriding defaults (see -defaults).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/prebid/openrtb/v20/trafficgen"
+)
+
+func main() {
+	var (
+		n         = flag.Int("n", 1000, "number of bid requests")
+		seed      = flag.Int64("seed", 0, "seed of random generator (overrides config; 0 keeps config)")
+		config    = flag.String("config", "", "JSON config file")
+		responses = flag.String("responses", "", "file to write bid responses to (null for no bid)")
+		defaults  = flag.Bool("defaults", false, "print default config and exit")
+	)
+	flag.Parse()
+
+	cfg := trafficgen.DefaultConfig()
+	if *defaults {
+		buf, _ := json.MarshalIndent(cfg, "", "  ")
+		fmt.Println(string(buf))
+		return
+	}
+	if *config != "" {
+		buf, err := ioutil.ReadFile(*config)
+		if err != nil {
+			fail(err)
+		}
+		if err := json.Unmarshal(buf, &cfg); err != nil {
+			fail(fmt.Errorf("%s: %v", *config, err))
+		}
+	}
+	if *seed != 0 {
+		cfg.Seed = *seed
+	}
+
+	var resp io.Writer
+	if *responses != "" {
+		f, err := os.Create(*responses)
+		if err != nil {
+			fail(err)
+		}
+		defer f.Close()
+		resp = f
+	}
+
+	if err := trafficgen.New(cfg).WriteNDJSON(os.Stdout, resp, *n); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "openrtb-trafficgen:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,113 @@
+package filter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Bundle returns normalized app bundle or store ID:
+// lower-cased, with "id" prefix of numeric iOS store IDs removed (e.g., "1234567" for "id1234567"),
+// and extracted from App Store and Google Play URLs (e.g., "com.foo.app" for "https://play.google.com/store/apps/details?id=com.foo.app").
+func Bundle(s string) string {
+	b := strings.ToLower(strings.TrimSpace(s))
+	if strings.Contains(b, "://") {
+		if u, err := url.Parse(b); err == nil {
+			if id := u.Query().Get("id"); id != "" {
+				b = id
+			} else if i := strings.LastIndexByte(u.Path, '/'); i != -1 {
+				b = u.Path[i+1:]
+			}
+		}
+	}
+	if len(b) > 2 && strings.HasPrefix(b, "id") && isDigits(b[2:]) {
+		b = b[2:]
+	}
+	return b
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Apps matches app bundles of bids against blocked apps by normalized bundle (see Bundle).
+//
+// Bundle IDs and numeric iOS store IDs of the same app match each other, if linked by store IDs (bundle ID to store ID),
+// e.g., {"com.foo.app": "1234567"}, as looked up in App Store.
+type Apps struct {
+	field    string
+	storeIDs map[string]string // normalized
+	blocked  map[string]string // rule by key
+}
+
+// NewApps returns matcher of blocked apps bapp, listed in field (e.g., "bapp").
+func NewApps(field string, bapp []string, storeIDs map[string]string) *Apps {
+	a := &Apps{field: field, storeIDs: make(map[string]string, len(storeIDs)), blocked: make(map[string]string, len(bapp))}
+	for bundle, id := range storeIDs {
+		a.storeIDs[Bundle(bundle)] = Bundle(id)
+	}
+	for _, rule := range bapp {
+		if k := a.key(rule); k != "" {
+			if _, ok := a.blocked[k]; !ok {
+				a.blocked[k] = rule
+			}
+		}
+	}
+	return a
+}
+
+// key returns normalized store ID of bundle, if known, or normalized bundle.
+func (a *Apps) key(bundle string) string {
+	b := Bundle(bundle)
+	if id, ok := a.storeIDs[b]; ok {
+		return id
+	}
+	return b
+}
+
+// RequestApps returns matcher of BApp of OpenRTB 2.x bid request.
+func RequestApps(req *openrtb2.BidRequest, storeIDs map[string]string) *Apps {
+	return NewApps("bapp", req.BApp, storeIDs)
+}
+
+// RestrictionsApps returns matcher of BApp of AdCOM restrictions.
+func RestrictionsApps(r *adcom1.Restrictions, storeIDs map[string]string) *Apps {
+	if r == nil {
+		return NewApps("restrictions.bapp", nil, storeIDs)
+	}
+	return NewApps("restrictions.bapp", r.BApp, storeIDs)
+}
+
+// Match returns rejection (with openrtb3.LossAppBundleExclusions) of app bundles; nil if none is blocked.
+func (a *Apps) Match(bundles []string) *Rejection {
+	if len(a.blocked) == 0 {
+		return nil
+	}
+	for _, value := range bundles {
+		if rule, ok := a.blocked[a.key(value)]; ok {
+			return &Rejection{Reason: openrtb3.LossAppBundleExclusions, Field: a.field, Rule: rule, Value: value}
+		}
+	}
+	return nil
+}
+
+// MatchBid matches Bundle of OpenRTB 2.x bid.
+func (a *Apps) MatchBid(bid *openrtb2.Bid) *Rejection {
+	if bid.Bundle == "" {
+		return nil
+	}
+	return a.Match([]string{bid.Bundle})
+}
+
+// MatchAd matches Bundle of AdCOM ad.
+func (a *Apps) MatchAd(ad *adcom1.Ad) *Rejection {
+	return a.Match(ad.Bundle)
+}
@@ -0,0 +1,54 @@
+package filter_test
+
+import (
+	. "github.com/prebid/openrtb/v20/filter"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable(
+	"Bundle",
+	func(s, expected string) {
+		Expect(Bundle(s)).To(Equal(expected))
+	},
+	Entry("bundle", "com.Foo.App", "com.foo.app"),
+	Entry("store ID", "1234567", "1234567"),
+	Entry("store ID with prefix", "id1234567", "1234567"),
+	Entry("bundle with id prefix", "idle.game", "idle.game"),
+	Entry("App Store URL", "https://apps.apple.com/us/app/foo/id1234567", "1234567"),
+	Entry("Google Play URL", "https://play.google.com/store/apps/details?id=com.foo.app&hl=en", "com.foo.app"),
+)
+
+var _ = Describe("Apps", func() {
+	storeIDs := map[string]string{"com.foo.app": "id1234567"}
+
+	It("should match bundle against store ID", func() {
+		a := RequestApps(&openrtb2.BidRequest{BApp: []string{"1234567"}}, storeIDs)
+		Expect(a.MatchBid(&openrtb2.Bid{Bundle: "com.foo.app"})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAppBundleExclusions, Field: "bapp", Rule: "1234567", Value: "com.foo.app"},
+		))
+		Expect(a.MatchBid(&openrtb2.Bid{Bundle: "com.bar.app"})).To(BeNil())
+		Expect(a.MatchBid(&openrtb2.Bid{})).To(BeNil())
+	})
+
+	It("should match store ID against bundle", func() {
+		a := RequestApps(&openrtb2.BidRequest{BApp: []string{"com.foo.app"}}, storeIDs)
+		Expect(a.MatchBid(&openrtb2.Bid{Bundle: "id1234567"})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAppBundleExclusions, Field: "bapp", Rule: "com.foo.app", Value: "id1234567"},
+		))
+	})
+
+	It("should match without store IDs", func() {
+		a := RestrictionsApps(&adcom1.Restrictions{BApp: []string{"com.foo.app"}}, nil)
+		Expect(a.MatchAd(&adcom1.Ad{Bundle: []string{"com.bar.app", "COM.FOO.APP"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAppBundleExclusions, Field: "restrictions.bapp", Rule: "com.foo.app", Value: "COM.FOO.APP"},
+		))
+		Expect(RestrictionsApps(nil, nil).MatchAd(&adcom1.Ad{Bundle: []string{"com.foo.app"}})).To(BeNil())
+	})
+})
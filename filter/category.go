@@ -0,0 +1,92 @@
+package filter
+
+import (
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/openrtb/v20/taxonomy"
+)
+
+// Categories matches categories of bids against blocked categories.
+//
+// A blocked category blocks its descendants too (e.g., "IAB7" blocks "IAB7-1"), if its taxonomy is registered.
+// Categories of other taxonomies are mapped to the taxonomy of blocked categories with the registry.
+type Categories struct {
+	registry *taxonomy.Registry
+	field    string
+	catTax   adcom1.CategoryTaxonomy
+	bcat     []string
+}
+
+// NewCategories returns matcher of blocked categories bcat of taxonomy catTax, listed in field (e.g., "bcat");
+// nil registry means taxonomy.NewRegistry().
+func NewCategories(registry *taxonomy.Registry, field string, catTax adcom1.CategoryTaxonomy, bcat []string) *Categories {
+	if registry == nil {
+		registry = taxonomy.NewRegistry()
+	}
+	return &Categories{registry: registry, field: field, catTax: catTax, bcat: bcat}
+}
+
+// RequestCategories returns matcher of BCat of OpenRTB 2.x bid request (CatTax defaults to Content Taxonomy 1.0).
+func RequestCategories(registry *taxonomy.Registry, req *openrtb2.BidRequest) *Categories {
+	catTax := req.CatTax
+	if catTax == 0 {
+		catTax = adcom1.CatTaxIABContent10
+	}
+	return NewCategories(registry, "bcat", catTax, req.BCat)
+}
+
+// RestrictionsCategories returns matcher of BCat of AdCOM restrictions (CatTax defaults to Content Taxonomy 2.0).
+func RestrictionsCategories(registry *taxonomy.Registry, r *adcom1.Restrictions) *Categories {
+	if r == nil {
+		return NewCategories(registry, "restrictions.bcat", adcom1.CatTaxIABContent20, nil)
+	}
+	catTax := r.CatTax
+	if catTax == 0 {
+		catTax = adcom1.CatTaxIABContent20
+	}
+	return NewCategories(registry, "restrictions.bcat", catTax, r.BCat)
+}
+
+// Match returns rejection (with openrtb3.LossCategoryExclusions) of categories cat of taxonomy catTax; nil if none is blocked.
+//
+// taxonomy.ErrNoMapping is returned, if categories cannot be mapped to the taxonomy of blocked categories.
+func (c *Categories) Match(catTax adcom1.CategoryTaxonomy, cat []string) (*Rejection, error) {
+	if len(c.bcat) == 0 || len(cat) == 0 {
+		return nil, nil
+	}
+	t := c.registry.Taxonomy(c.catTax)
+
+	for _, value := range cat {
+		mapped, err := c.registry.Map(catTax, c.catTax, []string{value})
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range mapped {
+			for _, rule := range c.bcat {
+				if rule == id || t != nil && t.Contains(rule, id) {
+					return &Rejection{Reason: openrtb3.LossCategoryExclusions, Field: c.field, Rule: rule, Value: value}, nil
+				}
+			}
+		}
+	}
+	return nil, nil
+}
+
+// MatchBid matches Cat of OpenRTB 2.x bid (CatTax defaults to Content Taxonomy 1.0).
+func (c *Categories) MatchBid(bid *openrtb2.Bid) (*Rejection, error) {
+	catTax := bid.CatTax
+	if catTax == 0 {
+		catTax = adcom1.CatTaxIABContent10
+	}
+	return c.Match(catTax, bid.Cat)
+}
+
+// MatchAd matches Cat of AdCOM ad (CatTax defaults to Content Taxonomy 2.0).
+func (c *Categories) MatchAd(ad *adcom1.Ad) (*Rejection, error) {
+	catTax := ad.CatTax
+	if catTax == 0 {
+		catTax = adcom1.CatTaxIABContent20
+	}
+	return c.Match(catTax, ad.Cat)
+}
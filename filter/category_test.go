@@ -0,0 +1,101 @@
+package filter_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/prebid/openrtb/v20/filter"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"github.com/prebid/openrtb/v20/taxonomy"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Categories", func() {
+	var registry *taxonomy.Registry
+
+	BeforeEach(func() {
+		// excerpts of IAB Tech Lab tables and mappings
+		taxonomies, mappings, err := taxonomy.LoadFS(os.DirFS(filepath.Join("..", "taxonomy", "testdata", "sample")))
+		Expect(err).NotTo(HaveOccurred())
+
+		registry = taxonomy.NewRegistry(taxonomies...)
+		for _, m := range mappings {
+			registry.AddMapping(m)
+		}
+	})
+
+	DescribeTable(
+		"of bid request",
+		func(bcat []string, bid openrtb2.Bid, expected *Rejection) {
+			req := &openrtb2.BidRequest{BCat: bcat}
+			Expect(RequestCategories(registry, req).MatchBid(&bid)).To(Equal(expected))
+		},
+		Entry("not blocked", []string{"IAB7"}, openrtb2.Bid{Cat: []string{"IAB17-1"}}, nil),
+		Entry("without categories", []string{"IAB7"}, openrtb2.Bid{}, nil),
+		Entry("blocked exactly", []string{"IAB7"}, openrtb2.Bid{Cat: []string{"IAB17-1", "IAB7"}},
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: "IAB7", Value: "IAB7"}),
+		Entry("blocked by parent", []string{"IAB25", "IAB7"}, openrtb2.Bid{Cat: []string{"IAB7-45"}},
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: "IAB7", Value: "IAB7-45"}),
+		Entry("not blocked by child", []string{"IAB7-1"}, openrtb2.Bid{Cat: []string{"IAB7"}}, nil),
+		Entry("blocked across taxonomies", []string{"IAB7"}, openrtb2.Bid{Cat: []string{"1101"}, CatTax: adcom1.CatTaxIABProduct20},
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: "IAB7", Value: "1101"}),
+		Entry("not blocked across taxonomies", []string{"IAB17"}, openrtb2.Bid{Cat: []string{"1101"}, CatTax: adcom1.CatTaxIABProduct20}, nil),
+	)
+
+	It("should block children of tier 1 category of other taxonomy", func() {
+		req := &openrtb2.BidRequest{BCat: []string{"223"}, CatTax: adcom1.CatTaxIABContent22}
+		Expect(RequestCategories(registry, req).MatchBid(&openrtb2.Bid{Cat: []string{"IAB7-1"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: "223", Value: "IAB7-1"},
+		))
+	})
+
+	It("should match restrictions", func() {
+		r := &adcom1.Restrictions{BCat: []string{"223"}, CatTax: adcom1.CatTaxIABContent22}
+		Expect(RestrictionsCategories(registry, r).MatchAd(&adcom1.Ad{Cat: []string{"225"}, CatTax: adcom1.CatTaxIABContent22})).To(Equal(
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "restrictions.bcat", Rule: "223", Value: "225"},
+		))
+		Expect(RestrictionsCategories(registry, nil).MatchAd(&adcom1.Ad{Cat: []string{"225"}})).To(BeNil())
+	})
+
+	It("should fail without mapping", func() {
+		req := &openrtb2.BidRequest{BCat: []string{"IAB7"}}
+		_, err := RequestCategories(registry, req).MatchBid(&openrtb2.Bid{Cat: []string{"1"}, CatTax: adcom1.CategoryTaxonomy(500)})
+		Expect(err).To(Equal(taxonomy.ErrNoMapping))
+	})
+
+	It("should use embedded taxonomy by default", func() {
+		req := &openrtb2.BidRequest{BCat: []string{"IAB26"}}
+		Expect(RequestCategories(nil, req).MatchBid(&openrtb2.Bid{Cat: []string{"IAB26-3"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: "IAB26", Value: "IAB26-3"},
+		))
+	})
+})
+
+var _ = Describe("Categories of the default registry", func() {
+	It("should block children in Content Taxonomy 1.0", func() {
+		t := taxonomy.NewRegistry().Taxonomy(adcom1.CatTaxIABContent10)
+		Expect(t).NotTo(BeNil())
+
+		blocked := 0
+		for _, c := range t.Categories() {
+			ancestors := t.Ancestors(c.ID)
+			if len(ancestors) == 0 {
+				continue
+			}
+			tier1 := ancestors[len(ancestors)-1]
+
+			req := &openrtb2.BidRequest{BCat: []string{tier1}}
+			Expect(RequestCategories(nil, req).MatchBid(&openrtb2.Bid{Cat: []string{c.ID}})).To(Equal(
+				&Rejection{Reason: openrtb3.LossCategoryExclusions, Field: "bcat", Rule: tier1, Value: c.ID},
+			))
+			blocked++
+		}
+		Expect(blocked).NotTo(BeZero())
+	})
+})
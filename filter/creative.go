@@ -0,0 +1,295 @@
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Check identifies a check of a creative.
+type Check int8
+
+// Check options.
+const (
+	CheckMedia    Check = 1 // media type (display, video, audio) of the ad is offered by the placement
+	CheckSize     Check = 2 // size or ratio of the display ad fits the placement (w, h, displayfmt)
+	CheckMIME     Check = 3 // MIME type of the ad is permitted
+	CheckAPI      Check = 4 // API frameworks, required by the ad, are supported
+	CheckCType    Check = 5 // creative subtype of the ad is permitted
+	CheckAttr     Check = 6 // creative attributes of the ad are not blocked (restrictions.battr)
+	CheckSecure   Check = 7 // the ad is secure, if the placement requires it
+	CheckLang     Check = 8 // language of the ad is permitted (wlang)
+	CheckDuration Check = 9 // duration of video or audio ad is within limits (mindur, maxdur, rqddurs)
+)
+
+// String implements fmt.Stringer.
+func (c Check) String() string {
+	switch c {
+	case CheckMedia:
+		return "media"
+	case CheckSize:
+		return "size"
+	case CheckMIME:
+		return "mime"
+	case CheckAPI:
+		return "api"
+	case CheckCType:
+		return "ctype"
+	case CheckAttr:
+		return "attr"
+	case CheckSecure:
+		return "secure"
+	case CheckLang:
+		return "lang"
+	case CheckDuration:
+		return "duration"
+	}
+	return fmt.Sprintf("Check(%d)", int8(c))
+}
+
+// Failure describes a failed check of a creative (see Compatible and CheckBidSize).
+type Failure struct {
+	Check  Check
+	Reason openrtb3.LossReason // loss reason to be reported to the bidder (e.g., openrtb3.LossSizeNotAllowed)
+	Field  string              // field of the ad (e.g., "display.mime")
+	Value  string              // value of the ad, failing the check (e.g., "image/gif")
+}
+
+// Error implements error.
+func (f *Failure) Error() string {
+	return fmt.Sprintf("filter: %s check failed for %s %q (loss reason %d)", f.Check, f.Field, f.Value, f.Reason)
+}
+
+// Compatible checks, whether AdCOM ad fits placement p, under restrictions r (which may be nil);
+// it returns all failed checks, in order of Check, or nil if the ad fits.
+//
+// Fields, absent from the ad, pass the checks, as do restrictions, absent from the placement.
+func Compatible(ad *adcom1.Ad, p *adcom1.Placement, r *adcom1.Restrictions) []Failure {
+	c := &compat{}
+	switch {
+	case ad.Display != nil:
+		if p.Display == nil {
+			c.fail(CheckMedia, openrtb3.LossIncorrectFormat, "display", "display")
+			break
+		}
+		c.display(ad.Display, p.Display)
+	case ad.Video != nil:
+		if p.Video == nil {
+			c.fail(CheckMedia, openrtb3.LossIncorrectFormat, "video", "video")
+			break
+		}
+		c.video(ad.Video, p.Video)
+	case ad.Audio != nil:
+		if p.Audio == nil {
+			c.fail(CheckMedia, openrtb3.LossIncorrectFormat, "audio", "audio")
+			break
+		}
+		c.audio(ad.Audio, p.Audio)
+	default:
+		c.fail(CheckMedia, openrtb3.LossIncorrectFormat, "", "")
+	}
+
+	if r != nil {
+		for _, attr := range ad.Attr {
+			if containsAttr(r.BAttr, attr) {
+				c.fail(CheckAttr, openrtb3.LossAttributeExclusions, "attr", fmt.Sprint(attr))
+				break
+			}
+		}
+	}
+	if p.Secure == 1 && ad.Secure != 1 {
+		c.fail(CheckSecure, openrtb3.LossNotSecure, "secure", fmt.Sprint(ad.Secure))
+	}
+	if len(p.WLang) != 0 && ad.Lang != "" && !langPermitted(p.WLang, ad.Lang) {
+		c.fail(CheckLang, openrtb3.LossLanguageExclusions, "lang", ad.Lang)
+	}
+
+	sort.SliceStable(c.failures, func(i, j int) bool {
+		return c.failures[i].Check < c.failures[j].Check
+	})
+	return c.failures
+}
+
+// compat collects failures of Compatible.
+type compat struct {
+	failures []Failure
+}
+
+func (c *compat) fail(check Check, reason openrtb3.LossReason, field, value string) {
+	c.failures = append(c.failures, Failure{Check: check, Reason: reason, Field: field, Value: value})
+}
+
+func (c *compat) display(d *adcom1.Display, p *adcom1.DisplayPlacement) {
+	if !displaySizeFits(d, p) {
+		value := fmt.Sprintf("%dx%d", d.W, d.H)
+		if d.W == 0 && d.H == 0 && d.WRatio != nil && d.HRatio != nil {
+			value = fmt.Sprintf("%d:%d", *d.WRatio, *d.HRatio)
+		}
+		c.fail(CheckSize, openrtb3.LossSizeNotAllowed, "display.w", value)
+	}
+	if d.MIME != "" && len(p.MIME) != 0 && !containsFold(p.MIME, d.MIME) {
+		c.fail(CheckMIME, openrtb3.LossIncorrectFormat, "display.mime", d.MIME)
+	}
+	c.api("display.api", d.API, p.API)
+	if d.CType != 0 && len(p.CType) != 0 {
+		permitted := false
+		for _, ct := range p.CType {
+			permitted = permitted || ct == d.CType
+		}
+		if !permitted {
+			c.fail(CheckCType, openrtb3.LossAdTypeExclusions, "display.ctype", fmt.Sprint(d.CType))
+		}
+	}
+}
+
+// media holds fields, common to video and audio ads, or to video and audio placements.
+type media struct {
+	mime    []string
+	api     []adcom1.APIFramework
+	ctype   adcom1.MediaCreativeSubtype   // ad only
+	ctypes  []adcom1.MediaCreativeSubtype // placement only
+	dur     int64                         // ad only
+	minDur  int64                         // placement only
+	maxDur  int64                         // placement only
+	rqdDurs []int64                       // placement only
+}
+
+func (c *compat) video(v *adcom1.Video, p *adcom1.VideoPlacement) {
+	c.media("video",
+		media{mime: v.MIME, api: v.API, ctype: v.CType, dur: v.Dur},
+		media{mime: p.MIME, api: p.API, ctypes: p.CType, minDur: p.MinDur, maxDur: p.MaxDur, rqdDurs: p.RqdDurs},
+	)
+}
+
+func (c *compat) audio(a *adcom1.Audio, p *adcom1.AudioPlacement) {
+	c.media("audio",
+		media{mime: a.MIME, api: a.API, ctype: a.CType, dur: a.Dur},
+		media{mime: p.MIME, api: p.API, ctypes: p.CType, minDur: p.MinDur, maxDur: p.MaxDur, rqdDurs: p.RqdDurs},
+	)
+}
+
+// media checks video or audio ad against placement.
+func (c *compat) media(kind string, ad, p media) {
+	if len(ad.mime) != 0 && len(p.mime) != 0 {
+		permitted := false
+		for _, m := range ad.mime {
+			permitted = permitted || containsFold(p.mime, m)
+		}
+		if !permitted {
+			c.fail(CheckMIME, openrtb3.LossIncorrectFormat, kind+".mime", strings.Join(ad.mime, ","))
+		}
+	}
+	c.api(kind+".api", ad.api, p.api)
+	if ad.ctype != 0 && len(p.ctypes) != 0 {
+		permitted := false
+		for _, ct := range p.ctypes {
+			permitted = permitted || ct == ad.ctype
+		}
+		if !permitted {
+			c.fail(CheckCType, openrtb3.LossAdTypeExclusions, kind+".ctype", fmt.Sprint(ad.ctype))
+		}
+	}
+	if ad.dur != 0 {
+		fits := (p.minDur == 0 || ad.dur >= p.minDur) && (p.maxDur == 0 || ad.dur <= p.maxDur)
+		if len(p.rqdDurs) != 0 {
+			fits = false
+			for _, d := range p.rqdDurs {
+				fits = fits || d == ad.dur
+			}
+		}
+		if !fits {
+			c.fail(CheckDuration, openrtb3.LossIncorrectFormat, kind+".dur", fmt.Sprint(ad.dur))
+		}
+	}
+}
+
+// api checks, that all API frameworks, required by the ad, are supported by the placement (unlisted ones are unsupported).
+func (c *compat) api(field string, api, supported []adcom1.APIFramework) {
+	for _, a := range api {
+		ok := false
+		for _, s := range supported {
+			ok = ok || s == a
+		}
+		if !ok {
+			c.fail(CheckAPI, openrtb3.LossIncorrectFormat, field, fmt.Sprint(a))
+			return
+		}
+	}
+}
+
+// displaySizeFits reports, whether size of display ad is permitted by displayfmt, or matches w and h of the placement:
+// absolute size must be equal, and ratio-only size must be the ratio of w and h.
+func displaySizeFits(d *adcom1.Display, p *adcom1.DisplayPlacement) bool {
+	var wRatio, hRatio int64
+	if d.WRatio != nil && d.HRatio != nil {
+		wRatio, hRatio = int64(*d.WRatio), int64(*d.HRatio)
+	}
+	if d.W == 0 && d.H == 0 && (wRatio == 0 || hRatio == 0) {
+		return true // size unknown
+	}
+
+	if len(p.DisplayFmt) != 0 {
+		for _, f := range p.DisplayFmt {
+			switch {
+			case f.W != 0 && f.H != 0:
+				if d.W == f.W && d.H == f.H {
+					return true
+				}
+			case f.WRatio != 0 && f.HRatio != 0:
+				if d.W != 0 && d.H != 0 && d.W*int64(f.HRatio) == d.H*int64(f.WRatio) {
+					return true
+				}
+				if d.W == 0 && d.H == 0 && wRatio*int64(f.HRatio) == hRatio*int64(f.WRatio) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	if d.W == 0 && d.H == 0 {
+		return p.W == 0 || p.H == 0 || wRatio*p.H == hRatio*p.W
+	}
+	return (p.W == 0 || d.W == p.W) && (p.H == 0 || d.H == p.H)
+}
+
+// langPermitted reports, whether language lang (ISO-639-1-alpha-2 or BCP-47) is permitted by wlang;
+// "xx" (no linguistic content) is always permitted.
+func langPermitted(wlang []string, lang string) bool {
+	primary := func(s string) string {
+		if i := strings.IndexAny(s, "-_"); i != -1 {
+			s = s[:i]
+		}
+		return strings.ToLower(s)
+	}
+	if strings.EqualFold(lang, "xx") || containsFold(wlang, lang) {
+		return true
+	}
+	for _, l := range wlang {
+		if primary(l) == primary(lang) && !strings.ContainsAny(l, "-_") {
+			return true // "en" permits "en-US"
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAttr(list []adcom1.CreativeAttribute, attr adcom1.CreativeAttribute) bool {
+	for _, e := range list {
+		if e == attr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package filter_test
+
+import (
+	. "github.com/prebid/openrtb/v20/filter"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Compatible", func() {
+	displayPlacement := &adcom1.Placement{
+		Secure: 1,
+		WLang:  []string{"en", "de-AT"},
+		Display: &adcom1.DisplayPlacement{
+			MIME:  []string{"image/jpeg", "text/html"},
+			API:   []adcom1.APIFramework{adcom1.APIMRAID20, adcom1.APIOMID10},
+			CType: []adcom1.DisplayCreativeSubtype{adcom1.CreativeHTML, adcom1.CreativeImage},
+			DisplayFmt: []adcom1.DisplayFormat{
+				{W: 300, H: 250},
+				{WRatio: 16, HRatio: 9},
+			},
+		},
+	}
+	videoPlacement := &adcom1.Placement{
+		Video: &adcom1.VideoPlacement{
+			MIME:   []string{"video/mp4"},
+			API:    []adcom1.APIFramework{adcom1.APIOMID10},
+			CType:  []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST40, adcom1.CreativeVAST42},
+			MinDur: 5,
+			MaxDur: 30,
+		},
+	}
+	restrictions := &adcom1.Restrictions{BAttr: []adcom1.CreativeAttribute{adcom1.AttrAudioAuto, adcom1.AttrPop}}
+
+	DescribeTable(
+		"of display ad",
+		func(ad adcom1.Ad, expected []Failure) {
+			Expect(Compatible(&ad, displayPlacement, restrictions)).To(Equal(expected))
+		},
+		Entry("fitting", adcom1.Ad{
+			Secure: 1,
+			Lang:   "en-US",
+			Display: &adcom1.Display{
+				MIME:  "image/jpeg",
+				API:   []adcom1.APIFramework{adcom1.APIOMID10},
+				CType: adcom1.CreativeImage,
+				W:     300,
+				H:     250,
+			},
+		}, nil),
+		Entry("fitting by ratio", adcom1.Ad{Secure: 1, Display: &adcom1.Display{W: 1280, H: 720}}, nil),
+		Entry("fitting ratio", adcom1.Ad{Secure: 1, Display: &adcom1.Display{WRatio: adcom1.Int8Ptr(32), HRatio: adcom1.Int8Ptr(18)}}, nil),
+		Entry("without linguistic content", adcom1.Ad{Secure: 1, Lang: "xx", Display: &adcom1.Display{}}, nil),
+		Entry("size", adcom1.Ad{Secure: 1, Display: &adcom1.Display{W: 728, H: 90}}, []Failure{
+			{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "display.w", Value: "728x90"},
+		}),
+		Entry("ratio", adcom1.Ad{Secure: 1, Display: &adcom1.Display{WRatio: adcom1.Int8Ptr(4), HRatio: adcom1.Int8Ptr(3)}}, []Failure{
+			{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "display.w", Value: "4:3"},
+		}),
+		Entry("all failing", adcom1.Ad{
+			Lang: "fr",
+			Attr: []adcom1.CreativeAttribute{adcom1.AttrProvocative, adcom1.AttrPop},
+			Display: &adcom1.Display{
+				MIME:  "image/gif",
+				API:   []adcom1.APIFramework{adcom1.APIOMID10, adcom1.APIMRAID30},
+				CType: adcom1.CreativeAMP,
+				W:     320,
+				H:     50,
+			},
+		}, []Failure{
+			{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "display.w", Value: "320x50"},
+			{Check: CheckMIME, Reason: openrtb3.LossIncorrectFormat, Field: "display.mime", Value: "image/gif"},
+			{Check: CheckAPI, Reason: openrtb3.LossIncorrectFormat, Field: "display.api", Value: "6"},
+			{Check: CheckCType, Reason: openrtb3.LossAdTypeExclusions, Field: "display.ctype", Value: "2"},
+			{Check: CheckAttr, Reason: openrtb3.LossAttributeExclusions, Field: "attr", Value: "8"},
+			{Check: CheckSecure, Reason: openrtb3.LossNotSecure, Field: "secure", Value: "0"},
+			{Check: CheckLang, Reason: openrtb3.LossLanguageExclusions, Field: "lang", Value: "fr"},
+		}),
+		Entry("region of permitted language", adcom1.Ad{Secure: 1, Lang: "de", Display: &adcom1.Display{}}, []Failure{
+			{Check: CheckLang, Reason: openrtb3.LossLanguageExclusions, Field: "lang", Value: "de"},
+		}),
+		Entry("media", adcom1.Ad{Secure: 1, Video: &adcom1.Video{}}, []Failure{
+			{Check: CheckMedia, Reason: openrtb3.LossIncorrectFormat, Field: "video", Value: "video"},
+		}),
+	)
+
+	DescribeTable(
+		"of video ad",
+		func(ad adcom1.Ad, expected []Failure) {
+			Expect(Compatible(&ad, videoPlacement, nil)).To(Equal(expected))
+		},
+		Entry("fitting", adcom1.Ad{Video: &adcom1.Video{
+			MIME:  []string{"video/webm", "video/mp4"},
+			CType: adcom1.CreativeVAST42,
+			Dur:   15,
+		}}, nil),
+		Entry("duration", adcom1.Ad{Video: &adcom1.Video{Dur: 60}}, []Failure{
+			{Check: CheckDuration, Reason: openrtb3.LossIncorrectFormat, Field: "video.dur", Value: "60"},
+		}),
+		Entry("MIME, API and subtype", adcom1.Ad{Video: &adcom1.Video{
+			MIME:  []string{"video/webm"},
+			API:   []adcom1.APIFramework{adcom1.APIVPAID20},
+			CType: adcom1.CreativeVAST20,
+		}}, []Failure{
+			{Check: CheckMIME, Reason: openrtb3.LossIncorrectFormat, Field: "video.mime", Value: "video/webm"},
+			{Check: CheckAPI, Reason: openrtb3.LossIncorrectFormat, Field: "video.api", Value: "2"},
+			{Check: CheckCType, Reason: openrtb3.LossAdTypeExclusions, Field: "video.ctype", Value: "2"},
+		}),
+		Entry("without media", adcom1.Ad{}, []Failure{
+			{Check: CheckMedia, Reason: openrtb3.LossIncorrectFormat},
+		}),
+	)
+
+	It("should check required durations of audio ad", func() {
+		p := &adcom1.Placement{Audio: &adcom1.AudioPlacement{RqdDurs: []int64{15, 30}}}
+		Expect(Compatible(&adcom1.Ad{Audio: &adcom1.Audio{Dur: 30}}, p, nil)).To(BeNil())
+		Expect(Compatible(&adcom1.Ad{Audio: &adcom1.Audio{Dur: 20}}, p, nil)).To(Equal([]Failure{
+			{Check: CheckDuration, Reason: openrtb3.LossIncorrectFormat, Field: "audio.dur", Value: "20"},
+		}))
+	})
+
+	It("should check exact size against placement", func() {
+		p := &adcom1.Placement{Display: &adcom1.DisplayPlacement{W: 320, H: 480}}
+		Expect(Compatible(&adcom1.Ad{Display: &adcom1.Display{W: 320, H: 480}}, p, nil)).To(BeNil())
+		Expect(Compatible(&adcom1.Ad{Display: &adcom1.Display{W: 320, H: 50}}, p, nil)).To(Equal([]Failure{
+			{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "display.w", Value: "320x50"},
+		}))
+		Expect(Compatible(&adcom1.Ad{Display: &adcom1.Display{W: 480, H: 320}}, p, nil)).To(HaveLen(1))
+	})
+
+	It("should check ratio against placement size", func() {
+		p := &adcom1.Placement{Display: &adcom1.DisplayPlacement{W: 320, H: 480}}
+		Expect(Compatible(&adcom1.Ad{Display: &adcom1.Display{WRatio: adcom1.Int8Ptr(2), HRatio: adcom1.Int8Ptr(3)}}, p, nil)).To(BeNil())
+		Expect(Compatible(&adcom1.Ad{Display: &adcom1.Display{WRatio: adcom1.Int8Ptr(3), HRatio: adcom1.Int8Ptr(2)}}, p, nil)).To(Equal([]Failure{
+			{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "display.w", Value: "3:2"},
+		}))
+	})
+})
@@ -0,0 +1,130 @@
+package filter
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+	"golang.org/x/net/publicsuffix"
+)
+
+// Domain returns registrable domain (eTLD+1, by the public suffix list) of a domain or URL,
+// e.g., "brand.co.uk" for "https://www.brand.co.uk/x"; hosts without one (IP addresses, public suffixes) are returned as is.
+func Domain(s string) string {
+	host := hostname(s)
+	if host == "" || net.ParseIP(host) != nil {
+		return host
+	}
+	if d, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return d
+	}
+	return host
+}
+
+// hostname returns lower-case host name of a domain or URL, e.g., "www.brand.co.uk" for "https://WWW.Brand.co.uk/x".
+func hostname(s string) string {
+	host := strings.ToLower(strings.TrimSpace(s))
+	if i := strings.Index(host, "://"); i != -1 {
+		if u, err := url.Parse(host); err == nil {
+			host = u.Hostname()
+		} else {
+			host = host[i+3:]
+		}
+	}
+	if i := strings.IndexAny(host, "/?#"); i != -1 {
+		host = host[:i]
+	}
+	if i := strings.LastIndexByte(host, '@'); i != -1 {
+		host = host[i+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
+
+// SiteDomain returns registrable domain of Site.Domain, or of Site.Page if domain is absent.
+func SiteDomain(site *openrtb2.Site) string {
+	if site == nil {
+		return ""
+	}
+	if site.Domain != "" {
+		return Domain(site.Domain)
+	}
+	return Domain(site.Page)
+}
+
+// Advertisers matches advertiser domains of bids against blocked advertisers:
+// a domain is blocked by a rule for it or any of its parent domains,
+// e.g., "https://www.brand.co.uk/x" is blocked by "brand.co.uk", but "other.brand.co.uk" is not blocked by "shop.brand.co.uk".
+type Advertisers struct {
+	field   string
+	blocked map[string]string // rule by host name
+}
+
+// NewAdvertisers returns matcher of blocked advertiser domains badv, listed in field (e.g., "badv").
+func NewAdvertisers(field string, badv []string) *Advertisers {
+	a := &Advertisers{field: field, blocked: make(map[string]string, len(badv))}
+	for _, rule := range badv {
+		if h := hostname(rule); h != "" {
+			if _, ok := a.blocked[h]; !ok {
+				a.blocked[h] = rule
+			}
+		}
+	}
+	return a
+}
+
+// RequestAdvertisers returns matcher of BAdv of OpenRTB 2.x bid request.
+func RequestAdvertisers(req *openrtb2.BidRequest) *Advertisers {
+	return NewAdvertisers("badv", req.BAdv)
+}
+
+// RestrictionsAdvertisers returns matcher of BAdv of AdCOM restrictions.
+func RestrictionsAdvertisers(r *adcom1.Restrictions) *Advertisers {
+	if r == nil {
+		return NewAdvertisers("restrictions.badv", nil)
+	}
+	return NewAdvertisers("restrictions.badv", r.BAdv)
+}
+
+// Match returns rejection (with openrtb3.LossAdvertiserExclusions) of advertiser domains adomain; nil if none is blocked.
+func (a *Advertisers) Match(adomain []string) *Rejection {
+	if len(a.blocked) == 0 {
+		return nil
+	}
+	for _, value := range adomain {
+		if rule, ok := a.match(hostname(value)); ok {
+			return &Rejection{Reason: openrtb3.LossAdvertiserExclusions, Field: a.field, Rule: rule, Value: value}
+		}
+	}
+	return nil
+}
+
+// match looks up rule for host or any of its parent domains, splitting on label boundaries.
+func (a *Advertisers) match(host string) (string, bool) {
+	for host != "" {
+		if rule, ok := a.blocked[host]; ok {
+			return rule, true
+		}
+		i := strings.IndexByte(host, '.')
+		if i == -1 || net.ParseIP(host) != nil {
+			break
+		}
+		host = host[i+1:]
+	}
+	return "", false
+}
+
+// MatchBid matches ADomain of OpenRTB 2.x bid.
+func (a *Advertisers) MatchBid(bid *openrtb2.Bid) *Rejection {
+	return a.Match(bid.ADomain)
+}
+
+// MatchAd matches ADomain of AdCOM ad.
+func (a *Advertisers) MatchAd(ad *adcom1.Ad) *Rejection {
+	return a.Match(ad.ADomain)
+}
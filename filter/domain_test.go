@@ -0,0 +1,68 @@
+package filter_test
+
+import (
+	. "github.com/prebid/openrtb/v20/filter"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = DescribeTable(
+	"Domain",
+	func(s, expected string) {
+		Expect(Domain(s)).To(Equal(expected))
+	},
+	Entry("domain", "brand.com", "brand.com"),
+	Entry("subdomain", "www.brand.com", "brand.com"),
+	Entry("multi-label suffix", "shop.brand.co.uk", "brand.co.uk"),
+	Entry("URL", "https://www.brand.co.uk/x?y=z", "brand.co.uk"),
+	Entry("URL with port and user", "http://user@www.Brand.com:8080/", "brand.com"),
+	Entry("URL without scheme", "www.brand.com/path", "brand.com"),
+	Entry("trailing dot", "brand.com.", "brand.com"),
+	Entry("private suffix", "foo.blogspot.com", "foo.blogspot.com"),
+	Entry("public suffix", "co.uk", "co.uk"),
+	Entry("IP", "http://192.0.2.1/x", "192.0.2.1"),
+	Entry("empty", " ", ""),
+)
+
+var _ = DescribeTable(
+	"SiteDomain",
+	func(site *openrtb2.Site, expected string) {
+		Expect(SiteDomain(site)).To(Equal(expected))
+	},
+	Entry("nil", nil, ""),
+	Entry("domain", &openrtb2.Site{Domain: "news.publisher.com", Page: "https://other.com/"}, "publisher.com"),
+	Entry("page", &openrtb2.Site{Page: "https://www.publisher.co.uk/article"}, "publisher.co.uk"),
+)
+
+var _ = Describe("Advertisers", func() {
+	It("should match domain and subdomains", func() {
+		a := RequestAdvertisers(&openrtb2.BidRequest{BAdv: []string{"other.com", "brand.co.uk"}})
+		Expect(a.MatchBid(&openrtb2.Bid{ADomain: []string{"unrelated.com", "https://www.brand.co.uk/x"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAdvertiserExclusions, Field: "badv", Rule: "brand.co.uk", Value: "https://www.brand.co.uk/x"},
+		))
+		Expect(a.MatchBid(&openrtb2.Bid{ADomain: []string{"brand.com", "co.uk", "notbrand.co.uk"}})).To(BeNil())
+		Expect(a.MatchBid(&openrtb2.Bid{})).To(BeNil())
+	})
+
+	It("should keep blocked subdomains as written", func() {
+		a := RequestAdvertisers(&openrtb2.BidRequest{BAdv: []string{"shop.brand.co.uk"}})
+		Expect(a.MatchBid(&openrtb2.Bid{ADomain: []string{"other.brand.co.uk", "brand.co.uk"}})).To(BeNil())
+		Expect(a.MatchBid(&openrtb2.Bid{ADomain: []string{"https://cdn.Shop.Brand.co.uk/x"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAdvertiserExclusions, Field: "badv", Rule: "shop.brand.co.uk", Value: "https://cdn.Shop.Brand.co.uk/x"},
+		))
+	})
+
+	It("should normalize advertiser domains", func() {
+		a := RestrictionsAdvertisers(&adcom1.Restrictions{BAdv: []string{"Brand.com"}})
+		Expect(a.MatchAd(&adcom1.Ad{ADomain: []string{"http://WWW.Brand.COM:8080/"}})).To(Equal(
+			&Rejection{Reason: openrtb3.LossAdvertiserExclusions, Field: "restrictions.badv", Rule: "Brand.com", Value: "http://WWW.Brand.COM:8080/"},
+		))
+		Expect(RestrictionsAdvertisers(nil).MatchAd(&adcom1.Ad{ADomain: []string{"brand.com"}})).To(BeNil())
+	})
+})
@@ -0,0 +1,24 @@
+// Package filter provides creative filtering of bids against restrictions of bid requests,
+// reporting the matched restriction along with the loss reason (openrtb3.LossReason)
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb/blob/main/OpenRTB%20v3.0%20FINAL.md#list_lossreasoncodes
+package filter
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Rejection describes a restriction, a bid is rejected for.
+type Rejection struct {
+	Reason openrtb3.LossReason // loss reason to be reported to the bidder (e.g., openrtb3.LossCategoryExclusions)
+	Field  string              // field of the restriction (e.g., "bcat")
+	Rule   string              // matched entry of the restriction (e.g., "IAB7")
+	Value  string              // value of the bid, matching the rule (e.g., "IAB7-1")
+}
+
+// Error implements error.
+func (r *Rejection) Error() string {
+	return fmt.Sprintf("filter: %q matches %s %q (loss reason %d)", r.Value, r.Field, r.Rule, r.Reason)
+}
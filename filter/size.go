@@ -0,0 +1,184 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Size is a size of a creative: absolute (W and H), relative (WRatio and HRatio), or both.
+type Size struct {
+	W, H           int64
+	WRatio, HRatio int64
+}
+
+// BidSize returns size of OpenRTB 2.x bid.
+func BidSize(bid *openrtb2.Bid) Size {
+	return Size{W: bid.W, H: bid.H, WRatio: bid.WRatio, HRatio: bid.HRatio}
+}
+
+// String implements fmt.Stringer: "300x250", "16:9", or "320x180 (16:9)".
+func (s Size) String() string {
+	switch {
+	case s.absolute() && s.relative():
+		return fmt.Sprintf("%dx%d (%d:%d)", s.W, s.H, s.WRatio, s.HRatio)
+	case s.relative():
+		return fmt.Sprintf("%d:%d", s.WRatio, s.HRatio)
+	}
+	return fmt.Sprintf("%dx%d", s.W, s.H)
+}
+
+func (s Size) absolute() bool {
+	return s.W > 0 && s.H > 0
+}
+
+func (s Size) relative() bool {
+	return s.WRatio > 0 && s.HRatio > 0
+}
+
+// consistent reports, whether absolute size of s (if any) has its ratio (if any).
+func (s Size) consistent() bool {
+	return !s.absolute() || !s.relative() || s.W*s.HRatio == s.H*s.WRatio
+}
+
+// fits reports, whether s is acceptable for format f:
+// absolute sizes must be equal, ratios must be equal, and absolute size must be at least WMin of ratio format.
+func (s Size) fits(f openrtb2.Format) bool {
+	switch {
+	case f.W > 0 && f.H > 0:
+		if s.absolute() {
+			return s.W == f.W && s.H == f.H
+		}
+		return s.relative() && f.W*s.HRatio == f.H*s.WRatio
+	case f.WRatio > 0 && f.HRatio > 0:
+		if s.absolute() {
+			return s.W*f.HRatio == s.H*f.WRatio && s.W >= f.WMin
+		}
+		return s.relative() && s.WRatio*f.HRatio == s.HRatio*f.WRatio
+	}
+	return false
+}
+
+// MatchBanner returns format of banner b, creative size s is acceptable for.
+//
+// Formats (Banner.Format) are matched first, then Banner.W and H,
+// and then deprecated ranges (Banner.WMin, WMax, HMin and HMax) for absolute size.
+func MatchBanner(b *openrtb2.Banner, s Size) (openrtb2.Format, bool) {
+	if !s.consistent() {
+		return openrtb2.Format{}, false
+	}
+	for _, f := range b.Format {
+		if s.fits(f) {
+			return f, true
+		}
+	}
+	if f, ok := bannerSize(b); ok && s.fits(f) {
+		return f, true
+	}
+	if s.absolute() && (b.WMin > 0 || b.WMax > 0 || b.HMin > 0 || b.HMax > 0) &&
+		(b.WMin == 0 || s.W >= b.WMin) && (b.WMax == 0 || s.W <= b.WMax) &&
+		(b.HMin == 0 || s.H >= b.HMin) && (b.HMax == 0 || s.H <= b.HMax) {
+		return openrtb2.Format{W: s.W, H: s.H}, true
+	}
+	return openrtb2.Format{}, false
+}
+
+// bannerSize returns format of Banner.W and H.
+func bannerSize(b *openrtb2.Banner) (openrtb2.Format, bool) {
+	if b.W == nil || b.H == nil || *b.W <= 0 || *b.H <= 0 {
+		return openrtb2.Format{}, false
+	}
+	return openrtb2.Format{W: *b.W, H: *b.H}, true
+}
+
+// MatchImp returns format of banner (Imp.Banner) or companion banner (Imp.Video.CompanionAd) of imp, creative size s is acceptable for.
+func MatchImp(imp *openrtb2.Imp, s Size) (openrtb2.Format, bool) {
+	for _, b := range banners(imp) {
+		if f, ok := MatchBanner(b, s); ok {
+			return f, true
+		}
+	}
+	return openrtb2.Format{}, false
+}
+
+func banners(imp *openrtb2.Imp) []*openrtb2.Banner {
+	var res []*openrtb2.Banner
+	if imp.Banner != nil {
+		res = append(res, imp.Banner)
+	}
+	if imp.Video != nil {
+		for i := range imp.Video.CompanionAd {
+			res = append(res, &imp.Video.CompanionAd[i])
+		}
+	}
+	return res
+}
+
+// BestFit returns format of banner b, a creative of size s without absolute size is displayed in:
+// for ratio size, the first format of the same ratio; for W (or H) only, the first format of the same W (or H);
+// otherwise, Banner.W and H, or the first format (as the primary one, if there are several).
+func BestFit(b *openrtb2.Banner, s Size) (openrtb2.Format, bool) {
+	formats := b.Format
+	if f, ok := bannerSize(b); ok {
+		formats = append(formats[:len(formats):len(formats)], f)
+	}
+
+	for _, f := range formats {
+		switch {
+		case s.relative():
+			if s.fits(f) {
+				return f, true
+			}
+		case s.W > 0:
+			if f.W == s.W && (f.H > 0 || f.HRatio > 0) {
+				return f, true
+			}
+		case s.H > 0:
+			if f.H == s.H && f.W > 0 {
+				return f, true
+			}
+		}
+	}
+	if s.relative() || s.W > 0 || s.H > 0 {
+		return openrtb2.Format{}, false
+	}
+
+	if f, ok := bannerSize(b); ok {
+		return f, true
+	}
+	if len(b.Format) != 0 {
+		return b.Format[0], true
+	}
+	return openrtb2.Format{}, false
+}
+
+// CheckBidSize returns format of imp, bid is displayed in: matching size of the bid, or best fit (see BestFit) for a bid without absolute size.
+// Failure (with openrtb3.LossSizeNotAllowed) is returned for size, inconsistent with its ratio, or not acceptable for imp;
+// imp without banners accepts any size.
+func CheckBidSize(imp *openrtb2.Imp, bid *openrtb2.Bid) (openrtb2.Format, *Failure) {
+	if len(banners(imp)) == 0 {
+		return openrtb2.Format{}, nil
+	}
+	s := BidSize(bid)
+	fail := &Failure{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "w", Value: s.String()}
+	if s.relative() && !s.absolute() {
+		fail.Field = "wratio"
+	}
+
+	switch {
+	case !s.consistent():
+		return openrtb2.Format{}, fail
+	case s.absolute():
+		if f, ok := MatchImp(imp, s); ok {
+			return f, nil
+		}
+		return openrtb2.Format{}, fail
+	}
+	for _, b := range banners(imp) {
+		if f, ok := BestFit(b, s); ok {
+			return f, nil
+		}
+	}
+	return openrtb2.Format{}, fail
+}
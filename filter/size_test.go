@@ -0,0 +1,96 @@
+package filter_test
+
+import (
+	. "github.com/prebid/openrtb/v20/filter"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Size", func() {
+	banner := &openrtb2.Banner{
+		Format: []openrtb2.Format{
+			{W: 300, H: 250},
+			{W: 728, H: 90},
+			{WRatio: 16, HRatio: 9, WMin: 320},
+		},
+	}
+
+	DescribeTable(
+		"MatchBanner",
+		func(s Size, expected openrtb2.Format, ok bool) {
+			f, matched := MatchBanner(banner, s)
+			Expect(matched).To(Equal(ok))
+			Expect(f).To(Equal(expected))
+		},
+		Entry("exact", Size{W: 728, H: 90}, openrtb2.Format{W: 728, H: 90}, true),
+		Entry("ratio format", Size{W: 640, H: 360}, openrtb2.Format{WRatio: 16, HRatio: 9, WMin: 320}, true),
+		Entry("ratio format, below minimum width", Size{W: 160, H: 90}, openrtb2.Format{}, false),
+		Entry("ratio", Size{WRatio: 6, HRatio: 5}, openrtb2.Format{W: 300, H: 250}, true),
+		Entry("ratio with size", Size{W: 1280, H: 720, WRatio: 16, HRatio: 9}, openrtb2.Format{WRatio: 16, HRatio: 9, WMin: 320}, true),
+		Entry("inconsistent ratio", Size{W: 300, H: 250, WRatio: 16, HRatio: 9}, openrtb2.Format{}, false),
+		Entry("not allowed", Size{W: 320, H: 50}, openrtb2.Format{}, false),
+	)
+
+	It("should match Banner.W and H", func() {
+		b := &openrtb2.Banner{W: openrtb2.Int64Ptr(320), H: openrtb2.Int64Ptr(50)}
+		f, ok := MatchBanner(b, Size{W: 320, H: 50})
+		Expect(ok).To(BeTrue())
+		Expect(f).To(Equal(openrtb2.Format{W: 320, H: 50}))
+		_, ok = MatchBanner(b, Size{W: 300, H: 50})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should match deprecated ranges", func() {
+		b := &openrtb2.Banner{WMin: 300, WMax: 320, HMin: 50, HMax: 100}
+		f, ok := MatchBanner(b, Size{W: 310, H: 100})
+		Expect(ok).To(BeTrue())
+		Expect(f).To(Equal(openrtb2.Format{W: 310, H: 100}))
+		_, ok = MatchBanner(b, Size{W: 310, H: 250})
+		Expect(ok).To(BeFalse())
+	})
+
+	DescribeTable(
+		"BestFit",
+		func(b *openrtb2.Banner, s Size, expected openrtb2.Format, ok bool) {
+			f, matched := BestFit(b, s)
+			Expect(matched).To(Equal(ok))
+			Expect(f).To(Equal(expected))
+		},
+		Entry("by ratio", banner, Size{WRatio: 6, HRatio: 5}, openrtb2.Format{W: 300, H: 250}, true),
+		Entry("by width", banner, Size{W: 300}, openrtb2.Format{W: 300, H: 250}, true),
+		Entry("by height", banner, Size{H: 90}, openrtb2.Format{W: 728, H: 90}, true),
+		Entry("first format", banner, Size{}, openrtb2.Format{W: 300, H: 250}, true),
+		Entry("without formats", &openrtb2.Banner{}, Size{}, openrtb2.Format{}, false),
+		Entry("only format", &openrtb2.Banner{Format: []openrtb2.Format{{W: 320, H: 50}}}, Size{}, openrtb2.Format{W: 320, H: 50}, true),
+		Entry("banner size", &openrtb2.Banner{Format: banner.Format, W: openrtb2.Int64Ptr(300), H: openrtb2.Int64Ptr(600)}, Size{}, openrtb2.Format{W: 300, H: 600}, true),
+	)
+
+	DescribeTable(
+		"CheckBidSize",
+		func(imp openrtb2.Imp, bid openrtb2.Bid, expected openrtb2.Format, failure *Failure) {
+			f, fail := CheckBidSize(&imp, &bid)
+			Expect(fail).To(Equal(failure))
+			Expect(f).To(Equal(expected))
+		},
+		Entry("exact", openrtb2.Imp{Banner: banner}, openrtb2.Bid{W: 300, H: 250}, openrtb2.Format{W: 300, H: 250}, nil),
+		Entry("companion", openrtb2.Imp{Video: &openrtb2.Video{CompanionAd: []openrtb2.Banner{
+			{W: openrtb2.Int64Ptr(300), H: openrtb2.Int64Ptr(60)},
+		}}}, openrtb2.Bid{W: 300, H: 60}, openrtb2.Format{W: 300, H: 60}, nil),
+		Entry("best fit", openrtb2.Imp{Banner: banner}, openrtb2.Bid{WRatio: 16, HRatio: 9}, openrtb2.Format{WRatio: 16, HRatio: 9, WMin: 320}, nil),
+		Entry("without banners", openrtb2.Imp{Video: &openrtb2.Video{}}, openrtb2.Bid{W: 1, H: 1}, openrtb2.Format{}, nil),
+		Entry("not allowed", openrtb2.Imp{Banner: banner}, openrtb2.Bid{W: 320, H: 50}, openrtb2.Format{},
+			&Failure{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "w", Value: "320x50"}),
+		Entry("inconsistent", openrtb2.Imp{Banner: banner}, openrtb2.Bid{W: 300, H: 250, WRatio: 16, HRatio: 9}, openrtb2.Format{},
+			&Failure{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "w", Value: "300x250 (16:9)"}),
+		Entry("ratio not allowed", openrtb2.Imp{Banner: banner}, openrtb2.Bid{WRatio: 4, HRatio: 3}, openrtb2.Format{},
+			&Failure{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "wratio", Value: "4:3"}),
+		Entry("without size", openrtb2.Imp{Banner: banner}, openrtb2.Bid{}, openrtb2.Format{W: 300, H: 250}, nil),
+		Entry("without size and formats", openrtb2.Imp{Banner: &openrtb2.Banner{}}, openrtb2.Bid{}, openrtb2.Format{},
+			&Failure{Check: CheckSize, Reason: openrtb3.LossSizeNotAllowed, Field: "w", Value: "0x0"}),
+	)
+})
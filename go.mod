@@ -5,4 +5,5 @@ go 1.16
 require (
 	github.com/onsi/ginkgo v1.16.1
 	github.com/onsi/gomega v1.11.0
+	golang.org/x/net v0.0.0-20201202161906-c7110b5ffcbb
 )
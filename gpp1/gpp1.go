@@ -0,0 +1,202 @@
+// Package gpp1 provides IAB Global Privacy Platform (GPP) 1.0 string decoder
+//
+// It is intended to interpret Regs.GPP and Regs.GPPSID of openrtb2 bid requests.
+//
+// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform
+package gpp1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/internal/bitstr"
+)
+
+// Header type and version, supported by Parse.
+const (
+	HeaderType    = 3
+	HeaderVersion = 1
+)
+
+// Errors, reported by Parse.
+var (
+	ErrEmpty                    = errors.New("gpp1: empty string")
+	ErrUnsupportedHeader        = errors.New("gpp1: unsupported header type or version")
+	ErrSectionCountMismatch     = errors.New("gpp1: number of sections does not match header")
+	ErrUnsupportedSectionFormat = errors.New("gpp1: unsupported section version")
+)
+
+// GPP is a decoded GPP string.
+type GPP struct {
+	// Header of the GPP string.
+	Header Header
+
+	// Sections, in header order.
+	// Sections without typed decoder are represented as Raw.
+	Sections []Section
+}
+
+// Header is a decoded GPP header section.
+type Header struct {
+	// Type of the header (always 3).
+	Type int
+
+	// Version of the header.
+	Version int
+
+	// SectionIDs lists sections included into the string, in order.
+	SectionIDs []SectionID
+}
+
+// Section is a decoded GPP section.
+type Section interface {
+	// SectionID returns GPP section ID.
+	SectionID() SectionID
+}
+
+// USSection is implemented by US sections (national and states) as well as legacy US Privacy (uspv1).
+type USSection interface {
+	Section
+
+	// OptedOut reports whether user opted out of sale of personal data, sharing or targeted advertising.
+	OptedOut() bool
+}
+
+// Raw is an undecoded section.
+type Raw struct {
+	ID    SectionID
+	Value string
+}
+
+// SectionID implements Section.
+func (s *Raw) SectionID() SectionID {
+	return s.ID
+}
+
+// Parse decodes GPP string.
+//
+// Sections are decoded independently: if some of them fail to decode,
+// the rest are still returned (along with first error encountered), while failed ones are represented as Raw.
+func Parse(s string) (*GPP, error) {
+	if s == "" {
+		return nil, ErrEmpty
+	}
+
+	parts := strings.Split(s, "~")
+	header, err := parseHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GPP{Header: *header}
+	if len(parts)-1 != len(header.SectionIDs) {
+		return g, fmt.Errorf("%w: %d sections declared, %d found", ErrSectionCountMismatch, len(header.SectionIDs), len(parts)-1)
+	}
+
+	var firstErr error
+	for i, id := range header.SectionIDs {
+		sec, err := parseSection(id, parts[i+1])
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("gpp1: section %d (%s): %w", id, id, err)
+			}
+			sec = &Raw{ID: id, Value: parts[i+1]}
+		}
+		g.Sections = append(g.Sections, sec)
+	}
+	return g, firstErr
+}
+
+func parseHeader(s string) (*Header, error) {
+	buf, err := bitstr.Decode(s)
+	if err != nil {
+		return nil, fmt.Errorf("gpp1: header: %w", err)
+	}
+	r := bitstr.NewReader(buf)
+
+	h := &Header{
+		Type:    int(r.Int(6)),
+		Version: int(r.Int(6)),
+	}
+	for _, id := range r.FibonacciRange() {
+		h.SectionIDs = append(h.SectionIDs, SectionID(id))
+	}
+	if err := r.Err(); err != nil {
+		return nil, fmt.Errorf("gpp1: header: %w", err)
+	}
+	if h.Type != HeaderType || h.Version != HeaderVersion {
+		return nil, fmt.Errorf("%w: type %d, version %d", ErrUnsupportedHeader, h.Type, h.Version)
+	}
+	return h, nil
+}
+
+func parseSection(id SectionID, s string) (Section, error) {
+	switch id {
+	case SectionIDTCFEUV2:
+		return parseTCFEUV2(s)
+	case SectionIDTCFCAV1:
+		return parseTCFCAV1(s)
+	case SectionIDUSPV1:
+		return parseUSPV1(s)
+	case SectionIDUSNat:
+		return parseUSNat(s)
+	case SectionIDUSCA:
+		return parseUSCA(s)
+	case SectionIDUSVA:
+		return parseUSVA(s)
+	case SectionIDUSCO:
+		return parseUSCO(s)
+	case SectionIDUSUT:
+		return parseUSUT(s)
+	case SectionIDUSCT:
+		return parseUSCT(s)
+	}
+	return &Raw{ID: id, Value: s}, nil
+}
+
+// Section returns section by ID, or nil if it is absent.
+func (g *GPP) Section(id SectionID) Section {
+	for _, s := range g.Sections {
+		if s.SectionID() == id {
+			return s
+		}
+	}
+	return nil
+}
+
+// segments decodes "."-separated base64url segments.
+func segments(s string) ([]*bitstr.Reader, error) {
+	parts := strings.Split(s, ".")
+	rs := make([]*bitstr.Reader, 0, len(parts))
+	for _, p := range parts {
+		buf, err := bitstr.Decode(p)
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, bitstr.NewReader(buf))
+	}
+	return rs, nil
+}
+
+// int2s reads n 2-bit fields.
+func int2s(r *bitstr.Reader, n int) []int8 {
+	v := make([]int8, n)
+	for i := range v {
+		v[i] = int8(r.Int(2))
+	}
+	return v
+}
+
+// gpc reads optional GPC subsection (SubsectionType = 1, Gpc) of US sections.
+func gpc(rs []*bitstr.Reader) (included, value bool, err error) {
+	for _, r := range rs[1:] {
+		if r.Int(2) == 1 {
+			included, value = true, r.Bool()
+		}
+		if err := r.Err(); err != nil {
+			return false, false, err
+		}
+	}
+	return included, value, nil
+}
@@ -0,0 +1,13 @@
+package gpp1_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestGpp1(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Gpp1 Suite")
+}
@@ -0,0 +1,167 @@
+package gpp1_test
+
+import (
+	"encoding/base64"
+
+	. "github.com/prebid/openrtb/v20/gpp1"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// header builds GPP header with given Fibonacci-encoded range entries;
+// each entry is either [id] (offset) or [start, length] (offsets).
+func header(entries ...[]int) string {
+	var bits []bool
+	putInt := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, v>>uint(i)&1 == 1)
+		}
+	}
+	putFib := func(v int) {
+		fib := []int{1, 2}
+		for fib[len(fib)-1] <= v {
+			fib = append(fib, fib[len(fib)-1]+fib[len(fib)-2])
+		}
+		code := make([]bool, len(fib))
+		last := 0
+		for i := len(fib) - 1; i >= 0; i-- {
+			if fib[i] <= v {
+				v -= fib[i]
+				code[i] = true
+				if last == 0 {
+					last = i
+				}
+			}
+		}
+		bits = append(bits, code[:last+1]...)
+		bits = append(bits, true)
+	}
+
+	putInt(3, 6)
+	putInt(1, 6)
+	putInt(len(entries), 12)
+	for _, e := range entries {
+		bits = append(bits, len(e) == 2)
+		for _, v := range e {
+			putFib(v)
+		}
+	}
+
+	buf := make([]byte, (len(bits)+7)/8)
+	for i, b := range bits {
+		if b {
+			buf[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+var _ = Describe("Parse", func() {
+	It("should decode TCF EU v2 and US Privacy sections", func() {
+		g, err := Parse("DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YNN")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(g.Header.SectionIDs).To(Equal([]SectionID{SectionIDTCFEUV2, SectionIDUSPV1}))
+		Expect(g.Sections).To(HaveLen(2))
+
+		tcf, ok := g.Section(SectionIDTCFEUV2).(*TCFEUV2)
+		Expect(ok).To(BeTrue())
+		Expect(tcf.Version).To(Equal(2))
+
+		usp, ok := g.Section(SectionIDUSPV1).(*USPV1)
+		Expect(ok).To(BeTrue())
+		Expect(*usp).To(Equal(USPV1{Version: 1, Notice: 'Y', OptOutSale: 'N', LSPACovered: 'N'}))
+		Expect(usp.OptedOut()).To(BeFalse())
+	})
+
+	It("should decode US national section with GPC subsection", func() {
+		g, err := Parse("DBABLA~BVVqAAEABCA.QA")
+		Expect(err).NotTo(HaveOccurred())
+
+		usnat, ok := g.Section(SectionIDUSNat).(*USNat)
+		Expect(ok).To(BeTrue())
+		Expect(usnat.Version).To(Equal(1))
+		Expect(usnat.SharingNotice).To(Equal(int8(1)))
+		Expect(usnat.SaleOptOut).To(Equal(int8(2)))
+		Expect(usnat.SensitiveDataProcessing).To(HaveLen(12))
+		Expect(usnat.KnownChildSensitiveDataConsents).To(HaveLen(2))
+		Expect(usnat.GPCSegmentIncluded).To(BeTrue())
+		Expect(usnat.GPC).To(BeFalse())
+		Expect(usnat.OptedOut()).To(BeFalse())
+	})
+
+	It("should decode Fibonacci-encoded section ranges", func() {
+		// 2; 6 (2+4); 8..10 (6+2, 8+2)
+		g, err := Parse(header([]int{2}, []int{4}, []int{2, 2}) + "~a~1---~b~c~d")
+		Expect(err).To(HaveOccurred()) // invalid "a" (tcfeuv2)
+		Expect(g.Header.SectionIDs).To(Equal([]SectionID{
+			SectionIDTCFEUV2, SectionIDUSPV1, SectionIDUSCA, SectionIDUSVA, SectionIDUSCO,
+		}))
+		Expect(g.Section(SectionIDTCFEUV2)).To(BeAssignableToTypeOf(&Raw{}))
+		Expect(g.Section(SectionIDUSPV1)).To(BeAssignableToTypeOf(&USPV1{}))
+	})
+
+	It("should reject malformed strings", func() {
+		_, err := Parse("")
+		Expect(err).To(MatchError(ErrEmpty))
+
+		_, err = Parse("DBABLA")
+		Expect(err).To(MatchError(ContainSubstring("number of sections")))
+
+		_, err = Parse("CBABLA~BVVqAAEABCA")
+		Expect(err).To(MatchError(ContainSubstring("unsupported header")))
+	})
+})
+
+var _ = Describe("FromBidRequest", func() {
+	It("should cross-check section IDs", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{
+			GPP:    "DBACNYA~CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA~1YYN",
+			GPPSID: []int8{6, 7},
+		}})
+		Expect(err).To(HaveOccurred())
+
+		mismatch, ok := err.(*SIDMismatchError)
+		Expect(ok).To(BeTrue())
+		Expect(mismatch.Missing).To(Equal([]SectionID{SectionIDUSNat}))
+		Expect(mismatch.Unreferenced).To(Equal([]SectionID{SectionIDTCFEUV2}))
+
+		Expect(s.Applicable()).To(HaveLen(1))
+		Expect(s.OptedOut()).To(BeTrue())
+	})
+
+	It("should cross-check section IDs despite section decoding failure", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{
+			GPP:    "DBABLA~invalid!",
+			GPPSID: []int8{6},
+		}})
+		Expect(err).To(HaveOccurred())
+
+		mismatch, ok := err.(*SIDMismatchError)
+		Expect(ok).To(BeTrue())
+		Expect(mismatch.Missing).To(Equal([]SectionID{SectionIDUSPV1}))
+		Expect(mismatch.Unreferenced).To(Equal([]SectionID{SectionIDUSNat}))
+		Expect(mismatch.Err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("section 7"))
+
+		Expect(s.GPP.Sections).To(Equal([]Section{&Raw{ID: SectionIDUSNat, Value: "invalid!"}}))
+	})
+
+	It("should ignore header and signal integrity sections", func() {
+		g := &GPP{Header: Header{SectionIDs: []SectionID{SectionIDSignalIntegrity, SectionIDUSPV1}}}
+		Expect(g.CheckSID([]int8{6, 3})).To(Succeed())
+	})
+
+	It("should accept consistent section IDs", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{Regs: &openrtb2.Regs{
+			GPP:    "DBABLA~BVVqAAEABCA.QA",
+			GPPSID: []int8{7},
+		}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Applicable()).To(HaveLen(1))
+		Expect(s.OptedOut()).To(BeFalse())
+	})
+})
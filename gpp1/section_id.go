@@ -0,0 +1,67 @@
+package gpp1
+
+import "strconv"
+
+// SectionID defines GPP section ID.
+//
+// https://github.com/InteractiveAdvertisingBureau/Global-Privacy-Platform/blob/main/Sections/Section%20Information.md
+type SectionID int8
+
+// SectionID options.
+const (
+	SectionIDTCFEUV2         SectionID = 2  // EU TCF v2 section (deprecated)
+	SectionIDHeader          SectionID = 3  // GPP Header section
+	SectionIDSignalIntegrity SectionID = 4  // GPP signal integrity section
+	SectionIDTCFCAV1         SectionID = 5  // Canadian TCF section
+	SectionIDUSPV1           SectionID = 6  // USPrivacy String (Unencoded Format)
+	SectionIDUSNat           SectionID = 7  // US - national section
+	SectionIDUSCA            SectionID = 8  // US - California section
+	SectionIDUSVA            SectionID = 9  // US - Virginia section
+	SectionIDUSCO            SectionID = 10 // US - Colorado section
+	SectionIDUSUT            SectionID = 11 // US - Utah section
+	SectionIDUSCT            SectionID = 12 // US - Connecticut section
+	SectionIDUSFL            SectionID = 13 // US - Florida section
+	SectionIDUSMT            SectionID = 14 // US - Montana section
+	SectionIDUSOR            SectionID = 15 // US - Oregon section
+	SectionIDUSTX            SectionID = 16 // US - Texas section
+	SectionIDUSDE            SectionID = 17 // US - Delaware section
+	SectionIDUSIA            SectionID = 18 // US - Iowa section
+	SectionIDUSNE            SectionID = 19 // US - Nebraska section
+	SectionIDUSNH            SectionID = 20 // US - New Hampshire section
+	SectionIDUSNJ            SectionID = 21 // US - New Jersey section
+	SectionIDUSTN            SectionID = 22 // US - Tennessee section
+	SectionIDUSMN            SectionID = 23 // US - Minnesota section
+)
+
+var sectionNames = map[SectionID]string{
+	SectionIDTCFEUV2:         "tcfeuv2",
+	SectionIDHeader:          "header",
+	SectionIDSignalIntegrity: "signalintegrity",
+	SectionIDTCFCAV1:         "tcfcav1",
+	SectionIDUSPV1:           "uspv1",
+	SectionIDUSNat:           "usnat",
+	SectionIDUSCA:            "usca",
+	SectionIDUSVA:            "usva",
+	SectionIDUSCO:            "usco",
+	SectionIDUSUT:            "usut",
+	SectionIDUSCT:            "usct",
+	SectionIDUSFL:            "usfl",
+	SectionIDUSMT:            "usmt",
+	SectionIDUSOR:            "usor",
+	SectionIDUSTX:            "ustx",
+	SectionIDUSDE:            "usde",
+	SectionIDUSIA:            "usia",
+	SectionIDUSNE:            "usne",
+	SectionIDUSNH:            "usnh",
+	SectionIDUSNJ:            "usnj",
+	SectionIDUSTN:            "ustn",
+	SectionIDUSMN:            "usmn",
+}
+
+// String returns API prefix of the section (e.g., "usnat"), or its numeric ID, if unknown.
+func (id SectionID) String() string {
+	if s, ok := sectionNames[id]; ok {
+		return s
+	}
+	return strconv.Itoa(int(id))
+}
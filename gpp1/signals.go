@@ -0,0 +1,155 @@
+package gpp1
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// SIDMismatchError reports discrepancies between GPP header section list and applicable section IDs (Regs.GPPSID).
+//
+// Header (3) and signal integrity (4) sections are never expected to be listed in either, and are ignored.
+type SIDMismatchError struct {
+	// Missing lists sections, referenced by GPPSID, but absent from the GPP string.
+	Missing []SectionID
+
+	// Unreferenced lists sections, present in the GPP string, but not referenced by GPPSID.
+	// This is allowed by the spec, but may indicate misconfiguration.
+	Unreferenced []SectionID
+
+	// Err is the GPP string decoding error, encountered alongside the mismatch (see FromBidRequest); nil, if none.
+	Err error
+}
+
+// Error implements error.
+func (e *SIDMismatchError) Error() string {
+	var parts []string
+	if len(e.Missing) != 0 {
+		parts = append(parts, fmt.Sprintf("missing sections %v", e.Missing))
+	}
+	if len(e.Unreferenced) != 0 {
+		parts = append(parts, fmt.Sprintf("unreferenced sections %v", e.Unreferenced))
+	}
+	msg := "gpp1: gpp_sid mismatch: " + strings.Join(parts, ", ")
+	if e.Err != nil {
+		msg += "; " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the decoding error, encountered alongside the mismatch, if any.
+func (e *SIDMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// CheckSID cross-checks header section list against applicable section IDs (e.g., Regs.GPPSID),
+// returning *SIDMismatchError on any discrepancy.
+func (g *GPP) CheckSID(sid []int8) error {
+	e := new(SIDMismatchError)
+	ids := toSectionIDs(sid)
+	for _, id := range ids {
+		if !nonSection(id) && !containsSectionID(g.Header.SectionIDs, id) {
+			e.Missing = append(e.Missing, id)
+		}
+	}
+	for _, id := range g.Header.SectionIDs {
+		if !nonSection(id) && !containsSectionID(ids, id) {
+			e.Unreferenced = append(e.Unreferenced, id)
+		}
+	}
+	if len(e.Missing) == 0 && len(e.Unreferenced) == 0 {
+		return nil
+	}
+	return e
+}
+
+// nonSection reports, whether id is the header or signal integrity section, which are not listed as sections.
+func nonSection(id SectionID) bool {
+	return id == SectionIDHeader || id == SectionIDSignalIntegrity
+}
+
+// Signals are GPP signals carried by a bid request.
+type Signals struct {
+	// GPP is the decoded Regs.GPP; nil if absent.
+	GPP *GPP
+
+	// SID is Regs.GPPSID.
+	SID []SectionID
+}
+
+// FromBidRequest reads Regs.GPP and Regs.GPPSID of OpenRTB 2.x bid request, cross-checking them.
+//
+// Non-nil Signals are returned even on error, as long as GPP header could be decoded:
+// error may report section decoding failure or *SIDMismatchError.
+// Section IDs are cross-checked even if some sections failed to decode;
+// when both occur, *SIDMismatchError is returned, wrapping the decoding error (see errors.Is).
+func FromBidRequest(req *openrtb2.BidRequest) (*Signals, error) {
+	s := new(Signals)
+	if req.Regs == nil {
+		return s, nil
+	}
+	s.SID = toSectionIDs(req.Regs.GPPSID)
+	if req.Regs.GPP == "" {
+		return s, nil
+	}
+
+	g, err := Parse(req.Regs.GPP)
+	if g == nil {
+		return s, err
+	}
+	s.GPP = g
+	if len(req.Regs.GPPSID) != 0 {
+		if mismatch := g.CheckSID(req.Regs.GPPSID); mismatch != nil {
+			e := mismatch.(*SIDMismatchError)
+			e.Err = err
+			return s, e
+		}
+	}
+	return s, err
+}
+
+// Applicable returns sections, that apply to the transaction: ones referenced by SID or, if SID is empty, all of them.
+func (s *Signals) Applicable() []Section {
+	if s.GPP == nil {
+		return nil
+	}
+	if len(s.SID) == 0 {
+		return s.GPP.Sections
+	}
+
+	var sections []Section
+	for _, sec := range s.GPP.Sections {
+		if containsSectionID(s.SID, sec.SectionID()) {
+			sections = append(sections, sec)
+		}
+	}
+	return sections
+}
+
+// OptedOut reports whether any applicable US section (or legacy US Privacy) signals user opt-out.
+func (s *Signals) OptedOut() bool {
+	for _, sec := range s.Applicable() {
+		if us, ok := sec.(USSection); ok && us.OptedOut() {
+			return true
+		}
+	}
+	return false
+}
+
+func toSectionIDs(sid []int8) []SectionID {
+	ids := make([]SectionID, len(sid))
+	for i, id := range sid {
+		ids[i] = SectionID(id)
+	}
+	return ids
+}
+
+func containsSectionID(list []SectionID, id SectionID) bool {
+	for _, v := range list {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
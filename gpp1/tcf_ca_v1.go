@@ -0,0 +1,165 @@
+package gpp1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prebid/openrtb/v20/internal/bitstr"
+	"github.com/prebid/openrtb/v20/tcf2"
+)
+
+// TCFCAV1 is Canadian TCF v1 section.
+//
+// Publisher restrictions (appended to the core segment by later revisions of the section) are not decoded.
+type TCFCAV1 struct {
+	// Version of the section.
+	Version int
+
+	// Created is the time of string creation (with decisecond precision).
+	Created time.Time
+
+	// LastUpdated is the time of string last update (with decisecond precision).
+	LastUpdated time.Time
+
+	// CMPID is Consent Management Platform ID.
+	CMPID int
+
+	// CMPVersion is Consent Management Platform version.
+	CMPVersion int
+
+	// ConsentScreen is CMP screen number at which consent was given.
+	ConsentScreen int
+
+	// ConsentLanguage is two-letter ISO 639-1 language code (upper case).
+	ConsentLanguage string
+
+	// VendorListVersion is the version of the Global Vendor List used.
+	VendorListVersion int
+
+	// TCFPolicyVersion is the version of policy used.
+	TCFPolicyVersion int
+
+	// UseNonStandardStacks indicates that publisher customized stack descriptions.
+	UseNonStandardStacks bool
+
+	// SpecialFeatureExpressConsent holds express consents for special features.
+	SpecialFeatureExpressConsent tcf2.BitField
+
+	// PurposesExpressConsent holds express consents for purposes.
+	PurposesExpressConsent tcf2.BitField
+
+	// PurposesImpliedConsent holds implied consents for purposes.
+	PurposesImpliedConsent tcf2.BitField
+
+	// VendorExpressConsent lists vendors, which received express consent.
+	VendorExpressConsent []int
+
+	// VendorImpliedConsent lists vendors, which received implied consent.
+	VendorImpliedConsent []int
+
+	// DisclosedVendors lists vendors disclosed to the user; nil if segment is absent.
+	DisclosedVendors []int
+
+	// PubPurposesExpressConsent holds express consents for publisher purposes.
+	PubPurposesExpressConsent tcf2.BitField
+
+	// PubPurposesImpliedConsent holds implied consents for publisher purposes.
+	PubPurposesImpliedConsent tcf2.BitField
+
+	// NumCustomPurposes is the number of custom purposes.
+	NumCustomPurposes int
+
+	// CustomPurposesExpressConsent holds express consents for custom purposes.
+	CustomPurposesExpressConsent tcf2.BitField
+
+	// CustomPurposesImpliedConsent holds implied consents for custom purposes.
+	CustomPurposesImpliedConsent tcf2.BitField
+}
+
+// SectionID implements Section.
+func (s *TCFCAV1) SectionID() SectionID {
+	return SectionIDTCFCAV1
+}
+
+// VendorConsent reports whether vendor received either express or implied consent.
+func (s *TCFCAV1) VendorConsent(id int) bool {
+	return containsInt(s.VendorExpressConsent, id) || containsInt(s.VendorImpliedConsent, id)
+}
+
+// PurposeConsent reports whether purpose n (1-based) received either express or implied consent.
+func (s *TCFCAV1) PurposeConsent(n int) bool {
+	return s.PurposesExpressConsent.Has(n) || s.PurposesImpliedConsent.Has(n)
+}
+
+func parseTCFCAV1(s string) (*TCFCAV1, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	t := &TCFCAV1{Version: int(r.Int(6))}
+	if r.Err() == nil && t.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, t.Version)
+	}
+	t.Created = r.Time()
+	t.LastUpdated = r.Time()
+	t.CMPID = int(r.Int(12))
+	t.CMPVersion = int(r.Int(12))
+	t.ConsentScreen = int(r.Int(6))
+	t.ConsentLanguage = r.Letters(2)
+	t.VendorListVersion = int(r.Int(12))
+	t.TCFPolicyVersion = int(r.Int(6))
+	t.UseNonStandardStacks = r.Bool()
+	t.SpecialFeatureExpressConsent = tcf2.BitField(r.Flags(12))
+	t.PurposesExpressConsent = tcf2.BitField(r.Flags(24))
+	t.PurposesImpliedConsent = tcf2.BitField(r.Flags(24))
+	t.VendorExpressConsent = optimizedFibonacciRange(r)
+	t.VendorImpliedConsent = optimizedFibonacciRange(r)
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, r := range rs[1:] {
+		switch typ := r.Int(3); typ {
+		case 1:
+			t.DisclosedVendors = optimizedFibonacciRange(r)
+		case 3:
+			t.PubPurposesExpressConsent = tcf2.BitField(r.Flags(24))
+			t.PubPurposesImpliedConsent = tcf2.BitField(r.Flags(24))
+			t.NumCustomPurposes = int(r.Int(6))
+			t.CustomPurposesExpressConsent = tcf2.BitField(r.Flags(t.NumCustomPurposes))
+			t.CustomPurposesImpliedConsent = tcf2.BitField(r.Flags(t.NumCustomPurposes))
+		default:
+			return nil, fmt.Errorf("unexpected segment type %d", typ)
+		}
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// optimizedFibonacciRange reads 16-bit max ID, 1-bit encoding type, then either bit field or Fibonacci range.
+func optimizedFibonacciRange(r *bitstr.Reader) []int {
+	max := int(r.Int(16))
+	if r.Bool() {
+		return r.FibonacciRange()
+	}
+	var ids []int
+	for i, set := range r.Bools(max) {
+		if set {
+			ids = append(ids, i+1)
+		}
+	}
+	return ids
+}
+
+func containsInt(list []int, v int) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
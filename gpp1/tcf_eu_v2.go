@@ -0,0 +1,21 @@
+package gpp1
+
+import "github.com/prebid/openrtb/v20/tcf2"
+
+// TCFEUV2 is EU TCF v2 section, which is a regular TC string.
+type TCFEUV2 struct {
+	*tcf2.TCString
+}
+
+// SectionID implements Section.
+func (s *TCFEUV2) SectionID() SectionID {
+	return SectionIDTCFEUV2
+}
+
+func parseTCFEUV2(s string) (*TCFEUV2, error) {
+	tc, err := tcf2.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &TCFEUV2{TCString: tc}, nil
+}
@@ -0,0 +1,70 @@
+package gpp1
+
+import "fmt"
+
+// USCA is US California section.
+//
+// Field values follow USNat conventions.
+type USCA struct {
+	Version                         int
+	SaleOptOutNotice                int8
+	SharingOptOutNotice             int8
+	SensitiveDataLimitUseNotice     int8
+	SaleOptOut                      int8
+	SharingOptOut                   int8
+	SensitiveDataProcessing         []int8 // 9 entries
+	KnownChildSensitiveDataConsents []int8 // 2 entries
+	PersonalDataConsents            int8
+	MSPACoveredTransaction          int8
+	MSPAOptOutOptionMode            int8
+	MSPAServiceProviderMode         int8
+
+	// GPCSegmentIncluded indicates whether optional GPC subsection is present.
+	GPCSegmentIncluded bool
+
+	// GPC is the Global Privacy Control signal.
+	GPC bool
+}
+
+// SectionID implements Section.
+func (s *USCA) SectionID() SectionID {
+	return SectionIDUSCA
+}
+
+// OptedOut implements USSection.
+func (s *USCA) OptedOut() bool {
+	return s.SaleOptOut == 1 || s.SharingOptOut == 1
+}
+
+func parseUSCA(s string) (*USCA, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	u := &USCA{Version: int(r.Int(6))}
+	if r.Err() == nil && u.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, u.Version)
+	}
+
+	u.SaleOptOutNotice = int8(r.Int(2))
+	u.SharingOptOutNotice = int8(r.Int(2))
+	u.SensitiveDataLimitUseNotice = int8(r.Int(2))
+	u.SaleOptOut = int8(r.Int(2))
+	u.SharingOptOut = int8(r.Int(2))
+	u.SensitiveDataProcessing = int2s(r, 9)
+	u.KnownChildSensitiveDataConsents = int2s(r, 2)
+	u.PersonalDataConsents = int8(r.Int(2))
+	u.MSPACoveredTransaction = int8(r.Int(2))
+	u.MSPAOptOutOptionMode = int8(r.Int(2))
+	u.MSPAServiceProviderMode = int8(r.Int(2))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	if u.GPCSegmentIncluded, u.GPC, err = gpc(rs); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
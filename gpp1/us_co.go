@@ -0,0 +1,68 @@
+package gpp1
+
+import "fmt"
+
+// USCO is US Colorado section.
+//
+// Field values follow USNat conventions.
+type USCO struct {
+	Version                         int
+	SharingNotice                   int8
+	SaleOptOutNotice                int8
+	TargetedAdvertisingOptOutNotice int8
+	SaleOptOut                      int8
+	TargetedAdvertisingOptOut       int8
+	SensitiveDataProcessing         []int8 // 7 entries
+	KnownChildSensitiveDataConsents int8
+	MSPACoveredTransaction          int8
+	MSPAOptOutOptionMode            int8
+	MSPAServiceProviderMode         int8
+
+	// GPCSegmentIncluded indicates whether optional GPC subsection is present.
+	GPCSegmentIncluded bool
+
+	// GPC is the Global Privacy Control signal.
+	GPC bool
+}
+
+// SectionID implements Section.
+func (s *USCO) SectionID() SectionID {
+	return SectionIDUSCO
+}
+
+// OptedOut implements USSection.
+func (s *USCO) OptedOut() bool {
+	return s.SaleOptOut == 1 || s.TargetedAdvertisingOptOut == 1
+}
+
+func parseUSCO(s string) (*USCO, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	u := &USCO{Version: int(r.Int(6))}
+	if r.Err() == nil && u.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, u.Version)
+	}
+
+	u.SharingNotice = int8(r.Int(2))
+	u.SaleOptOutNotice = int8(r.Int(2))
+	u.TargetedAdvertisingOptOutNotice = int8(r.Int(2))
+	u.SaleOptOut = int8(r.Int(2))
+	u.TargetedAdvertisingOptOut = int8(r.Int(2))
+	u.SensitiveDataProcessing = int2s(r, 7)
+	u.KnownChildSensitiveDataConsents = int8(r.Int(2))
+	u.MSPACoveredTransaction = int8(r.Int(2))
+	u.MSPAOptOutOptionMode = int8(r.Int(2))
+	u.MSPAServiceProviderMode = int8(r.Int(2))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	if u.GPCSegmentIncluded, u.GPC, err = gpc(rs); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
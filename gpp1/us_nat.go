@@ -0,0 +1,89 @@
+package gpp1
+
+import "fmt"
+
+// USNat is US national section.
+//
+// Notice fields: 0 = not applicable, 1 = notice provided, 2 = notice not provided.
+// Opt-out fields: 0 = not applicable, 1 = opted out, 2 = did not opt out.
+// Consent fields: 0 = not applicable, 1 = no consent, 2 = consent.
+// MSPA fields: 0 = not applicable (MSPACoveredTransaction only allows 1 and 2), 1 = yes, 2 = no.
+type USNat struct {
+	Version                             int
+	SharingNotice                       int8
+	SaleOptOutNotice                    int8
+	SharingOptOutNotice                 int8
+	TargetedAdvertisingOptOutNotice     int8
+	SensitiveDataProcessingOptOutNotice int8
+	SensitiveDataLimitUseNotice         int8
+	SaleOptOut                          int8
+	SharingOptOut                       int8
+	TargetedAdvertisingOptOut           int8
+	SensitiveDataProcessing             []int8 // 12 entries in version 1, 16 in version 2
+	KnownChildSensitiveDataConsents     []int8 // 2 entries in version 1, 3 in version 2
+	PersonalDataConsents                int8
+	MSPACoveredTransaction              int8
+	MSPAOptOutOptionMode                int8
+	MSPAServiceProviderMode             int8
+
+	// GPCSegmentIncluded indicates whether optional GPC subsection is present.
+	GPCSegmentIncluded bool
+
+	// GPC is the Global Privacy Control signal.
+	GPC bool
+}
+
+// SectionID implements Section.
+func (s *USNat) SectionID() SectionID {
+	return SectionIDUSNat
+}
+
+// OptedOut implements USSection.
+func (s *USNat) OptedOut() bool {
+	return s.SaleOptOut == 1 || s.SharingOptOut == 1 || s.TargetedAdvertisingOptOut == 1
+}
+
+func parseUSNat(s string) (*USNat, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	u := &USNat{Version: int(r.Int(6))}
+
+	sensitive, child := 12, 2
+	switch u.Version {
+	case 1:
+	case 2:
+		sensitive, child = 16, 3
+	default:
+		if r.Err() == nil {
+			return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, u.Version)
+		}
+	}
+
+	u.SharingNotice = int8(r.Int(2))
+	u.SaleOptOutNotice = int8(r.Int(2))
+	u.SharingOptOutNotice = int8(r.Int(2))
+	u.TargetedAdvertisingOptOutNotice = int8(r.Int(2))
+	u.SensitiveDataProcessingOptOutNotice = int8(r.Int(2))
+	u.SensitiveDataLimitUseNotice = int8(r.Int(2))
+	u.SaleOptOut = int8(r.Int(2))
+	u.SharingOptOut = int8(r.Int(2))
+	u.TargetedAdvertisingOptOut = int8(r.Int(2))
+	u.SensitiveDataProcessing = int2s(r, sensitive)
+	u.KnownChildSensitiveDataConsents = int2s(r, child)
+	u.PersonalDataConsents = int8(r.Int(2))
+	u.MSPACoveredTransaction = int8(r.Int(2))
+	u.MSPAOptOutOptionMode = int8(r.Int(2))
+	u.MSPAServiceProviderMode = int8(r.Int(2))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+
+	if u.GPCSegmentIncluded, u.GPC, err = gpc(rs); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
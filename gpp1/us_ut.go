@@ -0,0 +1,60 @@
+package gpp1
+
+import "fmt"
+
+// USUT is US Utah section.
+//
+// Field values follow USNat conventions.
+type USUT struct {
+	Version                             int
+	SharingNotice                       int8
+	SaleOptOutNotice                    int8
+	TargetedAdvertisingOptOutNotice     int8
+	SensitiveDataProcessingOptOutNotice int8
+	SaleOptOut                          int8
+	TargetedAdvertisingOptOut           int8
+	SensitiveDataProcessing             []int8 // 8 entries
+	KnownChildSensitiveDataConsents     int8
+	MSPACoveredTransaction              int8
+	MSPAOptOutOptionMode                int8
+	MSPAServiceProviderMode             int8
+}
+
+// SectionID implements Section.
+func (s *USUT) SectionID() SectionID {
+	return SectionIDUSUT
+}
+
+// OptedOut implements USSection.
+func (s *USUT) OptedOut() bool {
+	return s.SaleOptOut == 1 || s.TargetedAdvertisingOptOut == 1
+}
+
+func parseUSUT(s string) (*USUT, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	u := &USUT{Version: int(r.Int(6))}
+	if r.Err() == nil && u.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, u.Version)
+	}
+
+	u.SharingNotice = int8(r.Int(2))
+	u.SaleOptOutNotice = int8(r.Int(2))
+	u.TargetedAdvertisingOptOutNotice = int8(r.Int(2))
+	u.SensitiveDataProcessingOptOutNotice = int8(r.Int(2))
+	u.SaleOptOut = int8(r.Int(2))
+	u.TargetedAdvertisingOptOut = int8(r.Int(2))
+	u.SensitiveDataProcessing = int2s(r, 8)
+	u.KnownChildSensitiveDataConsents = int8(r.Int(2))
+	u.MSPACoveredTransaction = int8(r.Int(2))
+	u.MSPAOptOutOptionMode = int8(r.Int(2))
+	u.MSPAServiceProviderMode = int8(r.Int(2))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
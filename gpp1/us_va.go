@@ -0,0 +1,58 @@
+package gpp1
+
+import "fmt"
+
+// USVA is US Virginia section.
+//
+// Field values follow USNat conventions.
+type USVA struct {
+	Version                         int
+	SharingNotice                   int8
+	SaleOptOutNotice                int8
+	TargetedAdvertisingOptOutNotice int8
+	SaleOptOut                      int8
+	TargetedAdvertisingOptOut       int8
+	SensitiveDataProcessing         []int8 // 8 entries
+	KnownChildSensitiveDataConsents int8
+	MSPACoveredTransaction          int8
+	MSPAOptOutOptionMode            int8
+	MSPAServiceProviderMode         int8
+}
+
+// SectionID implements Section.
+func (s *USVA) SectionID() SectionID {
+	return SectionIDUSVA
+}
+
+// OptedOut implements USSection.
+func (s *USVA) OptedOut() bool {
+	return s.SaleOptOut == 1 || s.TargetedAdvertisingOptOut == 1
+}
+
+func parseUSVA(s string) (*USVA, error) {
+	rs, err := segments(s)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rs[0]
+	u := &USVA{Version: int(r.Int(6))}
+	if r.Err() == nil && u.Version != 1 {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedSectionFormat, u.Version)
+	}
+
+	u.SharingNotice = int8(r.Int(2))
+	u.SaleOptOutNotice = int8(r.Int(2))
+	u.TargetedAdvertisingOptOutNotice = int8(r.Int(2))
+	u.SaleOptOut = int8(r.Int(2))
+	u.TargetedAdvertisingOptOut = int8(r.Int(2))
+	u.SensitiveDataProcessing = int2s(r, 8)
+	u.KnownChildSensitiveDataConsents = int8(r.Int(2))
+	u.MSPACoveredTransaction = int8(r.Int(2))
+	u.MSPAOptOutOptionMode = int8(r.Int(2))
+	u.MSPAServiceProviderMode = int8(r.Int(2))
+	if err := r.Err(); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
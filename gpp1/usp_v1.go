@@ -0,0 +1,59 @@
+package gpp1
+
+import "fmt"
+
+// USPV1 is legacy US Privacy string (CCPA), e.g. "1YNN".
+//
+// https://github.com/InteractiveAdvertisingBureau/USPrivacy/blob/master/CCPA/US%20Privacy%20String.md
+type USPV1 struct {
+	// Version of the string.
+	Version int
+
+	// Notice indicates whether explicit notice and opportunity to opt out was provided: 'Y', 'N' or '-' (not applicable).
+	Notice byte
+
+	// OptOutSale indicates whether user opted out of sale: 'Y', 'N' or '-' (not applicable).
+	OptOutSale byte
+
+	// LSPACovered indicates whether publisher is a signatory to the IAB Limited Service Provider Agreement: 'Y', 'N' or '-' (not applicable).
+	LSPACovered byte
+}
+
+// SectionID implements Section.
+func (s *USPV1) SectionID() SectionID {
+	return SectionIDUSPV1
+}
+
+// OptedOut implements USSection.
+func (s *USPV1) OptedOut() bool {
+	return s.OptOutSale == 'Y'
+}
+
+// ParseUSPV1 decodes US Privacy string, as found in Regs.USPrivacy.
+func ParseUSPV1(s string) (*USPV1, error) {
+	return parseUSPV1(s)
+}
+
+func parseUSPV1(s string) (*USPV1, error) {
+	if len(s) != 4 || s[0] != '1' {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedSectionFormat, s)
+	}
+	for _, c := range []byte(s[1:]) {
+		if c != 'Y' && c != 'N' && c != '-' && c != 'y' && c != 'n' {
+			return nil, fmt.Errorf("invalid US Privacy string: %q", s)
+		}
+	}
+	return &USPV1{
+		Version:     1,
+		Notice:      upper(s[1]),
+		OptOutSale:  upper(s[2]),
+		LSPACovered: upper(s[3]),
+	}, nil
+}
+
+func upper(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
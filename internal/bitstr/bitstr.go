@@ -0,0 +1,174 @@
+// Package bitstr provides bit-level reading of base64url-encoded consent strings (TCF, GPP)
+package bitstr
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Errors, reported by Reader.
+var (
+	ErrUnexpectedEnd = errors.New("unexpected end of data")
+	ErrRangeTooLarge = errors.New("range too large")
+)
+
+// MaxRangeIDs limits the number of IDs, that can be produced by a single FibonacciRange.
+const MaxRangeIDs = 1 << 16
+
+// Decode decodes base64url (with or without padding) encoded segment.
+// Standard alphabet is tolerated as well, since it is frequently produced by non-compliant CMPs.
+func Decode(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+// Reader reads big-endian bit fields.
+//
+// Errors are sticky: once data is exhausted, all subsequent reads return zero values and Err reports ErrUnexpectedEnd.
+type Reader struct {
+	buf []byte
+	pos int
+	err error
+}
+
+// NewReader creates Reader over buf.
+func NewReader(buf []byte) *Reader {
+	return &Reader{buf: buf}
+}
+
+// Err returns the first error encountered.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// Pos returns the number of bits read so far.
+func (r *Reader) Pos() int {
+	return r.pos
+}
+
+// Len returns the number of unread bits.
+func (r *Reader) Len() int {
+	return len(r.buf)*8 - r.pos
+}
+
+// Int reads n-bit (n <= 64) unsigned integer.
+func (r *Reader) Int(n int) uint64 {
+	if r.err != nil {
+		return 0
+	}
+	if n > r.Len() {
+		r.err = ErrUnexpectedEnd
+		r.pos = len(r.buf) * 8
+		return 0
+	}
+
+	var v uint64
+	for i := 0; i < n; i++ {
+		b := r.buf[r.pos/8] >> (7 - uint(r.pos%8)) & 1
+		v = v<<1 | uint64(b)
+		r.pos++
+	}
+	return v
+}
+
+// Bool reads a single bit flag.
+func (r *Reader) Bool() bool {
+	return r.Int(1) == 1
+}
+
+// Bools reads n single bit flags.
+func (r *Reader) Bools(n int) []bool {
+	if n > r.Len() {
+		r.Int(n) // sets error
+		return nil
+	}
+	v := make([]bool, n)
+	for i := range v {
+		v[i] = r.Bool()
+	}
+	return v
+}
+
+// Letters reads n 6-bit letters, where 0 = "A", 1 = "B" etc.
+func (r *Reader) Letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = 'A' + byte(r.Int(6))
+	}
+	if r.err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// Fibonacci reads Fibonacci-encoded (Zeckendorf representation, least significant first, terminated by "11") integer.
+func (r *Reader) Fibonacci() uint64 {
+	var (
+		v       uint64
+		a, b    uint64 = 1, 2
+		prevSet bool
+	)
+	for r.err == nil {
+		set := r.Bool()
+		if set && prevSet {
+			return v
+		}
+		if set {
+			v += a
+		}
+		prevSet = set
+		a, b = b, a+b
+	}
+	return 0
+}
+
+// FibonacciRange reads a list of IDs, encoded as a 12-bit number of entries,
+// each being either a single ID or a range of IDs, where IDs are Fibonacci-encoded offsets from the previous entry.
+func (r *Reader) FibonacciRange() []int {
+	var (
+		ids  []int
+		last int
+	)
+	n := int(r.Int(12))
+	for i := 0; i < n && r.err == nil; i++ {
+		if r.Bool() {
+			start := last + int(r.Fibonacci())
+			end := start + int(r.Fibonacci())
+			if end-start+len(ids) > MaxRangeIDs {
+				r.err = ErrRangeTooLarge
+				break
+			}
+			for id := start; id <= end && r.err == nil; id++ {
+				ids = append(ids, id)
+			}
+			last = end
+		} else {
+			last += int(r.Fibonacci())
+			ids = append(ids, last)
+		}
+	}
+	if r.err != nil {
+		return nil
+	}
+	return ids
+}
+
+// Flags reads n (n <= 64) single bit flags into a bit mask, where the first flag read is the least significant bit.
+func (r *Reader) Flags(n int) uint64 {
+	var f uint64
+	for i := 0; i < n; i++ {
+		if r.Bool() && i < 64 {
+			f |= 1 << uint(i)
+		}
+	}
+	return f
+}
+
+// Time reads 36-bit timestamp in deciseconds since the epoch.
+func (r *Reader) Time() time.Time {
+	ds := r.Int(36)
+	return time.Unix(int64(ds/10), int64(ds%10)*int64(100*time.Millisecond)).UTC()
+}
@@ -0,0 +1,218 @@
+// Package jsonfields provides fields of struct types, as encoding/json sees them
+//
+// Untagged embedded structs (and pointers to structs) are inlined, and conflicting names are resolved
+// by the rules of encoding/json: the least nested field wins, then the one with JSON tag;
+// other conflicting fields are ignored.
+package jsonfields
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field is a struct field, encoded by encoding/json.
+type Field struct {
+	Name      string       // JSON name
+	Index     []int        // index sequence, promoted fields have more than one
+	Type      reflect.Type // type of the struct field
+	Tagged    bool         // Name is given by JSON tag
+	OmitEmpty bool         // omitempty option
+}
+
+// Value returns the field of struct v; ok is false if the field is promoted through a nil embedded pointer.
+// If alloc is set, nil embedded pointers are allocated instead (v must be addressable).
+func (f *Field) Value(v reflect.Value, alloc bool) (_ reflect.Value, ok bool) {
+	for i, x := range f.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// IsEmpty reports whether v is empty, as defined by encoding/json for omitempty.
+func IsEmpty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// Fields are fields of a struct type.
+type Fields struct {
+	List   []Field           // in the order of encoding/json
+	ByName map[string]*Field // by exact JSON name
+}
+
+var cache sync.Map // map[reflect.Type]*Fields
+
+// Of returns fields of struct type t; results are cached.
+func Of(t reflect.Type) *Fields {
+	if f, ok := cache.Load(t); ok {
+		return f.(*Fields)
+	}
+
+	fs := &Fields{List: collect(t), ByName: make(map[string]*Field)}
+	for i := range fs.List {
+		fs.ByName[fs.List[i].Name] = &fs.List[i]
+	}
+	f, _ := cache.LoadOrStore(t, fs)
+	return f.(*Fields)
+}
+
+// collect returns fields of t, walking embedded structs breadth-first, as encoding/json does.
+func collect(t reflect.Type) []Field {
+	var (
+		fields           []Field
+		next             = []Field{{Type: t}}
+		count, nextCount map[reflect.Type]int // of structs, embedded at the current and the next depth
+		visited          = make(map[reflect.Type]bool)
+	)
+	for len(next) != 0 {
+		current := next
+		next = nil
+		count, nextCount = nextCount, make(map[reflect.Type]int)
+
+		for _, f := range current {
+			if visited[f.Type] {
+				continue
+			}
+			visited[f.Type] = true
+
+			for i := 0; i < f.Type.NumField(); i++ {
+				sf := f.Type.Field(i)
+				if sf.Anonymous {
+					ft := sf.Type
+					if ft.Kind() == reflect.Ptr {
+						ft = ft.Elem()
+					}
+					if sf.PkgPath != "" && ft.Kind() != reflect.Struct {
+						continue // embedded unexported non-struct
+					}
+				} else if sf.PkgPath != "" {
+					continue // unexported
+				}
+
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := tag, ""
+				if i := strings.IndexByte(tag, ','); i != -1 {
+					name, opts = tag[:i], tag[i+1:]
+				}
+				if !validName(name) {
+					name = ""
+				}
+
+				index := make([]int, len(f.Index)+1)
+				copy(index, f.Index)
+				index[len(f.Index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					field := Field{
+						Name:      name,
+						Index:     index,
+						Type:      sf.Type,
+						Tagged:    name != "",
+						OmitEmpty: strings.Contains(","+opts+",", ",omitempty,"),
+					}
+					if field.Name == "" {
+						field.Name = sf.Name
+					}
+					fields = append(fields, field)
+					if count[f.Type] > 1 {
+						// the struct is embedded more than once at this depth, so are its fields
+						fields = append(fields, field)
+					}
+					continue
+				}
+
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, Field{Name: ft.Name(), Index: index, Type: ft})
+				}
+			}
+		}
+	}
+
+	sort.Slice(fields, func(i, j int) bool {
+		x, y := &fields[i], &fields[j]
+		if x.Name != y.Name {
+			return x.Name < y.Name
+		}
+		if len(x.Index) != len(y.Index) {
+			return len(x.Index) < len(y.Index)
+		}
+		if x.Tagged != y.Tagged {
+			return x.Tagged
+		}
+		return lessIndex(x.Index, y.Index)
+	})
+
+	out := fields[:0]
+	for i, n := 0, 0; i < len(fields); i += n {
+		for n = 1; i+n < len(fields) && fields[i+n].Name == fields[i].Name; n++ {
+		}
+		// fields are sorted by depth and tag, so the first one dominates unless it ties with the second
+		if n > 1 && len(fields[i].Index) == len(fields[i+1].Index) && fields[i].Tagged == fields[i+1].Tagged {
+			continue
+		}
+		out = append(out, fields[i])
+	}
+
+	sort.Slice(out, func(i, j int) bool { return lessIndex(out[i].Index, out[j].Index) })
+	return out
+}
+
+func lessIndex(x, y []int) bool {
+	for k, xk := range x {
+		if k >= len(y) {
+			return false
+		}
+		if xk != y[k] {
+			return xk < y[k]
+		}
+	}
+	return len(x) < len(y)
+}
+
+// validName reports whether JSON tag name s is used by encoding/json (otherwise, Go field name is).
+func validName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
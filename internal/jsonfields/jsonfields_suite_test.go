@@ -0,0 +1,13 @@
+package jsonfields_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestJsonfields(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jsonfields Suite")
+}
@@ -0,0 +1,148 @@
+package jsonfields_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	. "github.com/prebid/openrtb/v20/internal/jsonfields"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+type (
+	Inner struct {
+		A int
+		B int
+	}
+	Other struct {
+		C int `json:"c"`
+	}
+	Left struct {
+		A int
+		B int `json:"B"`
+	}
+	Right struct {
+		A int
+		B int
+	}
+	LeftInner  struct{ Inner }
+	RightInner struct{ Inner }
+	Recursive  struct {
+		*Recursive
+		N int
+	}
+	MyInt int
+	inner struct {
+		X int
+	}
+	myInt int
+)
+
+// names returns names of fields, in the order of encoding/json.
+func names(t reflect.Type) []string {
+	ns := []string{}
+	for _, f := range Of(t).List {
+		ns = append(ns, f.Name)
+	}
+	return ns
+}
+
+// keys returns keys of JSON object, in order.
+func keys(v interface{}) []string {
+	data, err := json.Marshal(v)
+	Expect(err).NotTo(HaveOccurred())
+
+	ks := []string{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	Expect(dec.Token()).To(Equal(json.Delim('{')))
+	for dec.More() {
+		k, err := dec.Token()
+		Expect(err).NotTo(HaveOccurred())
+		ks = append(ks, k.(string))
+		var skip json.RawMessage
+		Expect(dec.Decode(&skip)).To(Succeed())
+	}
+	return ks
+}
+
+var _ = Describe("Of", func() {
+	DescribeTable(
+		"should see fields as encoding/json",
+
+		func(v interface{}, expected []string) {
+			Expect(names(reflect.TypeOf(v))).To(Equal(expected))
+			Expect(keys(v)).To(Equal(expected))
+		},
+
+		Entry("tags", struct {
+			A int `json:"a"`
+			B int `json:"b,omitempty"`
+			C int `json:"-"`
+			D int `json:"-,"`
+			E int `json:",string"`
+			g int
+		}{B: 1}, []string{"a", "b", "-", "E"}),
+		Entry("embedded structs", struct {
+			Inner
+			*Other
+			X int
+		}{Other: &Other{}}, []string{"A", "B", "c", "X"}),
+		Entry("outer fields", struct {
+			Inner
+			A int `json:"B"`
+		}{}, []string{"A", "B"}),
+		Entry("tagged embedded structs", struct {
+			Inner `json:"inner"`
+		}{}, []string{"inner"}),
+		Entry("conflicts at the same depth", struct {
+			Left
+			Right
+		}{}, []string{"B"}),
+		Entry("structs embedded twice", struct {
+			LeftInner
+			RightInner
+		}{}, []string{}),
+		Entry("embedded non-structs", struct {
+			MyInt
+			myInt
+		}{}, []string{"MyInt"}),
+		Entry("unexported embedded structs", struct {
+			inner
+		}{}, []string{"X"}),
+		Entry("recursive structs", Recursive{}, []string{"N"}),
+	)
+
+	It("should describe fields", func() {
+		fields := Of(reflect.TypeOf(openrtb2.Regs{}))
+		f, ok := fields.ByName["gdpr"]
+		Expect(ok).To(BeTrue())
+		Expect(*f).To(Equal(Field{Name: "gdpr", Index: []int{1}, Type: reflect.TypeOf((*int8)(nil)), Tagged: true, OmitEmpty: true}))
+		Expect(Of(reflect.TypeOf(openrtb2.Regs{}))).To(BeIdenticalTo(fields))
+	})
+})
+
+var _ = Describe("Field.Value", func() {
+	type T struct {
+		*Other
+	}
+	f := Of(reflect.TypeOf(T{})).ByName["c"]
+
+	It("should skip fields, promoted through nil pointers", func() {
+		var t T
+		_, ok := f.Value(reflect.ValueOf(&t).Elem(), false)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should allocate embedded pointers", func() {
+		var t T
+		v, ok := f.Value(reflect.ValueOf(&t).Elem(), true)
+		Expect(ok).To(BeTrue())
+		v.SetInt(1)
+		Expect(t.Other).To(Equal(&Other{C: 1}))
+	})
+})
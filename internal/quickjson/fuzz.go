@@ -0,0 +1,33 @@
+//go:build go1.18
+// +build go1.18
+
+package quickjson
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+// Fuzz checks, that unmarshaling of arbitrary input into a value, returned by zero, followed by marshaling,
+// never panics and is idempotent (see Idempotent).
+// Files, matching pattern, are added to the seed corpus.
+func Fuzz(f *testing.F, pattern string, zero func() interface{}) {
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if err := Idempotent(data, zero); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+// Package quickjson provides property-based and fuzz testing helpers for JSON encoding of OpenRTB objects
+//
+// Random values are generated so, that they survive JSON round-trip unchanged:
+// strings are valid UTF-8, raw messages are valid compact JSON, and empty slices and maps are nil.
+// Pointers are generated nil, pointing to zero values (e.g., Video.Skip = 0, or empty arrays) and pointing to random values.
+package quickjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+)
+
+// MaxDepth limits nesting of generated structs; deeper structs are left zero.
+const MaxDepth = 8
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// rawMessages are valid compact JSON values, generated for json.RawMessage fields.
+var rawMessages = []string{
+	`{}`,
+	`{"a":1}`,
+	`{"prebid":{"bidder":{"appnexus":{"placement_id":12883451}}}}`,
+	`[1,"two",3.5,true,null]`,
+	`"text"`,
+	`42`,
+	`false`,
+}
+
+// runes are used in generated strings, including characters, escaped by encoding/json.
+var runes = []rune("abcXYZ019 -_./:?=&<>\"\\\n\té中\U0001F600")
+
+// New returns a random value of type t.
+func New(rnd *rand.Rand, t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	fill(rnd, v, 0)
+	return v
+}
+
+func fill(rnd *rand.Rand, v reflect.Value, depth int) {
+	t := v.Type()
+	if t == rawMessageType {
+		if rnd.Intn(2) == 0 {
+			v.SetBytes([]byte(rawMessages[rnd.Intn(len(rawMessages))]))
+		}
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(rnd.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		bits := uint(t.Bits())
+		switch rnd.Intn(3) {
+		case 0:
+			v.SetInt(int64(rnd.Intn(10)))
+		case 1:
+			v.SetInt(-int64(rnd.Intn(10)))
+		default:
+			v.SetInt(int64(rnd.Uint64()) >> (64 - bits))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(rnd.Uint64() >> (64 - uint(t.Bits())))
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		switch rnd.Intn(3) {
+		case 0:
+			f = float64(rnd.Intn(1000)) / 100
+		case 1:
+			f = rnd.NormFloat64() * math.Pow(10, float64(rnd.Intn(20)-10))
+		}
+		v.SetFloat(f)
+	case reflect.String:
+		r := make([]rune, rnd.Intn(12))
+		for i := range r {
+			r[i] = runes[rnd.Intn(len(runes))]
+		}
+		v.SetString(string(r))
+	case reflect.Ptr:
+		if depth >= MaxDepth || rnd.Intn(3) == 0 {
+			return
+		}
+		p := reflect.New(t.Elem())
+		if rnd.Intn(2) == 0 {
+			fill(rnd, p.Elem(), depth+1)
+		}
+		// pointer to nil slice is encoded as null, which is decoded as nil pointer
+		if k := t.Elem().Kind(); k == reflect.Slice && p.Elem().IsNil() {
+			p.Elem().Set(reflect.MakeSlice(t.Elem(), 0, 0))
+		} else if k == reflect.Map && p.Elem().IsNil() {
+			p.Elem().Set(reflect.MakeMap(t.Elem()))
+		}
+		v.Set(p)
+	case reflect.Slice:
+		if depth >= MaxDepth || rnd.Intn(3) == 0 {
+			return
+		}
+		n := 1 + rnd.Intn(3)
+		s := reflect.MakeSlice(t, n, n)
+		for i := 0; i < s.Len(); i++ {
+			fill(rnd, s.Index(i), depth+1)
+		}
+		v.Set(s)
+	case reflect.Map:
+		if depth >= MaxDepth || rnd.Intn(3) == 0 {
+			return
+		}
+		m := reflect.MakeMap(t)
+		for i := 0; i < 1+rnd.Intn(3); i++ {
+			k, e := New(rnd, t.Key()), reflect.New(t.Elem()).Elem()
+			fill(rnd, e, depth+1)
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+	case reflect.Struct:
+		if depth >= MaxDepth {
+			return
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+				continue
+			}
+			fill(rnd, v.Field(i), depth+1)
+			// nil raw message without omitempty is encoded as null, which is decoded as non-nil raw message
+			if f.Type == rawMessageType && v.Field(i).Len() == 0 && !strings.Contains(f.Tag.Get("json"), ",omitempty") {
+				v.Field(i).SetBytes([]byte(rawMessages[0]))
+			}
+		}
+	}
+}
+
+// RoundTrip marshals v (a pointer) to JSON and unmarshals it to a new value of the same type.
+func RoundTrip(v interface{}) (interface{}, error) {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	res := reflect.New(reflect.TypeOf(v).Elem())
+	if err := json.Unmarshal(buf, res.Interface()); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, buf)
+	}
+	return res.Interface(), nil
+}
+
+// Idempotent checks, that unmarshaling of data into a value, returned by zero, followed by marshaling, is idempotent:
+// the result marshals to the same JSON after another unmarshaling.
+// Data, that fails to unmarshal, is not checked.
+func Idempotent(data []byte, zero func() interface{}) error {
+	v := zero()
+	if json.Unmarshal(data, v) != nil {
+		return nil
+	}
+	first, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("marshal: %v", err)
+	}
+
+	w := zero()
+	if err := json.Unmarshal(first, w); err != nil {
+		return fmt.Errorf("unmarshal of %s: %v", first, err)
+	}
+	second, err := json.Marshal(w)
+	if err != nil {
+		return fmt.Errorf("marshal: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		return fmt.Errorf("not idempotent:\n%s\n%s", first, second)
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Command enumgen generates enum values of typed constants of adcom1, openrtb2, openrtb3 and native1 packages
+// for jsonschema package.
+//
+// Usage (from jsonschema directory):
+//
+//	go run ./internal/enumgen
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var packages = []string{"adcom1", "native1", "openrtb2", "openrtb3"}
+
+// openRange matches comments on values, reserved for vendors (e.g., "Values of 500+ hold vendor-specific codes").
+var openRange = regexp.MustCompile(`\b(\d{3,})(?:\+| and greater)`)
+
+type enum struct {
+	pkg, name string
+	values    []int64
+	open      int64 // vendor-specific values start; zero if none
+}
+
+func main() {
+	var enums []*enum
+	for _, pkg := range packages {
+		es, err := parsePackage(filepath.Join("..", pkg), pkg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		enums = append(enums, es...)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("// Code generated by enumgen; DO NOT EDIT.\n\npackage jsonschema\n\nimport (\n\t\"reflect\"\n\n")
+	for _, pkg := range packages {
+		fmt.Fprintf(&buf, "\t%q\n", "github.com/prebid/openrtb/v20/"+pkg)
+	}
+	buf.WriteString(")\n\nvar enums = map[reflect.Type]enum{\n")
+	for _, e := range enums {
+		values := make([]string, len(e.values))
+		for i, v := range e.values {
+			values[i] = strconv.FormatInt(v, 10)
+		}
+		fmt.Fprintf(&buf, "\treflect.TypeOf(%s.%s(0)): {values: []int64{%s}, open: %d},\n", e.pkg, e.name, strings.Join(values, ", "), e.open)
+	}
+	buf.WriteString("}\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile("enums.go", src, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func parsePackage(dir, pkg string) ([]*enum, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	p, ok := pkgs[pkg]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found in %s", pkg, dir)
+	}
+
+	byName := make(map[string]*enum)
+	openByFile := make(map[string]int64)
+	for filename, f := range p.Files {
+		for _, c := range f.Comments {
+			if m := openRange.FindStringSubmatch(c.Text()); m != nil {
+				openByFile[filename], _ = strconv.ParseInt(m[1], 10, 64)
+			}
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				if id, ok := ts.Type.(*ast.Ident); ok && ts.Assign == 0 && strings.HasPrefix(id.Name, "int") {
+					byName[ts.Name.Name] = &enum{pkg: pkg, name: ts.Name.Name}
+				}
+			}
+		}
+	}
+
+	for filename, f := range p.Files {
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			// untyped constants, following typed ones in the same block, are taken as values of the same type
+			var e *enum
+			for _, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if vs.Type != nil {
+					e = nil
+					if id, ok := vs.Type.(*ast.Ident); ok {
+						e = byName[id.Name]
+					}
+				}
+				if e == nil {
+					continue
+				}
+				for _, v := range vs.Values {
+					n, err := intValue(v)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %v", fset.Position(v.Pos()), err)
+					}
+					e.values = append(e.values, n)
+				}
+				if open := openByFile[filename]; open != 0 {
+					e.open = open
+				}
+			}
+		}
+	}
+
+	var enums []*enum
+	for _, e := range byName {
+		if len(e.values) == 0 {
+			continue
+		}
+		sort.Slice(e.values, func(i, j int) bool { return e.values[i] < e.values[j] })
+		uniq := e.values[:1]
+		for _, v := range e.values[1:] {
+			if v != uniq[len(uniq)-1] {
+				uniq = append(uniq, v)
+			}
+		}
+		e.values = uniq
+		enums = append(enums, e)
+	}
+	sort.Slice(enums, func(i, j int) bool { return enums[i].name < enums[j].name })
+	return enums, nil
+}
+
+func intValue(x ast.Expr) (int64, error) {
+	switch x := x.(type) {
+	case *ast.BasicLit:
+		if x.Kind == token.INT {
+			return strconv.ParseInt(x.Value, 0, 64)
+		}
+	case *ast.UnaryExpr:
+		if x.Op == token.SUB {
+			n, err := intValue(x.X)
+			return -n, err
+		}
+	case *ast.ParenExpr:
+		return intValue(x.X)
+	}
+	return 0, fmt.Errorf("unsupported constant expression")
+}
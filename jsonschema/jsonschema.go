@@ -0,0 +1,168 @@
+// Package jsonschema provides JSON Schema (draft 2020-12) generation for OpenRTB (2.x, 3.0), AdCOM 1.0 and Native 1.x objects
+//
+// Schemas are derived from struct tags, as encoding/json sees them:
+// fields without omitempty are required, typed constants (e.g., adcom1.APIFramework) constrain values to enums,
+// allowing vendor-specific ranges (e.g., 500+) where the specification reserves them.
+//
+// https://json-schema.org/draft/2020-12/json-schema-core.html
+package jsonschema
+
+//go:generate go run ./internal/enumgen
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/internal/jsonfields"
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Draft is the JSON Schema dialect of generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Ref                  string             `json:"$ref,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Enum                 []int64            `json:"enum,omitempty"`
+	Minimum              *int64             `json:"minimum,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Roots are the top-level objects, schemas are usually generated for, by name.
+var Roots = map[string]interface{}{
+	"openrtb2.BidRequest":       openrtb2.BidRequest{},
+	"openrtb2.BidResponse":      openrtb2.BidResponse{},
+	"openrtb3.Body":             openrtb3.Body{},
+	"native1.request.Request":   request.Request{},
+	"native1.response.Response": response.Response{},
+	"adcom1.Ad":                 adcom1.Ad{},
+	"adcom1.Placement":          adcom1.Placement{},
+	"adcom1.Site":               adcom1.Site{},
+	"adcom1.App":                adcom1.App{},
+	"adcom1.DOOH":               adcom1.DOOH{},
+	"adcom1.User":               adcom1.User{},
+	"adcom1.Device":             adcom1.Device{},
+	"adcom1.Regs":               adcom1.Regs{},
+	"adcom1.Restrictions":       adcom1.Restrictions{},
+}
+
+// enum describes values of a typed constant.
+type enum struct {
+	values []int64
+	open   int64 // vendor-specific values start; zero if none
+}
+
+// For returns schema of v (a struct, or pointer to one), with definitions of all nested objects in $defs.
+func For(v interface{}) *Schema {
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	g := generator{defs: make(map[string]*Schema)}
+	root := g.schema(t)
+	root.Schema = Draft
+	root.Title = defName(t)
+	root.Defs = g.defs
+	return root
+}
+
+// Marshal returns indented JSON of the schema of v.
+func Marshal(v interface{}) ([]byte, error) {
+	return json.MarshalIndent(For(v), "", "  ")
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// schema returns schema of t; structs are defined in $defs and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	if t == rawMessageType {
+		return &Schema{}
+	}
+	if e, ok := enums[t]; ok {
+		s := &Schema{Type: "integer", Enum: e.values}
+		if e.open != 0 {
+			open := e.open
+			return &Schema{AnyOf: []*Schema{s, {Type: "integer", Minimum: &open}}}
+		}
+		return s
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return g.schema(t.Elem())
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := int64(0)
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string"} // base64
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		name := defName(t)
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // placeholder for recursive types
+			g.defs[name] = g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	}
+	return &Schema{}
+}
+
+// object returns schema of struct type t.
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, f := range jsonfields.Of(t).List {
+		fs := g.schema(f.Type)
+		if f.Name == "ext" && f.Type == rawMessageType {
+			fs = &Schema{Type: "object"}
+		}
+		if !f.OmitEmpty {
+			s.Required = append(s.Required, f.Name)
+			switch f.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				// nil values are encoded as null
+				fs = &Schema{AnyOf: []*Schema{fs, {Type: "null"}}}
+			}
+		}
+		s.Properties[f.Name] = fs
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// defName returns definition name of named type t (e.g., "native1.request.Asset").
+func defName(t reflect.Type) string {
+	path := strings.TrimPrefix(t.PkgPath(), "github.com/prebid/openrtb/v20/")
+	return strings.Replace(path, "/", ".", -1) + "." + t.Name()
+}
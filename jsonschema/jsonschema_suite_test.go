@@ -0,0 +1,13 @@
+package jsonschema_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestJsonschema(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Jsonschema Suite")
+}
@@ -0,0 +1,182 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	. "github.com/prebid/openrtb/v20/jsonschema"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// validate checks doc against the subset of JSON Schema, For generates.
+func validate(root, s *Schema, doc interface{}, path string) error {
+	if s.Ref != "" {
+		def, ok := root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+		if !ok {
+			return fmt.Errorf("%s: unresolved %s", path, s.Ref)
+		}
+		if err := validate(root, def, doc, path); err != nil {
+			return err
+		}
+	}
+
+	if len(s.AnyOf) != 0 {
+		var errs []string
+		for _, sub := range s.AnyOf {
+			err := validate(root, sub, doc, path)
+			if err == nil {
+				errs = nil
+				break
+			}
+			errs = append(errs, err.Error())
+		}
+		if errs != nil {
+			return fmt.Errorf("%s: no anyOf matched: %s", path, strings.Join(errs, "; "))
+		}
+	}
+
+	switch s.Type {
+	case "":
+	case "null":
+		if doc != nil {
+			return fmt.Errorf("%s: not null", path)
+		}
+	case "string":
+		if _, ok := doc.(string); !ok {
+			return fmt.Errorf("%s: not a string", path)
+		}
+	case "boolean":
+		if _, ok := doc.(bool); !ok {
+			return fmt.Errorf("%s: not a boolean", path)
+		}
+	case "number", "integer":
+		n, ok := doc.(float64)
+		if !ok || s.Type == "integer" && n != float64(int64(n)) {
+			return fmt.Errorf("%s: not an %s", path, s.Type)
+		}
+		if s.Minimum != nil && n < float64(*s.Minimum) {
+			return fmt.Errorf("%s: %v < %d", path, n, *s.Minimum)
+		}
+		if s.Enum != nil {
+			found := false
+			for _, v := range s.Enum {
+				found = found || float64(v) == n
+			}
+			if !found {
+				return fmt.Errorf("%s: %v not in %v", path, n, s.Enum)
+			}
+		}
+	case "array":
+		a, ok := doc.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: not an array", path)
+		}
+		for i, v := range a {
+			if err := validate(root, s.Items, v, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		o, ok := doc.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: not an object", path)
+		}
+		for _, name := range s.Required {
+			if _, ok := o[name]; !ok {
+				return fmt.Errorf("%s: missing %s", path, name)
+			}
+		}
+		for name, v := range o {
+			ps, ok := s.Properties[name]
+			if !ok {
+				ps = s.AdditionalProperties
+			}
+			if ps == nil {
+				continue
+			}
+			if err := validate(root, ps, v, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateFile(v interface{}, file string) error {
+	buf, err := ioutil.ReadFile(filepath.Join("..", file))
+	Expect(err).NotTo(HaveOccurred())
+	return validateJSON(v, buf)
+}
+
+func validateJSON(v interface{}, buf []byte) error {
+	var doc interface{}
+	Expect(json.Unmarshal(buf, &doc)).To(Succeed())
+
+	s := For(v)
+	return validate(s, s, doc, "$")
+}
+
+var _ = Describe("For", func() {
+	DescribeTable(
+		"accepts testdata",
+
+		func(v interface{}, file string) {
+			Expect(validateFile(v, file)).To(Succeed())
+		},
+
+		Entry("openrtb2 bid request 2.5 native", openrtb2.BidRequest{}, "openrtb2/testdata/bid-request/2.5/native-ad.json"),
+		Entry("openrtb2 bid request 2.6 mobile", openrtb2.BidRequest{}, "openrtb2/testdata/bid-request/2.6/mobile.json"),
+		Entry("openrtb2 bid request 2.6 video", openrtb2.BidRequest{}, "openrtb2/testdata/bid-request/2.6/video.json"),
+		Entry("openrtb2 bid request 2.6 pmp", openrtb2.BidRequest{}, "openrtb2/testdata/bid-request/2.6/pmp-with-direct-deal.json"),
+		Entry("openrtb2 bid response 2.6 win notice", openrtb2.BidResponse{}, "openrtb2/testdata/bid-response/2.6/ad-served-on-win-notice.json"),
+		Entry("openrtb3 request", openrtb3.Body{}, "openrtb3/testdata/request.json"),
+		Entry("openrtb3 response", openrtb3.Body{}, "openrtb3/testdata/response.json"),
+		Entry("adcom1 request context", adcom1.RequestContext{}, "adcom1/testdata/request-context.json"),
+		Entry("native1 request", request.Request{}, "native1/request/testdata/v1.2/content-context.json"),
+		Entry("native1 response", response.Response{}, "native1/response/testdata/v1.2/video.json"),
+	)
+
+	It("should derive required from fields without omitempty", func() {
+		s := For(&openrtb2.BidRequest{})
+		Expect(s.Ref).To(Equal("#/$defs/openrtb2.BidRequest"))
+		Expect(s.Defs["openrtb2.BidRequest"].Required).To(Equal([]string{"id", "imp"}))
+		Expect(validateJSON(openrtb2.BidRequest{}, []byte(`{"imp":[]}`))).To(MatchError(ContainSubstring("missing id")))
+	})
+
+	It("should constrain enums", func() {
+		Expect(validateJSON(adcom1.Device{}, []byte(`{"type":4}`))).To(Succeed())
+		Expect(validateJSON(adcom1.Device{}, []byte(`{"type":42}`))).To(MatchError(ContainSubstring("42 not in")))
+	})
+
+	It("should allow vendor-specific enum values", func() {
+		Expect(validateJSON(openrtb2.Banner{}, []byte(`{"api":[3,500]}`))).To(Succeed())
+		Expect(validateJSON(openrtb2.Banner{}, []byte(`{"api":[3,400]}`))).NotTo(Succeed())
+	})
+
+	It("should inline embedded structs", func() {
+		s := For(adcom1.Site{})
+		Expect(s.Defs["adcom1.Site"].Properties).To(HaveKey("id"))
+		Expect(s.Defs["adcom1.Site"].Properties).To(HaveKey("domain"))
+		Expect(s.Defs).NotTo(HaveKey("adcom1.DistributionChannel"))
+	})
+
+	It("should generate schemas of all roots", func() {
+		for name, v := range Roots {
+			buf, err := Marshal(v)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(buf)).To(ContainSubstring(`"$ref": "#/$defs/` + name + `"`))
+		}
+	})
+})
@@ -0,0 +1,98 @@
+package msgpack_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/msgpack"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// corpus returns bid requests and responses of openrtb2 testdata.
+func corpus(b *testing.B) []interface{} {
+	var objs []interface{}
+	for _, dir := range []string{"bid-request", "bid-response"} {
+		files, err := filepath.Glob(filepath.Join("..", "openrtb2", "testdata", dir, "*", "*.json"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, file := range files {
+			buf, err := ioutil.ReadFile(file)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			var obj interface{} = new(openrtb2.BidRequest)
+			if dir == "bid-response" {
+				obj = new(openrtb2.BidResponse)
+			}
+			if err := json.Unmarshal(buf, obj); err != nil {
+				b.Fatal(err)
+			}
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+func benchmarkMarshal(b *testing.B, marshal func(interface{}) ([]byte, error)) {
+	objs := corpus(b)
+	size := 0
+	for _, obj := range objs {
+		buf, err := marshal(obj)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size += len(buf)
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := marshal(objs[i%len(objs)]); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportMetric(float64(size)/float64(len(objs)), "bytes/obj")
+}
+
+func benchmarkUnmarshal(b *testing.B, marshal func(interface{}) ([]byte, error), unmarshal func([]byte, interface{}) error) {
+	objs := corpus(b)
+	bufs := make([][]byte, len(objs))
+	for i, obj := range objs {
+		var err error
+		if bufs[i], err = marshal(obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var obj interface{} = new(openrtb2.BidRequest)
+		if _, ok := objs[i%len(objs)].(*openrtb2.BidResponse); ok {
+			obj = new(openrtb2.BidResponse)
+		}
+		if err := unmarshal(bufs[i%len(bufs)], obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMarshal_JSON(b *testing.B) {
+	benchmarkMarshal(b, json.Marshal)
+}
+
+func BenchmarkMarshal_MessagePack(b *testing.B) {
+	benchmarkMarshal(b, msgpack.Marshal)
+}
+
+func BenchmarkUnmarshal_JSON(b *testing.B) {
+	benchmarkUnmarshal(b, json.Marshal, json.Unmarshal)
+}
+
+func BenchmarkUnmarshal_MessagePack(b *testing.B) {
+	benchmarkUnmarshal(b, msgpack.Marshal, msgpack.Unmarshal)
+}
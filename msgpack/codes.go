@@ -0,0 +1,37 @@
+package msgpack
+
+// MessagePack format codes.
+const (
+	codeFixMap   byte = 0x80 // 1000xxxx
+	codeFixArray byte = 0x90 // 1001xxxx
+	codeFixStr   byte = 0xa0 // 101xxxxx
+	codeNil      byte = 0xc0
+	codeFalse    byte = 0xc2
+	codeTrue     byte = 0xc3
+	codeBin8     byte = 0xc4
+	codeBin16    byte = 0xc5
+	codeBin32    byte = 0xc6
+	codeExt8     byte = 0xc7
+	codeExt16    byte = 0xc8
+	codeExt32    byte = 0xc9
+	codeFloat32  byte = 0xca
+	codeFloat64  byte = 0xcb
+	codeUint8    byte = 0xcc
+	codeUint16   byte = 0xcd
+	codeUint32   byte = 0xce
+	codeUint64   byte = 0xcf
+	codeInt8     byte = 0xd0
+	codeInt16    byte = 0xd1
+	codeInt32    byte = 0xd2
+	codeInt64    byte = 0xd3
+	codeFixExt1  byte = 0xd4
+	codeFixExt16 byte = 0xd8
+	codeStr8     byte = 0xd9
+	codeStr16    byte = 0xda
+	codeStr32    byte = 0xdb
+	codeArray16  byte = 0xdc
+	codeArray32  byte = 0xdd
+	codeMap16    byte = 0xde
+	codeMap32    byte = 0xdf
+	codeNegFix   byte = 0xe0 // 111xxxxx
+)
@@ -0,0 +1,413 @@
+package msgpack
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+
+	"github.com/prebid/openrtb/v20/internal/jsonfields"
+)
+
+type decoder struct {
+	buf []byte
+	off int
+}
+
+func isStr(c byte) bool {
+	return c&0xe0 == codeFixStr || c == codeStr8 || c == codeStr16 || c == codeStr32
+}
+
+func isBin(c byte) bool {
+	return c == codeBin8 || c == codeBin16 || c == codeBin32
+}
+
+func isArray(c byte) bool {
+	return c&0xf0 == codeFixArray || c == codeArray16 || c == codeArray32
+}
+
+func isMap(c byte) bool {
+	return c&0xf0 == codeFixMap || c == codeMap16 || c == codeMap32
+}
+
+func isNumber(c byte) bool {
+	return c <= 0x7f || c >= codeNegFix || c >= codeFloat32 && c <= codeInt64
+}
+
+func (d *decoder) peek() (byte, error) {
+	if d.off >= len(d.buf) {
+		return 0, ErrTruncated
+	}
+	return d.buf[d.off], nil
+}
+
+func (d *decoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.buf)-d.off {
+		return nil, ErrTruncated
+	}
+	b := d.buf[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+// uintN reads n-byte big-endian unsigned integer.
+func (d *decoder) uintN(n int) (uint64, error) {
+	b, err := d.read(n)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v, nil
+}
+
+// header reads header of str, bin, array or map, returning its length.
+func (d *decoder) header() (int, error) {
+	c, err := d.peek()
+	if err != nil {
+		return 0, err
+	}
+	d.off++
+
+	var n uint64
+	switch {
+	case c&0xe0 == codeFixStr:
+		n = uint64(c & 0x1f)
+	case c&0xf0 == codeFixArray, c&0xf0 == codeFixMap:
+		n = uint64(c & 0x0f)
+	case c == codeStr8 || c == codeBin8:
+		n, err = d.uintN(1)
+	case c == codeStr16 || c == codeBin16 || c == codeArray16 || c == codeMap16:
+		n, err = d.uintN(2)
+	default:
+		n, err = d.uintN(4)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.buf)-d.off) {
+		// every element takes at least one byte
+		return 0, ErrTruncated
+	}
+	return int(n), nil
+}
+
+// number reads integer or float; kind is reflect.Int64, reflect.Uint64 or reflect.Float64,
+// depending on which one of i, u or f is set.
+func (d *decoder) number() (i int64, u uint64, f float64, kind reflect.Kind, err error) {
+	c, err := d.peek()
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+	d.off++
+	switch {
+	case c <= 0x7f:
+		return 0, uint64(c), 0, reflect.Uint64, nil
+	case c >= codeNegFix:
+		return int64(int8(c)), 0, 0, reflect.Int64, nil
+	}
+
+	var x uint64
+	switch c {
+	case codeUint8, codeInt8:
+		x, err = d.uintN(1)
+	case codeUint16, codeInt16:
+		x, err = d.uintN(2)
+	case codeUint32, codeInt32, codeFloat32:
+		x, err = d.uintN(4)
+	default:
+		x, err = d.uintN(8)
+	}
+	if err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	switch c {
+	case codeUint8, codeUint16, codeUint32, codeUint64:
+		return 0, x, 0, reflect.Uint64, nil
+	case codeInt8:
+		return int64(int8(x)), 0, 0, reflect.Int64, nil
+	case codeInt16:
+		return int64(int16(x)), 0, 0, reflect.Int64, nil
+	case codeInt32:
+		return int64(int32(x)), 0, 0, reflect.Int64, nil
+	case codeInt64:
+		return int64(x), 0, 0, reflect.Int64, nil
+	case codeFloat32:
+		return 0, 0, float64(math.Float32frombits(uint32(x))), reflect.Float64, nil
+	}
+	return 0, 0, math.Float64frombits(x), reflect.Float64, nil
+}
+
+func (d *decoder) value(v reflect.Value) error {
+	if v.Type() == rawMessageType {
+		raw, err := d.json(nil)
+		if err != nil {
+			return err
+		}
+		v.SetBytes(raw)
+		return nil
+	}
+
+	c, err := d.peek()
+	if err != nil {
+		return err
+	}
+	if c == codeNil {
+		d.off++
+		switch v.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+			v.Set(reflect.Zero(v.Type()))
+		}
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return d.value(v.Elem())
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		raw, err := d.json(nil)
+		if err != nil {
+			return err
+		}
+		var x interface{}
+		if err := json.Unmarshal(raw, &x); err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(x))
+		return nil
+	case reflect.Bool:
+		if c == codeTrue || c == codeFalse {
+			d.off++
+			v.SetBool(c == codeTrue)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumber(c) {
+			return d.numeric(v, c)
+		}
+	case reflect.String:
+		if isStr(c) {
+			n, err := d.header()
+			if err != nil {
+				return err
+			}
+			s, err := d.read(n)
+			if err != nil {
+				return err
+			}
+			v.SetString(string(s))
+			return nil
+		}
+	case reflect.Slice:
+		if isBin(c) && v.Type().Elem().Kind() == reflect.Uint8 {
+			n, err := d.header()
+			if err != nil {
+				return err
+			}
+			b, err := d.read(n)
+			if err != nil {
+				return err
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		}
+		if isArray(c) {
+			n, err := d.header()
+			if err != nil {
+				return err
+			}
+			s := reflect.MakeSlice(v.Type(), n, n)
+			for i := 0; i < n; i++ {
+				if err := d.value(s.Index(i)); err != nil {
+					return err
+				}
+			}
+			v.Set(s)
+			return nil
+		}
+	case reflect.Array:
+		if isArray(c) {
+			n, err := d.header()
+			if err != nil {
+				return err
+			}
+			for i := 0; i < n; i++ {
+				if i < v.Len() {
+					err = d.value(v.Index(i))
+				} else {
+					err = d.skip()
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	case reflect.Map:
+		if isMap(c) && v.Type().Key().Kind() == reflect.String {
+			n, err := d.header()
+			if err != nil {
+				return err
+			}
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), n))
+			}
+			for i := 0; i < n; i++ {
+				k := reflect.New(v.Type().Key()).Elem()
+				if err := d.value(k); err != nil {
+					return err
+				}
+				e := reflect.New(v.Type().Elem()).Elem()
+				if err := d.value(e); err != nil {
+					return err
+				}
+				v.SetMapIndex(k, e)
+			}
+			return nil
+		}
+	case reflect.Struct:
+		if isMap(c) {
+			return d.structure(v)
+		}
+	}
+	return &TypeError{Code: c, Type: v.Type()}
+}
+
+// numeric decodes number into integer or float v.
+func (d *decoder) numeric(v reflect.Value, c byte) error {
+	i, u, f, kind, err := d.number()
+	if err != nil {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		switch kind {
+		case reflect.Int64:
+			f = float64(i)
+		case reflect.Uint64:
+			f = float64(u)
+		}
+		v.SetFloat(f)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if kind == reflect.Uint64 {
+			if u > math.MaxInt64 {
+				return &TypeError{Code: c, Type: v.Type()}
+			}
+			i = int64(u)
+		}
+		if kind == reflect.Float64 || v.OverflowInt(i) {
+			return &TypeError{Code: c, Type: v.Type()}
+		}
+		v.SetInt(i)
+		return nil
+	}
+	if kind != reflect.Uint64 || v.OverflowUint(u) {
+		return &TypeError{Code: c, Type: v.Type()}
+	}
+	v.SetUint(u)
+	return nil
+}
+
+func (d *decoder) structure(v reflect.Value) error {
+	n, err := d.header()
+	if err != nil {
+		return err
+	}
+
+	fields := jsonfields.Of(v.Type()).ByName
+	for i := 0; i < n; i++ {
+		c, err := d.peek()
+		if err != nil {
+			return err
+		}
+		if !isStr(c) {
+			return &TypeError{Code: c, Type: reflect.TypeOf("")}
+		}
+		kn, err := d.header()
+		if err != nil {
+			return err
+		}
+		key, err := d.read(kn)
+		if err != nil {
+			return err
+		}
+
+		f, ok := fields[string(key)]
+		if !ok {
+			if err := d.skip(); err != nil {
+				return err
+			}
+			continue
+		}
+		fv, _ := f.Value(v, true)
+		if err := d.value(fv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skip skips a value of any format.
+func (d *decoder) skip() error {
+	c, err := d.peek()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case c == codeNil || c == codeTrue || c == codeFalse:
+		d.off++
+		return nil
+	case isNumber(c):
+		_, _, _, _, err := d.number()
+		return err
+	case c >= codeFixExt1 && c <= codeFixExt16:
+		_, err := d.read(2 + 1<<(c-codeFixExt1))
+		return err
+	case c == codeExt8 || c == codeExt16 || c == codeExt32:
+		d.off++
+		n, err := d.uintN(1 << (c - codeExt8))
+		if err != nil {
+			return err
+		}
+		if n >= uint64(len(d.buf)) {
+			return ErrTruncated
+		}
+		_, err = d.read(1 + int(n))
+		return err
+	case isArray(c) || isMap(c):
+		n, err := d.header()
+		if err != nil {
+			return err
+		}
+		if isMap(c) {
+			n *= 2
+		}
+		for i := 0; i < n; i++ {
+			if err := d.skip(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case isStr(c) || isBin(c):
+		n, err := d.header()
+		if err != nil {
+			return err
+		}
+		_, err = d.read(n)
+		return err
+	}
+	return ErrInvalid
+}
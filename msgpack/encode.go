@@ -0,0 +1,232 @@
+package msgpack
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/prebid/openrtb/v20/internal/jsonfields"
+)
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+func appendValue(b []byte, v reflect.Value) ([]byte, error) {
+	if !v.IsValid() {
+		return append(b, codeNil), nil
+	}
+	if v.Type() == rawMessageType {
+		if v.IsNil() {
+			return append(b, codeNil), nil
+		}
+		return appendJSON(b, v.Bytes())
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return append(b, codeNil), nil
+		}
+		return appendValue(b, v.Elem())
+	case reflect.Bool:
+		return appendBool(b, v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendInt(b, v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return appendUint(b, v.Uint()), nil
+	case reflect.Float32:
+		return appendFloat32(b, float32(v.Float())), nil
+	case reflect.Float64:
+		return appendFloat64(b, v.Float()), nil
+	case reflect.String:
+		return appendString(b, v.String()), nil
+	case reflect.Slice:
+		if v.IsNil() {
+			return append(b, codeNil), nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return appendBin(b, v.Bytes()), nil
+		}
+		return appendArray(b, v)
+	case reflect.Array:
+		return appendArray(b, v)
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if v.IsNil() {
+			return append(b, codeNil), nil
+		}
+		return appendMap(b, v)
+	case reflect.Struct:
+		return appendStruct(b, v)
+	}
+	return nil, &UnsupportedTypeError{Type: v.Type()}
+}
+
+func appendArray(b []byte, v reflect.Value) ([]byte, error) {
+	b = appendArrayHeader(b, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		var err error
+		if b, err = appendValue(b, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// appendMap appends map with sorted keys, as encoding/json does.
+func appendMap(b []byte, v reflect.Value) ([]byte, error) {
+	keys := v.MapKeys()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+	b = appendMapHeader(b, len(keys))
+	for _, k := range keys {
+		b = appendString(b, k.String())
+		var err error
+		if b, err = appendValue(b, v.MapIndex(k)); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+func appendStruct(b []byte, v reflect.Value) ([]byte, error) {
+	fields := jsonfields.Of(v.Type()).List
+
+	// fields are counted first, as their count precedes them
+	n := 0
+	for i := range fields {
+		if _, ok := structField(v, &fields[i]); ok {
+			n++
+		}
+	}
+
+	b = appendMapHeader(b, n)
+	for i := range fields {
+		f := &fields[i]
+		fv, ok := structField(v, f)
+		if !ok {
+			continue
+		}
+		b = appendString(b, f.Name)
+		var err error
+		if b, err = appendValue(b, fv); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// structField returns value of f in v; ok is false if the field is to be omitted.
+func structField(v reflect.Value, f *jsonfields.Field) (reflect.Value, bool) {
+	fv, ok := f.Value(v, false)
+	if !ok || f.OmitEmpty && jsonfields.IsEmpty(fv) {
+		return reflect.Value{}, false
+	}
+	return fv, true
+}
+
+func appendBool(b []byte, v bool) []byte {
+	if v {
+		return append(b, codeTrue)
+	}
+	return append(b, codeFalse)
+}
+
+func appendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendUint(b, uint64(v))
+	case v >= -32:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, codeInt8, byte(v))
+	case v >= math.MinInt16:
+		return append(b, codeInt16, byte(v>>8), byte(v))
+	case v >= math.MinInt32:
+		return appendBig32(append(b, codeInt32), uint32(v))
+	}
+	return appendBig64(append(b, codeInt64), uint64(v))
+}
+
+func appendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= 0x7f:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, codeUint8, byte(v))
+	case v <= math.MaxUint16:
+		return append(b, codeUint16, byte(v>>8), byte(v))
+	case v <= math.MaxUint32:
+		return appendBig32(append(b, codeUint32), uint32(v))
+	}
+	return appendBig64(append(b, codeUint64), v)
+}
+
+func appendFloat32(b []byte, v float32) []byte {
+	return appendBig32(append(b, codeFloat32), math.Float32bits(v))
+}
+
+func appendFloat64(b []byte, v float64) []byte {
+	return appendBig64(append(b, codeFloat64), math.Float64bits(v))
+}
+
+func appendString(b []byte, s string) []byte {
+	switch n := len(s); {
+	case n < 32:
+		b = append(b, codeFixStr|byte(n))
+	case n <= math.MaxUint8:
+		b = append(b, codeStr8, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, codeStr16, byte(n>>8), byte(n))
+	default:
+		b = appendBig32(append(b, codeStr32), uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendBin(b []byte, v []byte) []byte {
+	switch n := len(v); {
+	case n <= math.MaxUint8:
+		b = append(b, codeBin8, byte(n))
+	case n <= math.MaxUint16:
+		b = append(b, codeBin16, byte(n>>8), byte(n))
+	default:
+		b = appendBig32(append(b, codeBin32), uint32(n))
+	}
+	return append(b, v...)
+}
+
+func appendArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, codeFixArray|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, codeArray16, byte(n>>8), byte(n))
+	}
+	return appendBig32(append(b, codeArray32), uint32(n))
+}
+
+func appendMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, codeFixMap|byte(n))
+	case n <= math.MaxUint16:
+		return append(b, codeMap16, byte(n>>8), byte(n))
+	}
+	return appendBig32(append(b, codeMap32), uint32(n))
+}
+
+func appendBig32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendBig64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -0,0 +1,223 @@
+package msgpack
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// appendJSON appends JSON value raw, transcoded to MessagePack.
+// Object keys keep their order; integers are encoded as integers, other numbers as float64.
+func appendJSON(b []byte, raw []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	b, err := appendJSONValue(b, dec)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("msgpack: invalid JSON: trailing data")
+	}
+	return b, nil
+}
+
+func appendJSONValue(b []byte, dec *json.Decoder) ([]byte, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case nil:
+		return append(b, codeNil), nil
+	case bool:
+		return appendBool(b, t), nil
+	case string:
+		return appendString(b, t), nil
+	case json.Number:
+		return appendJSONNumber(b, t)
+	case json.Delim:
+		// elements are encoded to a separate buffer, as their count precedes them
+		var elems []byte
+		n := 0
+		for ; dec.More(); n++ {
+			if t == '{' {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				elems = appendString(elems, key.(string))
+			}
+			if elems, err = appendJSONValue(elems, dec); err != nil {
+				return nil, err
+			}
+		}
+		if _, err := dec.Token(); err != nil { // closing delimiter
+			return nil, err
+		}
+
+		if t == '{' {
+			b = appendMapHeader(b, n)
+		} else {
+			b = appendArrayHeader(b, n)
+		}
+		return append(b, elems...), nil
+	}
+	return nil, errors.New("msgpack: invalid JSON")
+}
+
+func appendJSONNumber(b []byte, n json.Number) ([]byte, error) {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return appendInt(b, i), nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return appendUint(b, u), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, err
+	}
+	return appendFloat64(b, f), nil
+}
+
+// json reads a value of any format and appends it to b as JSON.
+// Binary values are encoded as base64 strings, as encoding/json does for []byte.
+func (d *decoder) json(b []byte) ([]byte, error) {
+	c, err := d.peek()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c == codeNil:
+		d.off++
+		return append(b, "null"...), nil
+	case c == codeTrue:
+		d.off++
+		return append(b, "true"...), nil
+	case c == codeFalse:
+		d.off++
+		return append(b, "false"...), nil
+	case isNumber(c):
+		i, u, f, kind, err := d.number()
+		if err != nil {
+			return nil, err
+		}
+		switch kind {
+		case reflect.Int64:
+			return strconv.AppendInt(b, i, 10), nil
+		case reflect.Uint64:
+			return strconv.AppendUint(b, u, 10), nil
+		}
+		return appendJSONFloat(b, f)
+	case isStr(c) || isBin(c):
+		n, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.read(n)
+		if err != nil {
+			return nil, err
+		}
+		if isBin(c) {
+			s = []byte(base64.StdEncoding.EncodeToString(s))
+		}
+		return appendJSONString(b, s), nil
+	case isArray(c) || isMap(c):
+		n, err := d.header()
+		if err != nil {
+			return nil, err
+		}
+		start, end := byte('['), byte(']')
+		if isMap(c) {
+			start, end = '{', '}'
+		}
+
+		b = append(b, start)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if isMap(c) {
+				kc, err := d.peek()
+				if err != nil {
+					return nil, err
+				}
+				if !isStr(kc) {
+					return nil, ErrInvalid
+				}
+				if b, err = d.json(b); err != nil {
+					return nil, err
+				}
+				b = append(b, ':')
+			}
+			if b, err = d.json(b); err != nil {
+				return nil, err
+			}
+		}
+		return append(b, end), nil
+	}
+	return nil, ErrInvalid
+}
+
+// appendJSONFloat formats f as encoding/json does.
+func appendJSONFloat(b []byte, f float64) ([]byte, error) {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return nil, ErrInvalid
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	return strconv.AppendFloat(b, f, format, -1, 64), nil
+}
+
+const hex = "0123456789abcdef"
+
+func appendJSONString(b []byte, s []byte) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			switch {
+			case c == '"' || c == '\\':
+				b = append(b, '\\', c)
+			case c == '\n':
+				b = append(b, '\\', 'n')
+			case c == '\r':
+				b = append(b, '\\', 'r')
+			case c == '\t':
+				b = append(b, '\\', 't')
+			case c < 0x20:
+				b = append(b, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf])
+			default:
+				b = append(b, c)
+			}
+			i++
+			continue
+		}
+
+		r, size := utf8.DecodeRune(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b = append(b, `\ufffd`...)
+		case r == '\u2028' || r == '\u2029':
+			b = append(b, '\\', 'u', '2', '0', '2', hex[r&0xf])
+		default:
+			b = append(b, s[i:i+size]...)
+		}
+		i += size
+	}
+	return append(b, '"')
+}
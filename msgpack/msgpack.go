@@ -0,0 +1,71 @@
+// Package msgpack provides MessagePack encoding of OpenRTB (2.x, 3.0), AdCOM 1.0 and Native 1.x objects
+//
+// Objects are encoded as maps, keyed by JSON field names (struct tags), following encoding/json rules
+// (omitempty, embedded structs), so encoding round-trips with JSON.
+// json.RawMessage values (e.g., Ext) are transcoded to MessagePack, rather than stored as opaque bytes,
+// and are decoded back into compact JSON with object keys in original order.
+//
+// https://github.com/msgpack/msgpack/blob/master/spec.md
+package msgpack
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Errors, returned by Unmarshal.
+var (
+	ErrTruncated = errors.New("msgpack: truncated data")
+	ErrTrailing  = errors.New("msgpack: trailing data")
+	ErrInvalid   = errors.New("msgpack: invalid format")
+)
+
+// UnsupportedTypeError is returned for values, which cannot be encoded.
+type UnsupportedTypeError struct {
+	Type reflect.Type
+}
+
+// Error implements error.
+func (e *UnsupportedTypeError) Error() string {
+	return "msgpack: unsupported type " + e.Type.String()
+}
+
+// TypeError is returned for MessagePack values, which cannot be decoded into Go values of Type.
+type TypeError struct {
+	Code byte // format code of MessagePack value
+	Type reflect.Type
+}
+
+// Error implements error.
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("msgpack: cannot decode value of format 0x%02x into %s", e.Code, e.Type)
+}
+
+// Marshal returns MessagePack encoding of v.
+func Marshal(v interface{}) ([]byte, error) {
+	return AppendMarshal(nil, v)
+}
+
+// AppendMarshal appends MessagePack encoding of v to b.
+func AppendMarshal(b []byte, v interface{}) ([]byte, error) {
+	return appendValue(b, reflect.ValueOf(v))
+}
+
+// Unmarshal parses MessagePack encoding in data into v, which must be a non-nil pointer.
+// Unknown map keys are skipped.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &UnsupportedTypeError{Type: reflect.TypeOf(v)}
+	}
+
+	d := decoder{buf: data}
+	if err := d.value(rv.Elem()); err != nil {
+		return err
+	}
+	if d.off != len(d.buf) {
+		return ErrTrailing
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package msgpack_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/prebid/openrtb/v20/msgpack"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Marshal", func() {
+	DescribeTable(
+		"round-trips testdata",
+
+		func(path string, newObj func() interface{}) {
+			golden, err := ioutil.ReadFile(filepath.Join("..", path))
+			Expect(err).NotTo(HaveOccurred())
+
+			obj := newObj()
+			Expect(json.Unmarshal(golden, obj)).To(Succeed())
+
+			buf, err := Marshal(obj)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(len(buf)).To(BeNumerically("<", len(golden)))
+
+			got := newObj()
+			Expect(Unmarshal(buf, got)).To(Succeed())
+
+			js, err := json.Marshal(got)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(js).To(MatchJSON(golden))
+		},
+
+		Entry("openrtb2 bid request 2.5 mobile", "openrtb2/testdata/bid-request/2.5/mobile.json", func() interface{} { return new(openrtb2.BidRequest) }),
+		Entry("openrtb2 bid request 2.6 video", "openrtb2/testdata/bid-request/2.6/video.json", func() interface{} { return new(openrtb2.BidRequest) }),
+		Entry("openrtb2 bid request 2.6 pmp", "openrtb2/testdata/bid-request/2.6/pmp-with-direct-deal.json", func() interface{} { return new(openrtb2.BidRequest) }),
+		Entry("openrtb2 bid response 2.5 native", "openrtb2/testdata/bid-response/2.5/native-markup-returned-inline.json", func() interface{} { return new(openrtb2.BidResponse) }),
+		Entry("openrtb2 bid response 2.6 vast", "openrtb2/testdata/bid-response/2.6/vast-xml-document-returned-inline.json", func() interface{} { return new(openrtb2.BidResponse) }),
+		Entry("openrtb3 request", "openrtb3/testdata/request.json", func() interface{} { return new(openrtb3.Body) }),
+		Entry("openrtb3 response", "openrtb3/testdata/response.json", func() interface{} { return new(openrtb3.Body) }),
+		Entry("adcom1 request context", "adcom1/testdata/request-context.json", func() interface{} { return new(adcom1.RequestContext) }),
+		Entry("adcom1 item specifications", "adcom1/testdata/item-specifications.json", func() interface{} { return new(adcom1.ItemSpec) }),
+		Entry("adcom1 media response", "adcom1/testdata/media-response.json", func() interface{} { return new(adcom1.BidMedia) }),
+		Entry("native1 request", "native1/request/testdata/v1.2/content-context.json", func() interface{} { return new(request.Request) }),
+		Entry("native1 response", "native1/response/testdata/v1.2/third-party.json", func() interface{} { return new(response.Response) }),
+	)
+
+	It("should transcode ext, keeping key order", func() {
+		ext := `{"z":1,"a":[true,null,-1.5,"s\u0001\"",18446744073709551615],"m":{"n":{}}}`
+		imp := openrtb2.Imp{ID: "1", Ext: json.RawMessage(ext)}
+
+		buf, err := Marshal(&imp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).NotTo(ContainSubstring(ext))
+
+		var got openrtb2.Imp
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(string(got.Ext)).To(Equal(`{"z":1,"a":[true,null,-1.5,"s\u0001\"",18446744073709551615],"m":{"n":{}}}`))
+	})
+
+	It("should reject invalid ext", func() {
+		_, err := Marshal(&openrtb2.Imp{ID: "1", Ext: json.RawMessage(`{"a":`)})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should inline embedded structs", func() {
+		site := adcom1.Site{DistributionChannel: adcom1.DistributionChannel{ID: "site-1"}, Domain: "example.com"}
+
+		buf, err := Marshal(&site)
+		Expect(err).NotTo(HaveOccurred())
+
+		var got map[string]interface{}
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(got).To(Equal(map[string]interface{}{"id": "site-1", "domain": "example.com"}))
+	})
+
+	It("should skip unknown keys", func() {
+		buf, err := Marshal(map[string]interface{}{"w": 300, "h": 250, "unknown": []interface{}{1, map[string]string{"x": "y"}}})
+		Expect(err).NotTo(HaveOccurred())
+
+		var got openrtb2.Format
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(got).To(Equal(openrtb2.Format{W: 300, H: 250}))
+	})
+
+	It("should reject mismatched types", func() {
+		buf, err := Marshal(map[string]interface{}{"w": "300"})
+		Expect(err).NotTo(HaveOccurred())
+
+		var got openrtb2.Format
+		err = Unmarshal(buf, &got)
+		Expect(err).To(BeAssignableToTypeOf(&TypeError{}))
+	})
+
+	It("should reject truncated and trailing data", func() {
+		buf, err := Marshal(&openrtb2.BidRequest{ID: "1", Imp: []openrtb2.Imp{{ID: "1"}}})
+		Expect(err).NotTo(HaveOccurred())
+
+		for i := 0; i < len(buf); i++ {
+			Expect(Unmarshal(buf[:i], new(openrtb2.BidRequest))).To(MatchError(ErrTruncated))
+		}
+		Expect(Unmarshal(append(buf, 0), new(openrtb2.BidRequest))).To(MatchError(ErrTrailing))
+	})
+
+	It("should reject unsupported types", func() {
+		_, err := Marshal(map[int]string{1: "x"})
+		Expect(err).To(BeAssignableToTypeOf(&UnsupportedTypeError{}))
+		Expect(Unmarshal(nil, openrtb2.BidRequest{})).To(BeAssignableToTypeOf(&UnsupportedTypeError{}))
+	})
+})
@@ -0,0 +1,16 @@
+//go:build go1.18
+// +build go1.18
+
+package request_test
+
+import (
+	"testing"
+
+	. "github.com/prebid/openrtb/v20/native1/request"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+)
+
+func FuzzRequest(f *testing.F) {
+	quickjson.Fuzz(f, "testdata/*/*.json", func() interface{} { return new(Request) })
+}
@@ -0,0 +1,32 @@
+package request_test
+
+import (
+	"math/rand"
+	"reflect"
+
+	. "github.com/prebid/openrtb/v20/native1/request"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON round-trip", func() {
+	DescribeTable(
+		"of random values",
+
+		func(subject interface{}) {
+			rnd := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 200; i++ {
+				v := quickjson.New(rnd, reflect.TypeOf(subject).Elem()).Addr().Interface()
+				actual, err := quickjson.RoundTrip(v)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(v))
+			}
+		},
+
+		Entry("Request", new(Request)),
+	)
+})
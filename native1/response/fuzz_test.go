@@ -0,0 +1,16 @@
+//go:build go1.18
+// +build go1.18
+
+package response_test
+
+import (
+	"testing"
+
+	. "github.com/prebid/openrtb/v20/native1/response"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+)
+
+func FuzzResponse(f *testing.F) {
+	quickjson.Fuzz(f, "testdata/*/*.json", func() interface{} { return new(Response) })
+}
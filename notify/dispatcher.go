@@ -0,0 +1,301 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Default dispatcher parameters.
+const (
+	DefaultWorkers   = 8
+	DefaultQueueSize = 1024
+	DefaultBackoff   = 100 * time.Millisecond
+	DefaultTimeout   = time.Second
+	DefaultDedupeTTL = 10 * time.Minute
+	MaxMarkupSize    = 1 << 20
+)
+
+// Errors, returned by Dispatcher.
+var (
+	ErrDuplicate = errors.New("notify: duplicate notification")
+	ErrQueueFull = errors.New("notify: queue is full")
+	ErrClosed    = errors.New("notify: dispatcher is closed")
+)
+
+// StatusError is returned for notifications, answered with HTTP status other than 2xx.
+type StatusError struct {
+	StatusCode int
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("notify: unexpected status %d", e.StatusCode)
+}
+
+// Options configure Dispatcher.
+type Options struct {
+	// Workers is the number of notifications fired concurrently; zero means DefaultWorkers.
+	Workers int
+
+	// QueueSize is the number of notifications, awaiting dispatch; zero means DefaultQueueSize.
+	QueueSize int
+
+	// Retries is the number of retries of failed notifications (network errors, HTTP 429 and 5xx); zero means no retries.
+	Retries int
+
+	// Backoff is the delay before the first retry, doubled for every next one; zero means DefaultBackoff.
+	Backoff time.Duration
+
+	// Timeout of a single attempt; zero means DefaultTimeout.
+	Timeout time.Duration
+
+	// DedupeTTL is the time notifications are remembered for deduplication; zero means DefaultDedupeTTL.
+	DedupeTTL time.Duration
+
+	// Transport is used to fire notifications; nil means http.DefaultTransport.
+	Transport http.RoundTripper
+
+	// EncodePrice, if set, renders ${AUCTION_PRICE} (e.g., to encrypt it).
+	EncodePrice func(price float64) string
+
+	// OnMarkup, if set, is called with NURL response body, for win notifications of bids with empty AdM.
+	OnMarkup func(n *Notification, markup []byte)
+
+	// OnDone, if set, is called after notification is fired (with nil error) or has ultimately failed.
+	OnDone func(n *Notification, err error)
+}
+
+// Dispatcher fires notifications with a bounded pool of workers.
+//
+// Notifications are deduplicated by event, auction ID, seat ID and bid ID.
+type Dispatcher struct {
+	opts   Options
+	client *http.Client
+	queue  chan *Notification
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	closed bool
+	seen   map[dedupeKey]time.Time
+	swept  time.Time
+}
+
+type dedupeKey struct {
+	event     Event
+	auctionID string
+	seatID    string
+	bidID     string
+}
+
+// NewDispatcher returns Dispatcher with opts, starting its workers.
+func NewDispatcher(opts Options) *Dispatcher {
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultWorkers
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultQueueSize
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DefaultBackoff
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.DedupeTTL <= 0 {
+		opts.DedupeTTL = DefaultDedupeTTL
+	}
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+
+	d := &Dispatcher{
+		opts:   opts,
+		client: &http.Client{Transport: opts.Transport},
+		queue:  make(chan *Notification, opts.QueueSize),
+		seen:   make(map[dedupeKey]time.Time),
+		swept:  time.Now(),
+	}
+	d.ctx, d.cancel = context.WithCancel(context.Background())
+
+	for i := 0; i < opts.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Dispatch enqueues notification to be fired asynchronously.
+//
+// ErrDuplicate is returned for notifications, already dispatched within Options.DedupeTTL;
+// ErrQueueFull if the queue is full; ErrClosed after Close.
+func (d *Dispatcher) Dispatch(n Notification) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return ErrClosed
+	}
+	if !d.remember(&n) {
+		return ErrDuplicate
+	}
+
+	select {
+	case d.queue <- &n:
+		return nil
+	default:
+		d.forget(&n)
+		return ErrQueueFull
+	}
+}
+
+// DispatchAll enqueues notifications, returning the first error encountered (ErrDuplicate is ignored).
+func (d *Dispatcher) DispatchAll(ns []Notification) error {
+	var first error
+	for _, n := range ns {
+		if err := d.Dispatch(n); err != nil && err != ErrDuplicate && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// Fire fires notification synchronously (with retries), bypassing the queue and deduplication.
+//
+// For win notifications of bids with empty AdM, NURL response body is returned as markup.
+func (d *Dispatcher) Fire(ctx context.Context, n *Notification) ([]byte, error) {
+	url := n.Expand(d.opts.EncodePrice)
+	wantMarkup := n.Event == EventWin && n.Bid != nil && n.Bid.AdM == ""
+
+	backoff := d.opts.Backoff
+	for attempt := 0; ; attempt++ {
+		markup, retry, err := d.fire(ctx, url, wantMarkup)
+		if err == nil || !retry || attempt >= d.opts.Retries {
+			return markup, err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, err
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+// Close stops accepting notifications and waits for the queued ones to be fired.
+// Pending retries are abandoned if ctx is done first.
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for n := range d.queue {
+		markup, err := d.Fire(d.ctx, n)
+		if len(markup) != 0 && d.opts.OnMarkup != nil {
+			d.opts.OnMarkup(n, markup)
+		}
+		if d.opts.OnDone != nil {
+			d.opts.OnDone(n, err)
+		}
+	}
+}
+
+// fire makes a single attempt, reporting whether it may be retried on failure.
+func (d *Dispatcher) fire(ctx context.Context, url string, wantMarkup bool) ([]byte, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+		retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		return nil, retry, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	if !wantMarkup || resp.StatusCode == http.StatusNoContent {
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+		return nil, false, nil
+	}
+	markup, err := ioutil.ReadAll(io.LimitReader(resp.Body, MaxMarkupSize))
+	if err != nil {
+		// notification was delivered, so it is not retried
+		return nil, false, err
+	}
+	return markup, false, nil
+}
+
+// remember records notification for deduplication, reporting whether it is new.
+// It must be called with d.mu held.
+func (d *Dispatcher) remember(n *Notification) bool {
+	now := time.Now()
+	if now.Sub(d.swept) > d.opts.DedupeTTL {
+		for k, t := range d.seen {
+			if now.Sub(t) > d.opts.DedupeTTL {
+				delete(d.seen, k)
+			}
+		}
+		d.swept = now
+	}
+
+	k := key(n)
+	if t, ok := d.seen[k]; ok && now.Sub(t) <= d.opts.DedupeTTL {
+		return false
+	}
+	d.seen[k] = now
+	return true
+}
+
+// forget removes notification from deduplication records.
+// It must be called with d.mu held.
+func (d *Dispatcher) forget(n *Notification) {
+	delete(d.seen, key(n))
+}
+
+func key(n *Notification) dedupeKey {
+	k := dedupeKey{event: n.Event, auctionID: n.Macros.AuctionID, seatID: n.Macros.SeatID, bidID: n.Macros.BidID}
+	if n.Bid != nil {
+		k.bidID = n.Bid.ID
+	}
+	return k
+}
@@ -0,0 +1,188 @@
+// Package notify provides a dispatcher of OpenRTB 2.x win (Bid.NURL), billing (Bid.BURL) and loss (Bid.LURL) notifications
+//
+// Substitution macros (e.g., ${AUCTION_PRICE}, ${AUCTION_LOSS}) are expanded before notifications are fired.
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb2.x/blob/main/2.6.md#44---substitution-macros-
+package notify
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/auction"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Event is a type of notification.
+type Event int8
+
+// Event options.
+const (
+	EventWin     Event = 1 // Bid.NURL
+	EventBilling Event = 2 // Bid.BURL
+	EventLoss    Event = 3 // Bid.LURL
+)
+
+// String implements fmt.Stringer.
+func (e Event) String() string {
+	switch e {
+	case EventWin:
+		return "win"
+	case EventBilling:
+		return "billing"
+	case EventLoss:
+		return "loss"
+	}
+	return "Event(" + strconv.Itoa(int(e)) + ")"
+}
+
+// Macros holds substitution macro values.
+//
+// Zero values (and empty strings) expand to zero-length strings,
+// as OpenRTB prescribes for values, the exchange chooses not to share.
+type Macros struct {
+	AuctionID  string              // ${AUCTION_ID}
+	BidID      string              // ${AUCTION_BID_ID}
+	ImpID      string              // ${AUCTION_IMP_ID}
+	SeatID     string              // ${AUCTION_SEAT_ID}
+	AdID       string              // ${AUCTION_AD_ID}
+	Price      float64             // ${AUCTION_PRICE}
+	Currency   string              // ${AUCTION_CURRENCY}
+	MBR        float64             // ${AUCTION_MBR}
+	Loss       openrtb3.LossReason // ${AUCTION_LOSS}; only expanded for loss notifications
+	MinToWin   float64             // ${AUCTION_MIN_TO_WIN}
+	Multiplier float64             // ${AUCTION_MULTIPLIER}
+	ImpTS      int64               // ${AUCTION_IMP_TS}, Unix milliseconds
+}
+
+// Notification is a single notification to be fired.
+type Notification struct {
+	// Event type.
+	Event Event
+
+	// URL with unexpanded macros.
+	URL string
+
+	// Macros to expand.
+	Macros Macros
+
+	// Bid the notification is about.
+	// For win notifications with empty Bid.AdM, NURL response body is treated as markup.
+	Bid *openrtb2.Bid
+}
+
+// Expand returns notification URL with expanded macros.
+// encodePrice, if not nil, is used to render ${AUCTION_PRICE} (e.g., to encrypt it).
+func (n *Notification) Expand(encodePrice func(float64) string) string {
+	m := &n.Macros
+	price := formatFloat(m.Price)
+	if encodePrice != nil && m.Price != 0 {
+		price = encodePrice(m.Price)
+	}
+	loss := ""
+	if n.Event == EventLoss {
+		loss = strconv.FormatInt(int64(m.Loss), 10)
+	}
+	impTS := ""
+	if m.ImpTS != 0 {
+		impTS = strconv.FormatInt(m.ImpTS, 10)
+	}
+
+	return strings.NewReplacer(
+		"${AUCTION_ID}", m.AuctionID,
+		"${AUCTION_BID_ID}", m.BidID,
+		"${AUCTION_IMP_ID}", m.ImpID,
+		"${AUCTION_SEAT_ID}", m.SeatID,
+		"${AUCTION_AD_ID}", m.AdID,
+		"${AUCTION_PRICE}", price,
+		"${AUCTION_CURRENCY}", m.Currency,
+		"${AUCTION_MBR}", formatFloat(m.MBR),
+		"${AUCTION_LOSS}", loss,
+		"${AUCTION_MIN_TO_WIN}", formatFloat(m.MinToWin),
+		"${AUCTION_MULTIPLIER}", formatFloat(m.Multiplier),
+		"${AUCTION_IMP_TS}", impTS,
+	).Replace(n.URL)
+}
+
+func formatFloat(v float64) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// FromAuction returns win notifications (for winners with NURL) and loss notifications (for losers with LURL) of auction result.
+//
+// Loss notifications carry the clearing price of the impression winner as ${AUCTION_MIN_TO_WIN}.
+// Billing notifications are to be built with Billing, once billable event occurs.
+func FromAuction(req *openrtb2.BidRequest, res *auction.Result) []Notification {
+	var ns []Notification
+	clearing := make(map[string]float64, len(res.Winners))
+
+	for i := range res.Winners {
+		w := &res.Winners[i]
+		clearing[w.Bid.ImpID] = w.Price
+		if w.Bid.NURL != "" {
+			ns = append(ns, Notification{
+				Event:  EventWin,
+				URL:    w.Bid.NURL,
+				Macros: macros(req, &w.Candidate, w.Price),
+				Bid:    w.Bid,
+			})
+		}
+	}
+
+	for i := range res.Losers {
+		l := &res.Losers[i]
+		if l.Bid.LURL == "" {
+			continue
+		}
+		m := macros(req, &l.Candidate, 0)
+		m.Loss = l.Reason
+		m.MinToWin = clearing[l.Bid.ImpID]
+		ns = append(ns, Notification{
+			Event:  EventLoss,
+			URL:    l.Bid.LURL,
+			Macros: m,
+			Bid:    l.Bid,
+		})
+	}
+
+	return ns
+}
+
+// Billing returns billing notification of auction winner; false is returned if winner has no BURL.
+func Billing(req *openrtb2.BidRequest, w *auction.Winner) (Notification, bool) {
+	if w.Bid.BURL == "" {
+		return Notification{}, false
+	}
+	return Notification{
+		Event:  EventBilling,
+		URL:    w.Bid.BURL,
+		Macros: macros(req, &w.Candidate, w.Price),
+		Bid:    w.Bid,
+	}, true
+}
+
+func macros(req *openrtb2.BidRequest, c *auction.Candidate, price float64) Macros {
+	m := Macros{
+		AuctionID: req.ID,
+		BidID:     c.Response.BidID,
+		ImpID:     c.Bid.ImpID,
+		SeatID:    c.Seat(),
+		AdID:      c.Bid.AdID,
+		Price:     price,
+		Currency:  c.Response.Cur,
+	}
+	if m.BidID == "" {
+		m.BidID = c.Bid.ID
+	}
+	if m.Currency == "" {
+		m.Currency = "USD"
+	}
+	if c.Imp != nil && c.Imp.Qty != nil {
+		m.Multiplier = c.Imp.Qty.Multiplier
+	}
+	return m
+}
@@ -0,0 +1,178 @@
+package notify_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/prebid/openrtb/v20/notify"
+
+	"github.com/prebid/openrtb/v20/auction"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notification", func() {
+	It("should expand macros", func() {
+		n := &Notification{
+			Event: EventLoss,
+			URL:   "https://example.com/l?a=${AUCTION_ID}&b=${AUCTION_BID_ID}&i=${AUCTION_IMP_ID}&s=${AUCTION_SEAT_ID}&p=${AUCTION_PRICE}&c=${AUCTION_CURRENCY}&r=${AUCTION_LOSS}&m=${AUCTION_MIN_TO_WIN}&x=${AUCTION_MBR}",
+			Macros: Macros{
+				AuctionID: "req",
+				BidID:     "bid",
+				ImpID:     "1",
+				SeatID:    "seat",
+				Currency:  "USD",
+				Loss:      openrtb3.LossLostToHigherBid,
+				MinToWin:  2.51,
+			},
+		}
+		Expect(n.Expand(nil)).To(Equal("https://example.com/l?a=req&b=bid&i=1&s=seat&p=&c=USD&r=102&m=2.51&x="))
+	})
+
+	It("should encode price", func() {
+		n := &Notification{Event: EventWin, URL: "https://example.com/w?p=${AUCTION_PRICE}&r=${AUCTION_LOSS}", Macros: Macros{Price: 1.5}}
+		Expect(n.Expand(nil)).To(Equal("https://example.com/w?p=1.5&r="))
+		Expect(n.Expand(func(float64) string { return "ENC" })).To(Equal("https://example.com/w?p=ENC&r="))
+	})
+})
+
+var _ = Describe("FromAuction", func() {
+	It("should build win and loss notifications", func() {
+		req := &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "1"}}, AT: 1}
+		resps := []*openrtb2.BidResponse{
+			{ID: "req", SeatBid: []openrtb2.SeatBid{{Seat: "a", Bid: []openrtb2.Bid{{ID: "a1", ImpID: "1", Price: 3, NURL: "win?p=${AUCTION_PRICE}", BURL: "bill?p=${AUCTION_PRICE}"}}}}},
+			{ID: "req", SeatBid: []openrtb2.SeatBid{{Seat: "b", Bid: []openrtb2.Bid{{ID: "b1", ImpID: "1", Price: 2, LURL: "loss?r=${AUCTION_LOSS}&m=${AUCTION_MIN_TO_WIN}"}}}}},
+		}
+		res := auction.Run(req, resps, auction.Options{})
+
+		ns := FromAuction(req, res)
+		Expect(ns).To(HaveLen(2))
+		Expect(ns[0].Event).To(Equal(EventWin))
+		Expect(ns[0].Expand(nil)).To(Equal("win?p=3"))
+		Expect(ns[1].Event).To(Equal(EventLoss))
+		Expect(ns[1].Expand(nil)).To(Equal("loss?r=102&m=3"))
+
+		b, ok := Billing(req, &res.Winners[0])
+		Expect(ok).To(BeTrue())
+		Expect(b.Event).To(Equal(EventBilling))
+		Expect(b.Expand(nil)).To(Equal("bill?p=3"))
+	})
+})
+
+var _ = Describe("Dispatcher", func() {
+	var (
+		srv  *httptest.Server
+		hits int32
+		h    http.HandlerFunc
+	)
+
+	BeforeEach(func() {
+		atomic.StoreInt32(&hits, 0)
+		h = func(w http.ResponseWriter, _ *http.Request) {}
+		srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			h(w, r)
+		}))
+	})
+
+	AfterEach(func() {
+		srv.Close()
+	})
+
+	It("should fire notifications and dedupe them", func() {
+		var (
+			mu   sync.Mutex
+			urls []string
+		)
+		h = func(_ http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			urls = append(urls, r.URL.RequestURI())
+			mu.Unlock()
+		}
+
+		d := NewDispatcher(Options{})
+		n := Notification{Event: EventWin, URL: srv.URL + "/win?p=${AUCTION_PRICE}", Macros: Macros{AuctionID: "req", BidID: "bid", Price: 1.25}}
+		Expect(d.Dispatch(n)).To(Succeed())
+		Expect(d.Dispatch(n)).To(MatchError(ErrDuplicate))
+
+		n.Event = EventBilling
+		Expect(d.Dispatch(n)).To(Succeed())
+
+		Expect(d.Close(context.Background())).To(Succeed())
+		Expect(d.Dispatch(n)).To(MatchError(ErrClosed))
+		Expect(urls).To(ConsistOf("/win?p=1.25", "/win?p=1.25"))
+	})
+
+	It("should retry with backoff", func() {
+		h = func(w http.ResponseWriter, _ *http.Request) {
+			if atomic.LoadInt32(&hits) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}
+
+		d := NewDispatcher(Options{Retries: 2, Backoff: 10 * time.Millisecond})
+		defer d.Close(context.Background())
+
+		start := time.Now()
+		_, err := d.Fire(context.Background(), &Notification{URL: srv.URL})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(atomic.LoadInt32(&hits)).To(BeEquivalentTo(3))
+		Expect(time.Since(start)).To(BeNumerically(">=", 30*time.Millisecond))
+	})
+
+	It("should not retry client errors", func() {
+		h = func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}
+
+		d := NewDispatcher(Options{Retries: 2, Backoff: time.Millisecond})
+		defer d.Close(context.Background())
+
+		_, err := d.Fire(context.Background(), &Notification{URL: srv.URL})
+		Expect(err).To(Equal(&StatusError{StatusCode: http.StatusNotFound}))
+		Expect(atomic.LoadInt32(&hits)).To(BeEquivalentTo(1))
+	})
+
+	It("should return NURL markup for bids without AdM", func() {
+		h = func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte("<VAST/>"))
+		}
+
+		markups := make(chan string, 2)
+		d := NewDispatcher(Options{OnMarkup: func(n *Notification, markup []byte) {
+			markups <- n.Bid.ID + ":" + string(markup)
+		}})
+
+		Expect(d.Dispatch(Notification{Event: EventWin, URL: srv.URL, Bid: &openrtb2.Bid{ID: "empty"}})).To(Succeed())
+		Expect(d.Dispatch(Notification{Event: EventWin, URL: srv.URL, Bid: &openrtb2.Bid{ID: "adm", AdM: "<div/>"}})).To(Succeed())
+		Expect(d.Close(context.Background())).To(Succeed())
+
+		close(markups)
+		Expect(markups).To(Receive(Equal("empty:<VAST/>")))
+		Expect(markups).NotTo(Receive())
+	})
+
+	It("should use custom transport", func() {
+		var rt roundTripper = func(r *http.Request) (*http.Response, error) {
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: r}, nil
+		}
+		d := NewDispatcher(Options{Transport: rt})
+		defer d.Close(context.Background())
+
+		_, err := d.Fire(context.Background(), &Notification{URL: "https://example.com/"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
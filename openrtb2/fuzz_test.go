@@ -0,0 +1,20 @@
+//go:build go1.18
+// +build go1.18
+
+package openrtb2_test
+
+import (
+	"testing"
+
+	. "github.com/prebid/openrtb/v20/openrtb2"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+)
+
+func FuzzBidRequest(f *testing.F) {
+	quickjson.Fuzz(f, "testdata/bid-request/*/*.json", func() interface{} { return new(BidRequest) })
+}
+
+func FuzzBidResponse(f *testing.F) {
+	quickjson.Fuzz(f, "testdata/bid-response/*/*.json", func() interface{} { return new(BidResponse) })
+}
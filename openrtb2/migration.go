@@ -0,0 +1,357 @@
+package openrtb2
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Conflict describes a value, present both in a first-class OpenRTB 2.6 field and in its OpenRTB 2.5 ext counterpart,
+// with the two values being different, or a restricting OpenRTB 2.6 field, dropped by Downgrade26To25 (with empty Ext).
+//
+// Migration always resolves conflicts in favour of the first-class field.
+type Conflict struct {
+	// Field is JSON path of the first-class field (e.g., "regs.gdpr").
+	Field string
+
+	// Ext is JSON path of the ext counterpart (e.g., "regs.ext.gdpr"); empty, if the field has none.
+	Ext string
+}
+
+// String implements fmt.Stringer.
+func (c Conflict) String() string {
+	if c.Ext == "" {
+		return c.Field + " is dropped"
+	}
+	return c.Field + " conflicts with " + c.Ext
+}
+
+// Upgrade25To26 moves OpenRTB 2.5 ext fields of r into their OpenRTB 2.6 first-class counterparts, in place:
+//
+//	regs.ext.gdpr       -> regs.gdpr
+//	regs.ext.us_privacy -> regs.us_privacy
+//	regs.ext.gpp        -> regs.gpp
+//	regs.ext.gpp_sid    -> regs.gpp_sid
+//	user.ext.consent    -> user.consent
+//	user.ext.eids       -> user.eids
+//	source.ext.schain   -> source.schain
+//
+// If first-class field is already set, it is kept (ext value is dropped), and a Conflict is reported unless both values are equal.
+// imp.ext.skadn is left intact, as OpenRTB 2.6 has no first-class field for it.
+//
+// Error is returned if ext is not a JSON object, or its value can't be decoded into the first-class field;
+// r may be partially migrated in this case.
+func Upgrade25To26(r *BidRequest) ([]Conflict, error) {
+	m := new(migrator)
+
+	if regs := r.Regs; regs != nil {
+		err := m.ext(&regs.Ext, "regs.ext", func(ext map[string]json.RawMessage) error {
+			if err := m.up(ext, "gdpr", &regs.GDPR, regs.GDPR != nil, "regs"); err != nil {
+				return err
+			}
+			if err := m.up(ext, "us_privacy", &regs.USPrivacy, regs.USPrivacy != "", "regs"); err != nil {
+				return err
+			}
+			if err := m.up(ext, "gpp", &regs.GPP, regs.GPP != "", "regs"); err != nil {
+				return err
+			}
+			return m.up(ext, "gpp_sid", &regs.GPPSID, len(regs.GPPSID) != 0, "regs")
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+	}
+
+	if user := r.User; user != nil {
+		err := m.ext(&user.Ext, "user.ext", func(ext map[string]json.RawMessage) error {
+			if err := m.up(ext, "consent", &user.Consent, user.Consent != "", "user"); err != nil {
+				return err
+			}
+			return m.up(ext, "eids", &user.EIDs, len(user.EIDs) != 0, "user")
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+	}
+
+	if source := r.Source; source != nil {
+		err := m.ext(&source.Ext, "source.ext", func(ext map[string]json.RawMessage) error {
+			return m.up(ext, "schain", &source.SChain, source.SChain != nil, "source")
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+	}
+
+	return m.conflicts, nil
+}
+
+// Downgrade26To25 converts r into OpenRTB 2.5 form, in place.
+//
+// First-class fields, listed by Upgrade25To26, are moved back into ext
+// (a Conflict is reported if ext already holds a different value, which gets overwritten).
+// Other fields, introduced by OpenRTB 2.6, are removed:
+//
+//	bidrequest: dooh, wlangb, cattax, acat
+//	imp: rwdd, ssai, qty, dt, refresh
+//	video: maxseq, poddur, podid, podseq, rqddurs, slotinpod, mincpmpersec, plcmt, poddedupe, durfloors
+//	audio: poddur, podid, podseq, rqddurs, slotinpod, mincpmpersec, durfloors
+//	deal: guar, mincpmpersec, durfloors
+//	site, app: cattax, kwarray, inventorypartnerdomain
+//	publisher, producer: cattax
+//	content: cattax, kwarray, langb, network, channel
+//	device: sua, langb
+//	user: kwarray
+//
+// Removal of acat (allowed categories) lifts a restriction, so a Conflict (without Ext) is reported if it is set.
+//
+// Error is returned if ext is not a JSON object; r may be partially migrated in this case.
+func Downgrade26To25(r *BidRequest) ([]Conflict, error) {
+	m := new(migrator)
+
+	if regs := r.Regs; regs != nil {
+		err := m.ext(&regs.Ext, "regs.ext", func(ext map[string]json.RawMessage) error {
+			if regs.GDPR != nil {
+				if err := m.down(ext, "gdpr", regs.GDPR, "regs"); err != nil {
+					return err
+				}
+				regs.GDPR = nil
+			}
+			if regs.USPrivacy != "" {
+				if err := m.down(ext, "us_privacy", regs.USPrivacy, "regs"); err != nil {
+					return err
+				}
+				regs.USPrivacy = ""
+			}
+			if regs.GPP != "" {
+				if err := m.down(ext, "gpp", regs.GPP, "regs"); err != nil {
+					return err
+				}
+				regs.GPP = ""
+			}
+			if len(regs.GPPSID) != 0 {
+				if err := m.down(ext, "gpp_sid", regs.GPPSID, "regs"); err != nil {
+					return err
+				}
+				regs.GPPSID = nil
+			}
+			return nil
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+	}
+
+	if user := r.User; user != nil {
+		err := m.ext(&user.Ext, "user.ext", func(ext map[string]json.RawMessage) error {
+			if user.Consent != "" {
+				if err := m.down(ext, "consent", user.Consent, "user"); err != nil {
+					return err
+				}
+				user.Consent = ""
+			}
+			if len(user.EIDs) != 0 {
+				if err := m.down(ext, "eids", user.EIDs, "user"); err != nil {
+					return err
+				}
+				user.EIDs = nil
+			}
+			return nil
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+		user.KwArray = nil
+	}
+
+	if source := r.Source; source != nil && source.SChain != nil {
+		err := m.ext(&source.Ext, "source.ext", func(ext map[string]json.RawMessage) error {
+			if err := m.down(ext, "schain", source.SChain, "source"); err != nil {
+				return err
+			}
+			source.SChain = nil
+			return nil
+		})
+		if err != nil {
+			return m.conflicts, err
+		}
+	}
+
+	r.DOOH = nil
+	r.WLangB = nil
+	r.CatTax = 0
+	if len(r.ACat) != 0 {
+		m.conflicts = append(m.conflicts, Conflict{Field: "acat"})
+		r.ACat = nil
+	}
+
+	for i := range r.Imp {
+		downgradeImp(&r.Imp[i])
+	}
+	if site := r.Site; site != nil {
+		site.CatTax = 0
+		site.KwArray = nil
+		site.InventoryPartnerDomain = ""
+		downgradePublisher(site.Publisher)
+		downgradeContent(site.Content)
+	}
+	if app := r.App; app != nil {
+		app.CatTax = 0
+		app.KwArray = nil
+		app.InventoryPartnerDomain = ""
+		downgradePublisher(app.Publisher)
+		downgradeContent(app.Content)
+	}
+	if device := r.Device; device != nil {
+		device.SUA = nil
+		device.LangB = ""
+	}
+
+	return m.conflicts, nil
+}
+
+func downgradeImp(imp *Imp) {
+	imp.Rwdd = 0
+	imp.SSAI = 0
+	imp.Qty = nil
+	imp.DT = 0
+	imp.Refresh = nil
+
+	if video := imp.Video; video != nil {
+		video.MaxSeq = 0
+		video.PodDur = 0
+		video.PodID = ""
+		video.PodSeq = 0
+		video.RqdDurs = nil
+		video.SlotInPod = 0
+		video.MinCPMPerSec = 0
+		video.Plcmt = 0
+		video.PodDedupe = nil
+		video.DurFloors = nil
+	}
+	if audio := imp.Audio; audio != nil {
+		audio.PodDur = 0
+		audio.PodID = ""
+		audio.PodSeq = 0
+		audio.RqdDurs = nil
+		audio.SlotInPod = 0
+		audio.MinCPMPerSec = 0
+		audio.DurFloors = nil
+	}
+	if pmp := imp.PMP; pmp != nil {
+		for i := range pmp.Deals {
+			deal := &pmp.Deals[i]
+			deal.Guar = 0
+			deal.MinCPMPerSec = 0
+			deal.DurFloors = nil
+		}
+	}
+}
+
+func downgradePublisher(publisher *Publisher) {
+	if publisher != nil {
+		publisher.CatTax = 0
+	}
+}
+
+func downgradeContent(content *Content) {
+	if content == nil {
+		return
+	}
+	content.CatTax = 0
+	content.KwArray = nil
+	content.LangB = ""
+	content.Network = nil
+	content.Channel = nil
+	if content.Producer != nil {
+		content.Producer.CatTax = 0
+	}
+}
+
+// migrator accumulates conflicts, encountered while moving values between first-class fields and ext.
+type migrator struct {
+	conflicts []Conflict
+	dirty     bool
+}
+
+// ext decodes ext object at path, passes it to fn and encodes it back if modified (dropping it, if it becomes empty).
+func (m *migrator) ext(raw *json.RawMessage, path string, fn func(map[string]json.RawMessage) error) error {
+	var ext map[string]json.RawMessage
+	if len(*raw) != 0 {
+		if err := json.Unmarshal(*raw, &ext); err != nil {
+			return fmt.Errorf("openrtb2: %s: %w", path, err)
+		}
+	}
+	if ext == nil {
+		ext = make(map[string]json.RawMessage)
+	}
+
+	m.dirty = false
+	if err := fn(ext); err != nil {
+		return err
+	}
+
+	if !m.dirty {
+		return nil
+	}
+	if len(ext) == 0 {
+		*raw = nil
+		return nil
+	}
+	buf, err := json.Marshal(ext)
+	if err != nil {
+		return fmt.Errorf("openrtb2: %s: %w", path, err)
+	}
+	*raw = buf
+	return nil
+}
+
+// up moves ext[key] into first-class field dst (a pointer), unless it is already set.
+func (m *migrator) up(ext map[string]json.RawMessage, key string, dst interface{}, set bool, parent string) error {
+	raw, ok := ext[key]
+	if !ok {
+		return nil
+	}
+	delete(ext, key)
+	m.dirty = true
+
+	if set {
+		if !sameJSON(dst, raw) {
+			m.conflicts = append(m.conflicts, Conflict{Field: parent + "." + key, Ext: parent + ".ext." + key})
+		}
+		return nil
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return fmt.Errorf("openrtb2: %s.ext.%s: %w", parent, key, err)
+	}
+	return nil
+}
+
+// down moves first-class field value src into ext[key], overwriting it.
+func (m *migrator) down(ext map[string]json.RawMessage, key string, src interface{}, parent string) error {
+	if raw, ok := ext[key]; ok && !sameJSON(src, raw) {
+		m.conflicts = append(m.conflicts, Conflict{Field: parent + "." + key, Ext: parent + ".ext." + key})
+	}
+
+	buf, err := json.Marshal(src)
+	if err != nil {
+		return fmt.Errorf("openrtb2: %s.%s: %w", parent, key, err)
+	}
+	ext[key] = buf
+	m.dirty = true
+	return nil
+}
+
+// sameJSON reports whether v encodes to JSON, equivalent to raw (ignoring whitespace and object key order).
+func sameJSON(v interface{}, raw json.RawMessage) bool {
+	buf, err := json.Marshal(v)
+	if err != nil {
+		return false
+	}
+
+	var a, b interface{}
+	if json.Unmarshal(buf, &a) != nil || json.Unmarshal(raw, &b) != nil {
+		return false
+	}
+	return reflect.DeepEqual(a, b)
+}
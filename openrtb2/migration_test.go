@@ -0,0 +1,154 @@
+package openrtb2_test
+
+import (
+	"encoding/json"
+
+	. "github.com/prebid/openrtb/v20/openrtb2"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Upgrade25To26", func() {
+	It("should move ext fields into first-class fields", func() {
+		r := &BidRequest{
+			ID:  "req",
+			Imp: []Imp{{ID: "1", Ext: json.RawMessage(`{"skadn":{"version":"2.0"}}`)}},
+			Regs: &Regs{
+				Ext: json.RawMessage(`{"gdpr":1,"us_privacy":"1YNN","custom":true}`),
+			},
+			User: &User{
+				Ext: json.RawMessage(`{"consent":"CONSENT","eids":[{"source":"example.com","uids":[{"id":"uid"}]}]}`),
+			},
+			Source: &Source{
+				Ext: json.RawMessage(`{"schain":{"complete":1,"ver":"1.0","nodes":[{"asi":"example.com","sid":"1","hp":1}]}}`),
+			},
+		}
+
+		conflicts, err := Upgrade25To26(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+
+		Expect(r.Regs.GDPR).To(Equal(Int8Ptr(1)))
+		Expect(r.Regs.USPrivacy).To(Equal("1YNN"))
+		Expect(r.Regs.Ext).To(MatchJSON(`{"custom":true}`))
+		Expect(r.User.Consent).To(Equal("CONSENT"))
+		Expect(r.User.EIDs).To(Equal([]EID{{Source: "example.com", UIDs: []UID{{ID: "uid"}}}}))
+		Expect(r.User.Ext).To(BeNil())
+		Expect(r.Source.SChain).To(Equal(&SupplyChain{
+			Complete: 1,
+			Ver:      "1.0",
+			Nodes:    []SupplyChainNode{{ASI: "example.com", SID: "1", HP: Int8Ptr(1)}},
+		}))
+		Expect(r.Source.Ext).To(BeNil())
+		Expect(r.Imp[0].Ext).To(MatchJSON(`{"skadn":{"version":"2.0"}}`))
+	})
+
+	It("should keep first-class fields and report conflicts", func() {
+		r := &BidRequest{
+			Regs: &Regs{
+				GDPR:      Int8Ptr(0),
+				USPrivacy: "1YNN",
+				Ext:       json.RawMessage(`{"gdpr":1,"us_privacy":"1YNN"}`),
+			},
+		}
+
+		conflicts, err := Upgrade25To26(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal([]Conflict{{Field: "regs.gdpr", Ext: "regs.ext.gdpr"}}))
+		Expect(r.Regs.GDPR).To(Equal(Int8Ptr(0)))
+		Expect(r.Regs.Ext).To(BeNil())
+	})
+
+	It("should leave untouched ext as is", func() {
+		r := &BidRequest{User: &User{Ext: json.RawMessage(`{ "b": 1, "a": 2 }`)}}
+
+		_, err := Upgrade25To26(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(r.User.Ext)).To(Equal(`{ "b": 1, "a": 2 }`))
+	})
+
+	It("should fail on malformed ext", func() {
+		_, err := Upgrade25To26(&BidRequest{Regs: &Regs{Ext: json.RawMessage(`{"gdpr":"yes"}`)}})
+		Expect(err).To(MatchError(ContainSubstring("regs.ext.gdpr")))
+
+		_, err = Upgrade25To26(&BidRequest{Regs: &Regs{Ext: json.RawMessage(`[]`)}})
+		Expect(err).To(MatchError(ContainSubstring("regs.ext")))
+	})
+})
+
+var _ = Describe("Downgrade26To25", func() {
+	It("should move first-class fields into ext and strip 2.6 fields", func() {
+		r := &BidRequest{
+			ID:     "req",
+			CatTax: adcom1.CatTaxIABContent20,
+			ACat:   []string{"IAB1"},
+			WLangB: []string{"en"},
+			DOOH:   &DOOH{ID: "dooh"},
+			Imp: []Imp{{
+				ID:      "1",
+				Rwdd:    1,
+				Qty:     &Qty{Multiplier: 2},
+				Refresh: &Refresh{Count: intPtr(1)},
+				Video:   &Video{MIMEs: []string{"video/mp4"}, PodID: "pod", Plcmt: adcom1.VideoPlcmtInstream},
+				PMP:     &PMP{Deals: []Deal{{ID: "deal", Guar: 1}}},
+			}},
+			Site: &Site{
+				ID:      "site",
+				KwArray: []string{"kw"},
+				Content: &Content{ID: "content", Network: &Network{ID: "net"}},
+			},
+			Device: &Device{UA: "ua", SUA: &UserAgent{Model: "model"}},
+			Regs: &Regs{
+				GDPR:      Int8Ptr(1),
+				USPrivacy: "1YNN",
+				Ext:       json.RawMessage(`{"gdpr":0}`),
+			},
+			User:   &User{Consent: "CONSENT"},
+			Source: &Source{SChain: &SupplyChain{Complete: 1, Ver: "1.0"}},
+		}
+
+		conflicts, err := Downgrade26To25(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(Equal([]Conflict{{Field: "regs.gdpr", Ext: "regs.ext.gdpr"}, {Field: "acat"}}))
+		Expect(conflicts[1].String()).To(Equal("acat is dropped"))
+
+		buf, err := json.Marshal(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf).To(MatchJSON(`{
+			"id": "req",
+			"imp": [{"id": "1", "video": {"mimes": ["video/mp4"]}, "pmp": {"deals": [{"id": "deal"}]}}],
+			"site": {"id": "site", "content": {"id": "content"}},
+			"device": {"ua": "ua"},
+			"regs": {"ext": {"gdpr": 1, "us_privacy": "1YNN"}},
+			"user": {"ext": {"consent": "CONSENT"}},
+			"source": {"ext": {"schain": {"complete": 1, "nodes": null, "ver": "1.0"}}}
+		}`))
+	})
+
+	It("should round-trip with Upgrade25To26", func() {
+		r := &BidRequest{
+			Regs: &Regs{GDPR: Int8Ptr(1), GPP: "DBABLA~BVVqAAEABCA.QA", GPPSID: []int8{7}},
+			User: &User{EIDs: []EID{{Source: "example.com", UIDs: []UID{{ID: "uid"}}}}},
+		}
+		exp := &BidRequest{
+			Regs: &Regs{GDPR: Int8Ptr(1), GPP: "DBABLA~BVVqAAEABCA.QA", GPPSID: []int8{7}},
+			User: &User{EIDs: []EID{{Source: "example.com", UIDs: []UID{{ID: "uid"}}}}},
+		}
+
+		_, err := Downgrade26To25(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Regs.GDPR).To(BeNil())
+
+		conflicts, err := Upgrade25To26(r)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(conflicts).To(BeEmpty())
+		Expect(r).To(Equal(exp))
+	})
+})
+
+func intPtr(n int) *int {
+	return &n
+}
@@ -9,3 +9,8 @@ func Int8Ptr(n int8) *int8 {
 func Int64Ptr(n int64) *int64 {
 	return &n
 }
+
+// Float64Ptr returns pointer to passed argument.
+func Float64Ptr(n float64) *float64 {
+	return &n
+}
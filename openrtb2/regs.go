@@ -16,7 +16,7 @@ type Regs struct {
 	//   Flag indicating if this request is subject to the COPPA
 	//   regulations established by the USA FTC, where 0 = no, 1 = yes.
 	//   Refer to Section 7.5 for more information.
-	COPPA int8 `json:"coppa,omitempty"`
+	COPPA *int8 `json:"coppa,omitempty"`
 
 	// Attribute:
 	//   gdpr
@@ -0,0 +1,52 @@
+package openrtb2_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+
+	. "github.com/prebid/openrtb/v20/openrtb2"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON round-trip", func() {
+	DescribeTable(
+		"of random values",
+
+		func(subject interface{}) {
+			rnd := rand.New(rand.NewSource(GinkgoRandomSeed()))
+			for i := 0; i < 200; i++ {
+				v := quickjson.New(rnd, reflect.TypeOf(subject).Elem()).Addr().Interface()
+				actual, err := quickjson.RoundTrip(v)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(actual).To(Equal(v))
+			}
+		},
+
+		Entry("BidRequest", new(BidRequest)),
+		Entry("BidResponse", new(BidResponse)),
+	)
+
+	DescribeTable(
+		"of pointers to zero values",
+
+		func(subject interface{}, expected string) {
+			actual, err := quickjson.RoundTrip(subject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(Equal(subject))
+			Expect(json.Marshal(subject)).To(MatchJSON(expected))
+		},
+
+		Entry("Video.Skip", &Video{Skip: Int8Ptr(0)}, `{"mimes":null,"skip":0}`),
+		Entry("Regs.GDPR", &Regs{GDPR: Int8Ptr(0)}, `{"gdpr":0}`),
+		Entry("Regs.COPPA", &Regs{COPPA: Int8Ptr(0)}, `{"coppa":0}`),
+		Entry("Imp.BidFloor", &Imp{BidFloor: Float64Ptr(0)}, `{"id":"","bidfloor":0}`),
+		Entry("Device.Lmt", &Device{Lmt: Int8Ptr(0)}, `{"lmt":0}`),
+		Entry("Banner.W", &Banner{W: Int64Ptr(0)}, `{"w":0}`),
+	)
+})
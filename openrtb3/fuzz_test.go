@@ -0,0 +1,16 @@
+//go:build go1.18
+// +build go1.18
+
+package openrtb3_test
+
+import (
+	"testing"
+
+	. "github.com/prebid/openrtb/v20/openrtb3"
+
+	"github.com/prebid/openrtb/v20/internal/quickjson"
+)
+
+func FuzzBody(f *testing.F) {
+	quickjson.Fuzz(f, "testdata/*.json", func() interface{} { return new(Body) })
+}
@@ -0,0 +1,360 @@
+package openrtbpb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+type encoder struct {
+	opts MarshalOptions
+}
+
+func (e *encoder) message(b []byte, v reflect.Value) ([]byte, error) {
+	m := messages[v.Type()]
+
+	nf, native := nativeFields[v.Type()]
+	native = native && e.opts.NativeMessages
+
+	var (
+		ext map[string]json.RawMessage
+		nb  []byte // native message alternative
+	)
+	for i := range m.fields {
+		f := &m.fields[i]
+		fv := v.Field(f.index)
+		if fv.IsZero() && !f.required {
+			continue
+		}
+
+		if f.num == 0 {
+			raw, err := json.Marshal(fv.Interface())
+			if err != nil {
+				return nil, err
+			}
+			if ext == nil {
+				ext = make(map[string]json.RawMessage)
+			}
+			ext[f.name] = raw
+			continue
+		}
+
+		if native && f.name == nf.name {
+			if nv, ok := nativeMarkup(fv.String(), nf.typ); ok {
+				sub, err := e.message(nil, nv)
+				if err != nil {
+					return nil, err
+				}
+				// The message alternative is emitted in its own field number order.
+				nb = appendTag(nil, nf.num, wireBytes)
+				nb = appendBytes(nb, sub)
+				continue
+			}
+		}
+
+		if nb != nil && f.num > nf.num {
+			b, nb = append(b, nb...), nil
+		}
+		var err error
+		if b, err = e.field(b, f, fv); err != nil {
+			return nil, err
+		}
+	}
+	b = append(b, nb...)
+
+	if m.ext != -1 {
+		if raw := v.Field(m.ext).Bytes(); len(raw) != 0 {
+			if ext == nil {
+				ext = make(map[string]json.RawMessage)
+			}
+			ext["ext"] = json.RawMessage(raw)
+		}
+	}
+	if ext != nil {
+		raw, err := json.Marshal(ext)
+		if err != nil {
+			return nil, err
+		}
+		b = appendTag(b, ExtFieldNumber, wireBytes)
+		b = appendBytes(b, raw)
+	}
+	return b, nil
+}
+
+func (e *encoder) field(b []byte, f *field, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	num := f.num
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b = appendTag(b, num, wireBytes)
+			return appendBytes(b, v.Bytes()), nil
+		}
+		if f.packed && isScalar(v.Type().Elem()) {
+			var packed []byte
+			for i := 0; i < v.Len(); i++ {
+				packed = appendScalar(packed, v.Index(i))
+			}
+			b = appendTag(b, num, wireBytes)
+			return appendBytes(b, packed), nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			if b, err = e.field(b, f, v.Index(i)); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case reflect.String:
+		b = appendTag(b, num, wireBytes)
+		return appendBytes(b, []byte(v.String())), nil
+	case reflect.Struct:
+		sub, err := e.message(nil, v)
+		if err != nil {
+			return nil, err
+		}
+		b = appendTag(b, num, wireBytes)
+		return appendBytes(b, sub), nil
+	case reflect.Float32:
+		b = appendTag(b, num, wireFixed32)
+		return appendScalar(b, v), nil
+	case reflect.Float64:
+		b = appendTag(b, num, wireFixed64)
+		return appendScalar(b, v), nil
+	}
+	b = appendTag(b, num, wireVarint)
+	return appendScalar(b, v), nil
+}
+
+func isScalar(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	}
+	return false
+}
+
+// appendScalar appends scalar value without tag.
+func appendScalar(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return appendVarint(b, 1)
+		}
+		return appendVarint(b, 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return appendVarint(b, uint64(v.Int()))
+	case reflect.Float32:
+		var buf [4]byte
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(float32(v.Float())))
+		return append(b, buf[:]...)
+	case reflect.Float64:
+		return appendFixed64(b, math.Float64bits(v.Float()))
+	}
+	return appendVarint(b, v.Uint())
+}
+
+// nativeMarkup parses native markup (JSON) into a value of typ,
+// reporting whether it can be carried as a message without loss.
+func nativeMarkup(markup string, typ reflect.Type) (reflect.Value, bool) {
+	ptr := reflect.New(typ)
+	dec := json.NewDecoder(bytes.NewReader([]byte(markup)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(ptr.Interface()); err != nil || dec.More() {
+		return reflect.Value{}, false
+	}
+
+	raw, err := json.Marshal(ptr.Interface())
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	var original, marshaled interface{}
+	if json.Unmarshal([]byte(markup), &original) != nil || json.Unmarshal(raw, &marshaled) != nil {
+		return reflect.Value{}, false
+	}
+	return ptr.Elem(), reflect.DeepEqual(original, marshaled)
+}
+
+func decodeMessage(b []byte, v reflect.Value) error {
+	m := messages[v.Type()]
+	nf, native := nativeFields[v.Type()]
+
+	for len(b) != 0 {
+		num, typ, x, payload, n, err := consumeField(b)
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+
+		switch {
+		case num == ExtFieldNumber && typ == wireBytes:
+			if err := decodeExt(payload, v, m); err != nil {
+				return err
+			}
+		case native && num == nf.num && typ == wireBytes:
+			nv := reflect.New(nf.typ)
+			if err := decodeMessage(payload, nv.Elem()); err != nil {
+				return err
+			}
+			raw, err := json.Marshal(nv.Interface())
+			if err != nil {
+				return err
+			}
+			v.Field(m.byName[nf.name].index).SetString(string(raw))
+		default:
+			f, ok := m.byNum[num]
+			if !ok {
+				continue // unknown field
+			}
+			if err := decodeField(v.Field(f.index), typ, x, payload); err != nil {
+				return fmt.Errorf("%s: %w", f.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func decodeExt(b []byte, v reflect.Value, m *message) error {
+	var ext map[string]json.RawMessage
+	if err := json.Unmarshal(b, &ext); err != nil {
+		return fmt.Errorf("openrtbpb: malformed JSON extension: %w", err)
+	}
+	for name, raw := range ext {
+		if name == "ext" && m.ext != -1 {
+			v.Field(m.ext).SetBytes(append([]byte(nil), raw...))
+			continue
+		}
+		f, ok := m.byName[name]
+		if !ok || f.num != 0 {
+			continue // unknown field
+		}
+		if err := json.Unmarshal(raw, v.Field(f.index).Addr().Interface()); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+func decodeField(v reflect.Value, typ int, x uint64, payload []byte) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		et := v.Type().Elem()
+		if et.Kind() == reflect.Uint8 {
+			if typ != wireBytes {
+				return errWireType
+			}
+			v.SetBytes(append([]byte(nil), payload...))
+			return nil
+		}
+		if isScalar(et) && typ == wireBytes {
+			return decodePacked(v, payload)
+		}
+		v.Set(reflect.Append(v, reflect.Zero(et)))
+		return decodeField(v.Index(v.Len()-1), typ, x, payload)
+	case reflect.String:
+		if typ != wireBytes {
+			return errWireType
+		}
+		v.SetString(string(payload))
+		return nil
+	case reflect.Struct:
+		if typ != wireBytes {
+			return errWireType
+		}
+		return decodeMessage(payload, v)
+	}
+	return setScalar(v, typ, x)
+}
+
+func decodePacked(v reflect.Value, b []byte) error {
+	et := v.Type().Elem()
+	typ := wireVarint
+	switch et.Kind() {
+	case reflect.Float32:
+		typ = wireFixed32
+	case reflect.Float64:
+		typ = wireFixed64
+	}
+
+	for len(b) != 0 {
+		var (
+			x uint64
+			n int
+		)
+		switch typ {
+		case wireFixed32:
+			if len(b) < 4 {
+				return errTruncated
+			}
+			x, n = uint64(binary.LittleEndian.Uint32(b)), 4
+		case wireFixed64:
+			if len(b) < 8 {
+				return errTruncated
+			}
+			x, n = binary.LittleEndian.Uint64(b), 8
+		default:
+			var err error
+			if x, n, err = consumeVarint(b); err != nil {
+				return err
+			}
+		}
+		b = b[n:]
+
+		ev := reflect.New(et).Elem()
+		if err := setScalar(ev, typ, x); err != nil {
+			return err
+		}
+		v.Set(reflect.Append(v, ev))
+	}
+	return nil
+}
+
+func setScalar(v reflect.Value, typ int, x uint64) error {
+	switch v.Kind() {
+	case reflect.Float32:
+		if typ != wireFixed32 {
+			return errWireType
+		}
+		v.SetFloat(float64(math.Float32frombits(uint32(x))))
+	case reflect.Float64:
+		if typ != wireFixed64 {
+			return errWireType
+		}
+		v.SetFloat(math.Float64frombits(x))
+	case reflect.Bool:
+		if typ != wireVarint {
+			return errWireType
+		}
+		v.SetBool(x != 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if typ != wireVarint {
+			return errWireType
+		}
+		v.SetInt(int64(x))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if typ != wireVarint {
+			return errWireType
+		}
+		v.SetUint(x)
+	default:
+		return errors.New("openrtbpb: unsupported type " + v.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+// Package openrtbpb provides Protocol Buffers encoding of OpenRTB 2.x bid requests and responses (including Native 1.x markup),
+// wire-compatible with openrtb.proto of the IAB/Google OpenRTB library
+//
+// Field numbers are taken from the embedded openrtb.proto.
+// Ext objects, as well as fields without proto counterpart (e.g., introduced by OpenRTB 2.6),
+// are carried as a single JSON object by bytes extension ExtFieldNumber of each message.
+//
+// https://github.com/google/openrtb/blob/master/openrtb-core/src/main/protobuf/openrtb.proto
+package openrtbpb
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ExtFieldNumber is the number of bytes extension field,
+// carrying Ext and fields without proto counterpart as a JSON object (e.g., {"ext":{...},"sua":{...}}).
+const ExtFieldNumber = 9999
+
+// ErrUnsupportedType is returned for values of types without proto counterpart.
+var ErrUnsupportedType = errors.New("openrtbpb: unsupported type")
+
+// MarshalOptions configure Marshal.
+type MarshalOptions struct {
+	// NativeMessages enables encoding of Native.Request and Bid.AdM as NativeRequest and NativeResponse messages
+	// (request_native and adm_native), instead of JSON strings.
+	// Only markup, which is valid Native 1.2 JSON without unknown fields, is encoded as message;
+	// other markup (e.g., HTML or Native 1.0 with "native" wrapper) is kept as a string.
+	NativeMessages bool
+}
+
+// Marshal returns protobuf encoding of v with default options.
+// See MarshalOptions.Marshal.
+func Marshal(v interface{}) ([]byte, error) {
+	return MarshalOptions{}.Marshal(v)
+}
+
+// Marshal returns protobuf encoding of v, which must be (a pointer to)
+// openrtb2.BidRequest, openrtb2.BidResponse, request.Request or response.Response (of native1),
+// or any nested object of theirs.
+func (o MarshalOptions) Marshal(v interface{}) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, ErrUnsupportedType
+		}
+		rv = rv.Elem()
+	}
+	if _, ok := messages[rv.Type()]; !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	e := encoder{opts: o}
+	return e.message(nil, rv)
+}
+
+// Unmarshal parses protobuf encoding in buf into v, which must be a pointer to any type, supported by Marshal.
+// v is reset before decoding; unknown fields are skipped.
+//
+// Native messages (request_native and adm_native) are decoded into JSON strings.
+func Unmarshal(buf []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrUnsupportedType
+	}
+	rv = rv.Elem()
+	if _, ok := messages[rv.Type()]; !ok {
+		return ErrUnsupportedType
+	}
+
+	rv.Set(reflect.Zero(rv.Type()))
+	return decodeMessage(buf, rv)
+}
@@ -0,0 +1,13 @@
+package openrtbpb_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestOpenrtbpb(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Openrtbpb Suite")
+}
@@ -0,0 +1,198 @@
+package openrtbpb_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/prebid/openrtb/v20/openrtbpb"
+
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// roundTrip decodes JSON fixture into subject, encodes it to protobuf and back, returning the result as JSON.
+func roundTrip(opts MarshalOptions, path string, subject interface{}) (expected, actual []byte) {
+	expected, err := ioutil.ReadFile(path)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(json.Unmarshal(expected, subject)).To(Succeed())
+
+	buf, err := opts.Marshal(subject)
+	Expect(err).NotTo(HaveOccurred())
+	Expect(Unmarshal(buf, subject)).To(Succeed())
+
+	actual, err = json.Marshal(subject)
+	Expect(err).NotTo(HaveOccurred())
+	return expected, actual
+}
+
+var _ = Describe("Marshal", func() {
+	DescribeTable(
+		"round-trips openrtb2 fixtures",
+
+		func(filename string, subject interface{}) {
+			for _, opts := range []MarshalOptions{{}, {NativeMessages: true}} {
+				expected, actual := roundTrip(opts, filepath.Join("..", "openrtb2", "testdata", filename), subject)
+				Expect(actual).To(MatchJSON(expected))
+			}
+		},
+
+		Entry("2.5 bid-request/expandable-creative.json", "bid-request/2.5/expandable-creative.json", &openrtb2.BidRequest{}),
+		Entry("2.5 bid-request/mobile.json", "bid-request/2.5/mobile.json", &openrtb2.BidRequest{}),
+		Entry("2.5 bid-request/native-ad.json", "bid-request/2.5/native-ad.json", &openrtb2.BidRequest{}),
+		Entry("2.5 bid-request/pmp-with-direct-deal.json", "bid-request/2.5/pmp-with-direct-deal.json", &openrtb2.BidRequest{}),
+		Entry("2.5 bid-request/simple-banner.json", "bid-request/2.5/simple-banner.json", &openrtb2.BidRequest{}),
+		Entry("2.5 bid-request/video.json", "bid-request/2.5/video.json", &openrtb2.BidRequest{}),
+
+		Entry("2.6 bid-request/expandable-creative.json", "bid-request/2.6/expandable-creative.json", &openrtb2.BidRequest{}),
+		Entry("2.6 bid-request/mobile.json", "bid-request/2.6/mobile.json", &openrtb2.BidRequest{}),
+		Entry("2.6 bid-request/pmp-with-direct-deal.json", "bid-request/2.6/pmp-with-direct-deal.json", &openrtb2.BidRequest{}),
+		Entry("2.6 bid-request/simple-banner.json", "bid-request/2.6/simple-banner.json", &openrtb2.BidRequest{}),
+		Entry("2.6 bid-request/video.json", "bid-request/2.6/video.json", &openrtb2.BidRequest{}),
+
+		Entry("2.5 bid-response/ad-served-on-win-notice.json", "bid-response/2.5/ad-served-on-win-notice.json", &openrtb2.BidResponse{}),
+		Entry("2.5 bid-response/direct-deal-ad-served-on-win-notice.json", "bid-response/2.5/direct-deal-ad-served-on-win-notice.json", &openrtb2.BidResponse{}),
+		Entry("2.5 bid-response/vast-xml-document-returned-inline.json", "bid-response/2.5/vast-xml-document-returned-inline.json", &openrtb2.BidResponse{}),
+
+		Entry("2.6 bid-response/ad-served-on-win-notice.json", "bid-response/2.6/ad-served-on-win-notice.json", &openrtb2.BidResponse{}),
+		Entry("2.6 bid-response/direct-deal-ad-served-on-win-notice.json", "bid-response/2.6/direct-deal-ad-served-on-win-notice.json", &openrtb2.BidResponse{}),
+		Entry("2.6 bid-response/vast-xml-document-returned-inline.json", "bid-response/2.6/vast-xml-document-returned-inline.json", &openrtb2.BidResponse{}),
+	)
+
+	DescribeTable(
+		"round-trips native1 fixtures",
+
+		func(path string, subject interface{}) {
+			expected, actual := roundTrip(MarshalOptions{}, filepath.Join("..", "native1", path), subject)
+			Expect(actual).To(MatchJSON(expected))
+		},
+
+		Entry("request/v1.2/content-context.json", "request/testdata/v1.2/content-context.json", &request.Request{}),
+		Entry("request/v1.2/social-context.json", "request/testdata/v1.2/social-context.json", &request.Request{}),
+		Entry("request/v1.2/third-party.json", "request/testdata/v1.2/third-party.json", &request.Request{}),
+		Entry("response/v1.2/clickout.json", "response/testdata/v1.2/clickout.json", &response.Response{}),
+		Entry("response/v1.2/third-party.json", "response/testdata/v1.2/third-party.json", &response.Response{}),
+		Entry("response/v1.2/video.json", "response/testdata/v1.2/video.json", &response.Response{}),
+	)
+
+	DescribeTable(
+		"interoperates with golden bytes of openrtb.proto",
+
+		// Golden bytes are encoded by the protobuf reference implementation (see testdata/gen).
+		func(name string, opts MarshalOptions, subject interface{}) {
+			golden, err := ioutil.ReadFile(filepath.Join("testdata", "golden", name+".pb"))
+			Expect(err).NotTo(HaveOccurred())
+			expected, err := ioutil.ReadFile(filepath.Join("testdata", "golden", name+".json"))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(Unmarshal(golden, subject)).To(Succeed())
+			actual, err := json.Marshal(subject)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actual).To(MatchJSON(expected))
+
+			Expect(json.Unmarshal(expected, subject)).To(Succeed())
+			Expect(opts.Marshal(subject)).To(Equal(golden))
+		},
+
+		Entry("bid-request", "bid-request", MarshalOptions{}, &openrtb2.BidRequest{}),
+		Entry("bid-request-native", "bid-request-native", MarshalOptions{NativeMessages: true}, &openrtb2.BidRequest{}),
+		Entry("bid-response", "bid-response", MarshalOptions{}, &openrtb2.BidResponse{}),
+		Entry("bid-response-native", "bid-response-native", MarshalOptions{NativeMessages: true}, &openrtb2.BidResponse{}),
+	)
+
+	It("should decode unpacked repeated scalars", func() {
+		// field 5 (btype), varint 1 and 4, unpacked
+		buf := []byte{5 << 3, 1, 5 << 3, 4}
+
+		var got openrtb2.Banner
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(got.BType).To(Equal([]openrtb2.BannerAdType{1, 4}))
+	})
+
+	It("should encode native request as message", func() {
+		req := &openrtb2.BidRequest{
+			ID: "1",
+			Imp: []openrtb2.Imp{{
+				ID:     "1",
+				Native: &openrtb2.Native{Request: `{"ver":"1.2","assets":[{"id":1,"title":{"len":90}}]}`, Ver: "1.2"},
+			}},
+		}
+
+		plain, err := Marshal(req)
+		Expect(err).NotTo(HaveOccurred())
+		native, err := MarshalOptions{NativeMessages: true}.Marshal(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(native).NotTo(Equal(plain))
+
+		var got openrtb2.BidRequest
+		Expect(Unmarshal(native, &got)).To(Succeed())
+		Expect(got.Imp[0].Native.Request).To(MatchJSON(req.Imp[0].Native.Request))
+	})
+
+	It("should encode native markup as message", func() {
+		adm, err := ioutil.ReadFile(filepath.Join("..", "native1", "response", "testdata", "v1.2", "clickout.json"))
+		Expect(err).NotTo(HaveOccurred())
+		bid := &openrtb2.Bid{ID: "1", ImpID: "1", Price: 1, AdM: string(adm)}
+
+		plain, err := Marshal(bid)
+		Expect(err).NotTo(HaveOccurred())
+		native, err := MarshalOptions{NativeMessages: true}.Marshal(bid)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(native)).To(BeNumerically("<", len(plain)))
+
+		var got openrtb2.Bid
+		Expect(Unmarshal(native, &got)).To(Succeed())
+		Expect(got.AdM).To(MatchJSON(adm))
+	})
+
+	It("should keep native markup with unknown fields as string", func() {
+		bid := &openrtb2.Bid{ID: "1", ImpID: "1", Price: 1, AdM: `{"link":{"url":"https://x"},"foo":1}`}
+
+		plain, err := Marshal(bid)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(MarshalOptions{NativeMessages: true}.Marshal(bid)).To(Equal(plain))
+	})
+
+	It("should carry ext and fields without proto counterpart as JSON extension", func() {
+		req := &openrtb2.BidRequest{
+			ID:   "1",
+			Regs: &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1), Ext: json.RawMessage(`{"foo":"bar"}`)},
+		}
+
+		buf, err := Marshal(req)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(buf)).To(ContainSubstring(`{"ext":{"foo":"bar"},"gdpr":1}`))
+
+		var got openrtb2.BidRequest
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(got).To(Equal(*req))
+	})
+
+	It("should skip unknown fields", func() {
+		buf, err := Marshal(&openrtb2.Format{W: 300, H: 250})
+		Expect(err).NotTo(HaveOccurred())
+		// field 15, varint 1
+		buf = append(buf, 15<<3, 1)
+
+		var got openrtb2.Format
+		Expect(Unmarshal(buf, &got)).To(Succeed())
+		Expect(got).To(Equal(openrtb2.Format{W: 300, H: 250}))
+	})
+
+	It("should reject unsupported types", func() {
+		_, err := Marshal(&struct{}{})
+		Expect(err).To(MatchError(ErrUnsupportedType))
+		Expect(Unmarshal(nil, openrtb2.BidRequest{})).To(MatchError(ErrUnsupportedType))
+	})
+
+	It("should reject truncated messages", func() {
+		buf, err := Marshal(&openrtb2.BidRequest{ID: "some-request-id"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(Unmarshal(buf[:len(buf)-1], &openrtb2.BidRequest{})).NotTo(Succeed())
+	})
+})
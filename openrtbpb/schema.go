@@ -0,0 +1,258 @@
+package openrtbpb
+
+import (
+	_ "embed" // openrtb.proto
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Proto is the content of openrtb.proto, field numbers are taken from.
+//
+//go:embed openrtb.proto
+var Proto string
+
+// messageTypes map Go types to proto messages (full names, without package).
+var messageTypes = map[reflect.Type]string{
+	reflect.TypeOf(openrtb2.BidRequest{}): "BidRequest",
+	reflect.TypeOf(openrtb2.Source{}):     "BidRequest.Source",
+	reflect.TypeOf(openrtb2.Imp{}):        "BidRequest.Imp",
+	reflect.TypeOf(openrtb2.Metric{}):     "BidRequest.Imp.Metric",
+	reflect.TypeOf(openrtb2.Banner{}):     "BidRequest.Imp.Banner",
+	reflect.TypeOf(openrtb2.Format{}):     "BidRequest.Imp.Banner.Format",
+	reflect.TypeOf(openrtb2.Video{}):      "BidRequest.Imp.Video",
+	reflect.TypeOf(openrtb2.Audio{}):      "BidRequest.Imp.Audio",
+	reflect.TypeOf(openrtb2.Native{}):     "BidRequest.Imp.Native",
+	reflect.TypeOf(openrtb2.PMP{}):        "BidRequest.Imp.Pmp",
+	reflect.TypeOf(openrtb2.Deal{}):       "BidRequest.Imp.Pmp.Deal",
+	reflect.TypeOf(openrtb2.Site{}):       "BidRequest.Site",
+	reflect.TypeOf(openrtb2.App{}):        "BidRequest.App",
+	reflect.TypeOf(openrtb2.Publisher{}):  "BidRequest.Publisher",
+	reflect.TypeOf(openrtb2.Content{}):    "BidRequest.Content",
+	reflect.TypeOf(openrtb2.Producer{}):   "BidRequest.Producer",
+	reflect.TypeOf(openrtb2.Device{}):     "BidRequest.Device",
+	reflect.TypeOf(openrtb2.Geo{}):        "BidRequest.Geo",
+	reflect.TypeOf(openrtb2.User{}):       "BidRequest.User",
+	reflect.TypeOf(openrtb2.Data{}):       "BidRequest.Data",
+	reflect.TypeOf(openrtb2.Segment{}):    "BidRequest.Data.Segment",
+	reflect.TypeOf(openrtb2.Regs{}):       "BidRequest.Regs",
+
+	reflect.TypeOf(openrtb2.BidResponse{}): "BidResponse",
+	reflect.TypeOf(openrtb2.SeatBid{}):     "BidResponse.SeatBid",
+	reflect.TypeOf(openrtb2.Bid{}):         "BidResponse.SeatBid.Bid",
+
+	reflect.TypeOf(request.Request{}):      "NativeRequest",
+	reflect.TypeOf(request.Asset{}):        "NativeRequest.Asset",
+	reflect.TypeOf(request.Title{}):        "NativeRequest.Asset.Title",
+	reflect.TypeOf(request.Image{}):        "NativeRequest.Asset.Image",
+	reflect.TypeOf(request.Data{}):         "NativeRequest.Asset.Data",
+	reflect.TypeOf(request.EventTracker{}): "NativeRequest.EventTrackers",
+
+	reflect.TypeOf(response.Response{}):     "NativeResponse",
+	reflect.TypeOf(response.Link{}):         "NativeResponse.Link",
+	reflect.TypeOf(response.Asset{}):        "NativeResponse.Asset",
+	reflect.TypeOf(response.Title{}):        "NativeResponse.Asset.Title",
+	reflect.TypeOf(response.Image{}):        "NativeResponse.Asset.Image",
+	reflect.TypeOf(response.Video{}):        "NativeResponse.Asset.Video",
+	reflect.TypeOf(response.Data{}):         "NativeResponse.Asset.Data",
+	reflect.TypeOf(response.EventTracker{}): "NativeResponse.EventTracker",
+}
+
+// nativeFields describe oneof alternatives, carrying native markup as messages, rather than JSON strings.
+var nativeFields = map[reflect.Type]nativeField{
+	reflect.TypeOf(openrtb2.Native{}): {name: "request", num: 50, typ: reflect.TypeOf(request.Request{})},
+	reflect.TypeOf(openrtb2.Bid{}):    {name: "adm", num: 50, typ: reflect.TypeOf(response.Response{})},
+}
+
+type nativeField struct {
+	name string       // JSON name of the string field
+	num  int32        // field number of the message alternative
+	typ  reflect.Type // Go type of the message alternative
+}
+
+// message describes encoding of a Go struct type.
+type message struct {
+	fields []field
+	byNum  map[int32]*field
+	byName map[string]*field
+	ext    int // index of Ext field, -1 if none
+}
+
+// field describes encoding of a Go struct field.
+type field struct {
+	index    int    // struct field index
+	name     string // JSON name
+	num      int32  // proto field number, zero if the field is carried by JSON extension
+	required bool   // proto2 required field, emitted even if zero
+	packed   bool   // repeated scalar field, declared [packed = true]
+}
+
+// decl is a field declaration of openrtb.proto.
+type decl struct {
+	num      int32
+	required bool
+	packed   bool
+}
+
+// messages map Go types to their encoding, built from Proto.
+var messages = buildMessages()
+
+func buildMessages() map[reflect.Type]*message {
+	numbers, err := parseProto(Proto)
+	if err != nil {
+		panic(err)
+	}
+
+	ms := make(map[reflect.Type]*message, len(messageTypes))
+	for typ, name := range messageTypes {
+		nums, ok := numbers[name]
+		if !ok {
+			panic(fmt.Sprintf("openrtbpb: message %s is not defined in openrtb.proto", name))
+		}
+
+		m := &message{
+			byNum:  make(map[int32]*field),
+			byName: make(map[string]*field),
+			ext:    -1,
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			sf := typ.Field(i)
+			tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			if tag == "ext" {
+				m.ext = i
+				continue
+			}
+			if err := checkType(sf.Type); err != nil && nums[tag].num != 0 {
+				panic(fmt.Sprintf("openrtbpb: field %s.%s: %v", name, tag, err))
+			}
+			d := nums[tag]
+			m.fields = append(m.fields, field{index: i, name: tag, num: d.num, required: d.required, packed: d.packed})
+		}
+		// Fields are encoded in field number order, as protoc-generated code does.
+		sort.SliceStable(m.fields, func(i, j int) bool { return m.fields[i].num < m.fields[j].num })
+		for i := range m.fields {
+			f := &m.fields[i]
+			m.byName[f.name] = f
+			if f.num != 0 {
+				m.byNum[f.num] = f
+			}
+		}
+
+		for fname, d := range nums {
+			if _, ok := m.byName[fname]; ok {
+				continue
+			}
+			if nf, ok := nativeFields[typ]; ok && nf.num == d.num {
+				continue
+			}
+			panic(fmt.Sprintf("openrtbpb: field %s.%s has no Go counterpart in %s", name, fname, typ))
+		}
+		ms[typ] = m
+	}
+	return ms
+}
+
+// checkType reports whether values of t can be encoded as proto fields.
+func checkType(t reflect.Type) error {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8 {
+		t = t.Elem()
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return nil
+	case reflect.Slice: // []byte
+		return nil
+	case reflect.Struct:
+		if _, ok := messageTypes[t]; ok {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported type %s", t)
+}
+
+var (
+	protoField  = regexp.MustCompile(`^(optional|required|repeated)?\s*[\w.]+\s+(\w+)\s*=\s*(\d+)`)
+	protoPacked = regexp.MustCompile(`\[(.*,)?\s*packed\s*=\s*true\s*(,.*)?\]`)
+)
+
+// parseProto returns field declarations by field name for every message (full name, without package) defined in src.
+// Only the subset of proto2 syntax, used by openrtb.proto, is supported.
+func parseProto(src string) (map[string]map[string]decl, error) {
+	type scope struct {
+		kind string // "message", "oneof" or "other" (e.g., enum)
+		name string // full message name, for messages and oneofs
+	}
+
+	var (
+		stack []scope
+		msgs  = make(map[string]map[string]decl)
+	)
+	for n, line := range strings.Split(src, "\n") {
+		if i := strings.Index(line, "//"); i != -1 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		parent := ""
+		if len(stack) != 0 {
+			parent = stack[len(stack)-1].name
+		}
+
+		switch words := strings.Fields(line); {
+		case line == "}":
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("openrtbpb: openrtb.proto:%d: unexpected }", n+1)
+			}
+			stack = stack[:len(stack)-1]
+		case strings.HasSuffix(line, "{") && words[0] == "message":
+			name := words[1]
+			if parent != "" {
+				name = parent + "." + name
+			}
+			msgs[name] = make(map[string]decl)
+			stack = append(stack, scope{kind: "message", name: name})
+		case strings.HasSuffix(line, "{") && words[0] == "oneof":
+			stack = append(stack, scope{kind: "oneof", name: parent})
+		case strings.HasSuffix(line, "{"):
+			stack = append(stack, scope{kind: "other"})
+		case len(stack) != 0 && stack[len(stack)-1].kind != "other":
+			if m := protoField.FindStringSubmatch(line); m != nil && words[0] != "option" {
+				num, err := strconv.ParseInt(m[3], 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("openrtbpb: openrtb.proto:%d: %w", n+1, err)
+				}
+				msgs[parent][m[2]] = decl{
+					num:      int32(num),
+					required: m[1] == "required",
+					packed:   m[1] == "repeated" && protoPacked.MatchString(line),
+				}
+			}
+		}
+	}
+	if len(stack) != 0 {
+		return nil, fmt.Errorf("openrtbpb: openrtb.proto: unterminated %s", stack[len(stack)-1].kind)
+	}
+	return msgs, nil
+}
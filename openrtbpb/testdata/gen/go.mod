@@ -0,0 +1,10 @@
+module github.com/prebid/openrtb/v20/openrtbpb/testdata/gen
+
+go 1.21
+
+require (
+	github.com/bufbuild/protocompile v0.14.1
+	google.golang.org/protobuf v1.34.2
+)
+
+require golang.org/x/sync v0.8.0 // indirect
@@ -0,0 +1,5 @@
+
+native-1X
+1jS1.2"D
+1.2 2Z2" (2	image/jpg22x8Hjp"o
+"agltb3B1Yi1pbmNyDAsSA0FwcBiJkfIUDAYahoo WeatherBcom.yahoo.weatherP$https://itunes.apple.com/id628677149@d
\ No newline at end of file
@@ -0,0 +1,100 @@
+package openrtbpb
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// Protocol buffers wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var (
+	errTruncated = errors.New("openrtbpb: truncated message")
+	errOverflow  = errors.New("openrtbpb: varint overflow")
+	errWireType  = errors.New("openrtbpb: unsupported wire type")
+)
+
+func appendVarint(b []byte, v uint64) []byte {
+	for v >= 0x80 {
+		b = append(b, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(b, byte(v))
+}
+
+func appendTag(b []byte, num int32, typ int) []byte {
+	return appendVarint(b, uint64(num)<<3|uint64(typ))
+}
+
+func appendFixed64(b []byte, v uint64) []byte {
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendBytes(b []byte, v []byte) []byte {
+	b = appendVarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func consumeVarint(b []byte) (uint64, int, error) {
+	var v uint64
+	for i := 0; i < len(b); i++ {
+		if i == 10 {
+			return 0, 0, errOverflow
+		}
+		v |= uint64(b[i]&0x7f) << (7 * uint(i))
+		if b[i] < 0x80 {
+			return v, i + 1, nil
+		}
+	}
+	return 0, 0, errTruncated
+}
+
+// consumeField reads a field, returning its number, wire type, value (for varint and fixed types) or payload (for bytes type),
+// and the number of bytes read.
+func consumeField(b []byte) (num int32, typ int, v uint64, payload []byte, n int, err error) {
+	tag, n, err := consumeVarint(b)
+	if err != nil {
+		return 0, 0, 0, nil, 0, err
+	}
+	num, typ = int32(tag>>3), int(tag&7)
+	if num <= 0 {
+		return 0, 0, 0, nil, 0, errors.New("openrtbpb: invalid field number")
+	}
+	b = b[n:]
+
+	switch typ {
+	case wireVarint:
+		x, m, err := consumeVarint(b)
+		if err != nil {
+			return 0, 0, 0, nil, 0, err
+		}
+		return num, typ, x, nil, n + m, nil
+	case wireFixed64:
+		if len(b) < 8 {
+			return 0, 0, 0, nil, 0, errTruncated
+		}
+		return num, typ, binary.LittleEndian.Uint64(b), nil, n + 8, nil
+	case wireFixed32:
+		if len(b) < 4 {
+			return 0, 0, 0, nil, 0, errTruncated
+		}
+		return num, typ, uint64(binary.LittleEndian.Uint32(b)), nil, n + 4, nil
+	case wireBytes:
+		l, m, err := consumeVarint(b)
+		if err != nil {
+			return 0, 0, 0, nil, 0, err
+		}
+		if l > uint64(len(b)-m) {
+			return 0, 0, 0, nil, 0, errTruncated
+		}
+		return num, typ, 0, b[m : m+int(l)], n + m + int(l), nil
+	}
+	return 0, 0, 0, nil, 0, errWireType
+}
@@ -0,0 +1,183 @@
+// Package privacy provides regulation-driven scrubbing of OpenRTB 2.x bid requests
+//
+// Signals (COPPA, LMT, DNT, GDPR with TCF v2 consent, US Privacy, GPP) are mapped to actions
+// (IP truncation, removal of device and user identifiers, demographics and data, geo rounding) by a Policy.
+package privacy
+
+import (
+	"github.com/prebid/openrtb/v20/gpp1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/tcf2"
+)
+
+// Signal identifies a privacy signal of a bid request.
+type Signal string
+
+// Signal options.
+const (
+	SignalCOPPA     Signal = "coppa"      // Regs.COPPA = 1
+	SignalLMT       Signal = "lmt"        // Device.Lmt = 1
+	SignalDNT       Signal = "dnt"        // Device.DNT = 1
+	SignalGDPR      Signal = "gdpr"       // GDPR applies, but personal data is not allowed for the vendor by User.Consent (or GPP tcfeuv2 section)
+	SignalUSPrivacy Signal = "us_privacy" // Regs.USPrivacy signals sale opt-out
+	SignalGPP       Signal = "gpp"        // Regs.GPP applicable US section signals opt-out
+)
+
+// Action is a set of scrubbing actions.
+type Action uint
+
+// Action options.
+const (
+	ActionTruncateIP         Action = 1  // truncate Device.IP and Device.IPv6
+	ActionRemoveDeviceIDs    Action = 2  // remove Device.IFA and hashed device IDs (DIDSHA1, DIDMD5, DPIDSHA1, DPIDMD5, MACSHA1, MACMD5)
+	ActionRemoveUserIDs      Action = 4  // remove User.ID, User.BuyerUID and User.EIDs
+	ActionRemoveDemographics Action = 8  // remove User.Yob and User.Gender
+	ActionRoundGeo           Action = 16 // round Geo.Lat and Geo.Lon of Device.Geo and User.Geo
+	ActionRemoveUserData     Action = 32 // remove User.Data
+
+	ActionAll = ActionTruncateIP | ActionRemoveDeviceIDs | ActionRemoveUserIDs | ActionRemoveDemographics | ActionRoundGeo | ActionRemoveUserData
+)
+
+// DefaultRules map signals to actions, unless overridden by Policy.Rules.
+var DefaultRules = map[Signal]Action{
+	SignalCOPPA:     ActionAll,
+	SignalLMT:       ActionTruncateIP | ActionRemoveDeviceIDs | ActionRemoveUserIDs | ActionRoundGeo,
+	SignalDNT:       ActionTruncateIP | ActionRemoveDeviceIDs | ActionRemoveUserIDs | ActionRoundGeo,
+	SignalGDPR:      ActionAll,
+	SignalUSPrivacy: ActionTruncateIP | ActionRemoveDeviceIDs | ActionRemoveUserIDs | ActionRemoveDemographics | ActionRoundGeo,
+	SignalGPP:       ActionTruncateIP | ActionRemoveDeviceIDs | ActionRemoveUserIDs | ActionRemoveDemographics | ActionRoundGeo,
+}
+
+// Default policy parameters.
+const (
+	DefaultIPv4Bits    = 24
+	DefaultIPv6Bits    = 56
+	DefaultGeoDecimals = 2
+)
+
+// Policy configures scrubbing.
+type Policy struct {
+	// VendorID is the TCF Global Vendor List ID of the request recipient (e.g., a bidder).
+	// Zero means the recipient has no vendor ID, so personal data is never allowed when GDPR applies.
+	VendorID int
+
+	// Purposes are TCF purposes, that must be allowed for the vendor in addition to purpose 1 (storage and access).
+	Purposes []int
+
+	// IPv4Bits is the number of leading IPv4 address bits, retained by truncation; zero means DefaultIPv4Bits.
+	IPv4Bits int
+
+	// IPv6Bits is the number of leading IPv6 address bits, retained by truncation; zero means DefaultIPv6Bits.
+	IPv6Bits int
+
+	// GeoDecimals is the number of decimal places coordinates are rounded to; zero means DefaultGeoDecimals.
+	GeoDecimals int
+
+	// Rules map signals to actions; nil means DefaultRules.
+	// Signals, absent from the map, trigger no actions.
+	Rules map[Signal]Action
+}
+
+// Audit describes what was removed or modified by scrubbing.
+type Audit struct {
+	// Signals triggered, in evaluation order.
+	Signals []Signal
+
+	// Actions applied (union for all triggered signals).
+	Actions Action
+
+	// Fields lists JSON paths of fields removed or modified (e.g., "device.ifa", "user.geo.lat").
+	Fields []string
+
+	// Errors encountered while decoding consent strings.
+	// Malformed TCF consent is treated as absent (so GDPR signal is triggered when GDPR applies),
+	// malformed GPP sections are ignored.
+	Errors []error
+}
+
+// Scrub applies policy to a copy of req, leaving req intact.
+//
+// Only the objects that are modified get copied (the rest is shared with req).
+func Scrub(req *openrtb2.BidRequest, p Policy) (*openrtb2.BidRequest, *Audit) {
+	cp := *req
+	audit := Detect(&cp, p)
+	apply(&cp, p, audit, true)
+	return &cp, audit
+}
+
+// Apply applies policy to req in place.
+func Apply(req *openrtb2.BidRequest, p Policy) *Audit {
+	audit := Detect(req, p)
+	apply(req, p, audit, false)
+	return audit
+}
+
+// Detect evaluates signals of req without modifying it.
+// Audit.Fields is always empty.
+func Detect(req *openrtb2.BidRequest, p Policy) *Audit {
+	rules := p.Rules
+	if rules == nil {
+		rules = DefaultRules
+	}
+	audit := new(Audit)
+	trigger := func(s Signal) {
+		audit.Signals = append(audit.Signals, s)
+		audit.Actions |= rules[s]
+	}
+
+	if req.Regs != nil && req.Regs.COPPA != nil && *req.Regs.COPPA == 1 {
+		trigger(SignalCOPPA)
+	}
+	if req.Device != nil {
+		if req.Device.Lmt != nil && *req.Device.Lmt == 1 {
+			trigger(SignalLMT)
+		}
+		if req.Device.DNT != nil && *req.Device.DNT == 1 {
+			trigger(SignalDNT)
+		}
+	}
+
+	gpp, err := gpp1.FromBidRequest(req)
+	if err != nil {
+		if _, ok := err.(*gpp1.SIDMismatchError); !ok {
+			audit.Errors = append(audit.Errors, err)
+		}
+	}
+
+	if !gdprAllowed(req, gpp, p, audit) {
+		trigger(SignalGDPR)
+	}
+
+	if req.Regs != nil && req.Regs.USPrivacy != "" {
+		usp, err := gpp1.ParseUSPV1(req.Regs.USPrivacy)
+		if err != nil {
+			audit.Errors = append(audit.Errors, err)
+		} else if usp.OptedOut() {
+			trigger(SignalUSPrivacy)
+		}
+	}
+
+	if gpp.OptedOut() {
+		trigger(SignalGPP)
+	}
+
+	return audit
+}
+
+func gdprAllowed(req *openrtb2.BidRequest, gpp *gpp1.Signals, p Policy, audit *Audit) bool {
+	s, err := tcf2.FromBidRequest(req)
+	if err != nil {
+		audit.Errors = append(audit.Errors, err)
+	}
+
+	// fall back to GPP EU TCF section, if User.Consent is absent
+	if s.Consent == nil && (req.User == nil || req.User.Consent == "") {
+		for _, sec := range gpp.Applicable() {
+			if tcf, ok := sec.(*gpp1.TCFEUV2); ok {
+				s.Consent = tcf.TCString
+			}
+		}
+	}
+
+	return s.PersonalDataAllowed(p.VendorID, p.Purposes...)
+}
@@ -0,0 +1,13 @@
+package privacy_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestPrivacy(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Privacy Suite")
+}
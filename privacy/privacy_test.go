@@ -0,0 +1,129 @@
+package privacy_test
+
+import (
+	. "github.com/prebid/openrtb/v20/privacy"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func float64Ptr(v float64) *float64 {
+	return &v
+}
+
+var _ = Describe("Scrub", func() {
+	var req *openrtb2.BidRequest
+
+	BeforeEach(func() {
+		req = &openrtb2.BidRequest{
+			ID: "req",
+			Device: &openrtb2.Device{
+				IP:      "192.168.1.123",
+				IPv6:    "2001:db8:85a3:1234:5678:8a2e:370:7334",
+				IFA:     "ifa",
+				DIDSHA1: "didsha1",
+				DPIDMD5: "dpidmd5",
+				Geo:     &openrtb2.Geo{Lat: float64Ptr(51.123456), Lon: float64Ptr(-0.987654)},
+			},
+			User: &openrtb2.User{
+				ID:       "user",
+				BuyerUID: "buyer",
+				Yob:      1980,
+				Gender:   "F",
+				EIDs:     []openrtb2.EID{{Source: "example.com"}},
+				Data:     []openrtb2.Data{{ID: "data"}},
+			},
+		}
+	})
+
+	It("should leave request intact without signals", func() {
+		res, audit := Scrub(req, Policy{})
+		Expect(audit.Signals).To(BeEmpty())
+		Expect(audit.Fields).To(BeEmpty())
+		Expect(res.Device).To(BeIdenticalTo(req.Device))
+		Expect(res.User).To(BeIdenticalTo(req.User))
+	})
+
+	It("should scrub everything for COPPA on a copy", func() {
+		req.Regs = &openrtb2.Regs{COPPA: openrtb2.Int8Ptr(1)}
+		res, audit := Scrub(req, Policy{})
+
+		Expect(audit.Signals).To(Equal([]Signal{SignalCOPPA}))
+		Expect(audit.Actions).To(Equal(ActionAll))
+		Expect(audit.Fields).To(ConsistOf(
+			"device.ip", "device.ipv6", "device.ifa", "device.didsha1", "device.dpidmd5",
+			"device.geo.lat", "device.geo.lon",
+			"user.id", "user.buyeruid", "user.eids", "user.yob", "user.gender", "user.data",
+		))
+
+		Expect(res.Device.IP).To(Equal("192.168.1.0"))
+		Expect(res.Device.IPv6).To(Equal("2001:db8:85a3:1200::"))
+		Expect(res.Device.IFA).To(BeEmpty())
+		Expect(*res.Device.Geo.Lat).To(Equal(51.12))
+		Expect(*res.Device.Geo.Lon).To(Equal(-0.99))
+		Expect(res.User.ID).To(BeEmpty())
+		Expect(res.User.EIDs).To(BeNil())
+		Expect(res.User.Data).To(BeNil())
+
+		// original is intact
+		Expect(req.Device.IP).To(Equal("192.168.1.123"))
+		Expect(req.Device.IFA).To(Equal("ifa"))
+		Expect(*req.Device.Geo.Lat).To(Equal(51.123456))
+		Expect(req.User.ID).To(Equal("user"))
+		Expect(req.User.Data).To(HaveLen(1))
+	})
+
+	It("should scrub in place on LMT, keeping demographics", func() {
+		req.Device.Lmt = openrtb2.Int8Ptr(1)
+		audit := Apply(req, Policy{IPv4Bits: 16})
+
+		Expect(audit.Signals).To(Equal([]Signal{SignalLMT}))
+		Expect(req.Device.IP).To(Equal("192.168.0.0"))
+		Expect(req.Device.IFA).To(BeEmpty())
+		Expect(req.User.BuyerUID).To(BeEmpty())
+		Expect(req.User.Yob).To(Equal(int64(1980)))
+		Expect(req.User.Data).To(HaveLen(1))
+	})
+
+	It("should scrub when GDPR applies without consent for the vendor", func() {
+		req.Regs = &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1)}
+		req.User.Consent = "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"
+
+		_, audit := Scrub(req, Policy{VendorID: 32})
+		Expect(audit.Signals).To(Equal([]Signal{SignalGDPR}))
+		Expect(audit.Errors).To(BeEmpty())
+	})
+
+	It("should treat malformed consent as absent", func() {
+		req.Regs = &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1)}
+		req.User.Consent = "garbage"
+
+		_, audit := Scrub(req, Policy{VendorID: 32})
+		Expect(audit.Signals).To(Equal([]Signal{SignalGDPR}))
+		Expect(audit.Errors).To(HaveLen(1))
+	})
+
+	It("should scrub on US Privacy and GPP opt-outs", func() {
+		req.Regs = &openrtb2.Regs{
+			USPrivacy: "1YYN",
+			GPP:       "DBABLA~BVVaAAEABCA.QA",
+			GPPSID:    []int8{7},
+		}
+
+		res, audit := Scrub(req, Policy{})
+		Expect(audit.Signals).To(Equal([]Signal{SignalUSPrivacy, SignalGPP}))
+		Expect(audit.Actions.Has(ActionRemoveUserData)).To(BeFalse())
+		Expect(res.User.Data).To(HaveLen(1))
+		Expect(res.User.Gender).To(BeEmpty())
+	})
+
+	It("should honor custom rules", func() {
+		req.Regs = &openrtb2.Regs{COPPA: openrtb2.Int8Ptr(1)}
+
+		res, audit := Scrub(req, Policy{Rules: map[Signal]Action{SignalCOPPA: ActionRemoveUserData}})
+		Expect(audit.Fields).To(Equal([]string{"user.data"}))
+		Expect(res.Device).To(BeIdenticalTo(req.Device))
+	})
+})
@@ -0,0 +1,179 @@
+package privacy
+
+import (
+	"math"
+	"net"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Has reports whether all of actions are in the set.
+func (a Action) Has(actions Action) bool {
+	return a&actions == actions
+}
+
+// Actions, affecting Device and User objects respectively.
+const (
+	deviceActions = ActionTruncateIP | ActionRemoveDeviceIDs | ActionRoundGeo
+	userActions   = ActionRemoveUserIDs | ActionRemoveDemographics | ActionRemoveUserData | ActionRoundGeo
+)
+
+// scrubber applies actions, copying objects before modification, if requested.
+type scrubber struct {
+	policy Policy
+	audit  *Audit
+	copy   bool
+}
+
+func apply(req *openrtb2.BidRequest, p Policy, audit *Audit, copy bool) {
+	if audit.Actions == 0 {
+		return
+	}
+	s := &scrubber{policy: p, audit: audit, copy: copy}
+	if req.Device != nil && audit.Actions&deviceActions != 0 {
+		req.Device = s.device(req.Device)
+	}
+	if req.User != nil && audit.Actions&userActions != 0 {
+		req.User = s.user(req.User)
+	}
+}
+
+func (s *scrubber) device(d *openrtb2.Device) *openrtb2.Device {
+	if s.copy {
+		cp := *d
+		d = &cp
+	}
+	actions := s.audit.Actions
+
+	if actions.Has(ActionTruncateIP) {
+		if ip := truncateIP(d.IP, s.ipv4Bits(), 32); ip != d.IP {
+			d.IP = ip
+			s.touch("device.ip")
+		}
+		if ip := truncateIP(d.IPv6, s.ipv6Bits(), 128); ip != d.IPv6 {
+			d.IPv6 = ip
+			s.touch("device.ipv6")
+		}
+	}
+
+	if actions.Has(ActionRemoveDeviceIDs) {
+		s.clear(&d.IFA, "device.ifa")
+		s.clear(&d.DIDSHA1, "device.didsha1")
+		s.clear(&d.DIDMD5, "device.didmd5")
+		s.clear(&d.DPIDSHA1, "device.dpidsha1")
+		s.clear(&d.DPIDMD5, "device.dpidmd5")
+		s.clear(&d.MACSHA1, "device.macsha1")
+		s.clear(&d.MACMD5, "device.macmd5")
+	}
+
+	if actions.Has(ActionRoundGeo) && d.Geo != nil {
+		d.Geo = s.geo(d.Geo, "device.geo")
+	}
+	return d
+}
+
+func (s *scrubber) user(u *openrtb2.User) *openrtb2.User {
+	if s.copy {
+		cp := *u
+		u = &cp
+	}
+	actions := s.audit.Actions
+
+	if actions.Has(ActionRemoveUserIDs) {
+		s.clear(&u.ID, "user.id")
+		s.clear(&u.BuyerUID, "user.buyeruid")
+		if u.EIDs != nil {
+			u.EIDs = nil
+			s.touch("user.eids")
+		}
+	}
+
+	if actions.Has(ActionRemoveDemographics) {
+		if u.Yob != 0 {
+			u.Yob = 0
+			s.touch("user.yob")
+		}
+		s.clear(&u.Gender, "user.gender")
+	}
+
+	if actions.Has(ActionRemoveUserData) && u.Data != nil {
+		u.Data = nil
+		s.touch("user.data")
+	}
+
+	if actions.Has(ActionRoundGeo) && u.Geo != nil {
+		u.Geo = s.geo(u.Geo, "user.geo")
+	}
+	return u
+}
+
+func (s *scrubber) geo(g *openrtb2.Geo, path string) *openrtb2.Geo {
+	if s.copy {
+		cp := *g
+		g = &cp
+	}
+	g.Lat = s.round(g.Lat, path+".lat")
+	g.Lon = s.round(g.Lon, path+".lon")
+	return g
+}
+
+func (s *scrubber) round(v *float64, path string) *float64 {
+	if v == nil {
+		return nil
+	}
+
+	decimals := s.policy.GeoDecimals
+	if decimals == 0 {
+		decimals = DefaultGeoDecimals
+	}
+	scale := math.Pow(10, float64(decimals))
+	r := math.Round(*v*scale) / scale
+	if r == *v {
+		return v
+	}
+	s.touch(path)
+	return &r
+}
+
+func (s *scrubber) clear(v *string, path string) {
+	if *v != "" {
+		*v = ""
+		s.touch(path)
+	}
+}
+
+func (s *scrubber) touch(path string) {
+	s.audit.Fields = append(s.audit.Fields, path)
+}
+
+func (s *scrubber) ipv4Bits() int {
+	if s.policy.IPv4Bits != 0 {
+		return s.policy.IPv4Bits
+	}
+	return DefaultIPv4Bits
+}
+
+func (s *scrubber) ipv6Bits() int {
+	if s.policy.IPv6Bits != 0 {
+		return s.policy.IPv6Bits
+	}
+	return DefaultIPv6Bits
+}
+
+// truncateIP zeroes all but leading bits of IP address; unparsable addresses are removed altogether.
+func truncateIP(s string, bits, size int) string {
+	if s == "" {
+		return ""
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return ""
+	}
+	if size == 32 {
+		ip = ip.To4()
+		if ip == nil {
+			return ""
+		}
+	}
+	return ip.Mask(net.CIDRMask(bits, size)).String()
+}
@@ -0,0 +1,354 @@
+package rtbhttp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Endpoint is a bidder endpoint, bid requests are sent to.
+type Endpoint struct {
+	// Name identifies the bidder in results.
+	Name string
+
+	// URL of the endpoint.
+	URL string
+
+	// Header is added to each request (e.g., for authorization).
+	Header http.Header
+
+	// Version is sent in HeaderVersion; empty means DefaultVersion2.
+	Version string
+
+	// Gzip enables compression of request bodies.
+	Gzip bool
+
+	// Transform, if set, returns the request to be sent to the bidder.
+	// It is passed a shallow copy of the original request,
+	// so nested objects must be copied before they are modified.
+	Transform func(req *openrtb2.BidRequest) (*openrtb2.BidRequest, error)
+}
+
+// ClientOptions configure Client.
+type ClientOptions struct {
+	// HTTPClient is used to send requests; nil means a client with NewTransport.
+	HTTPClient *http.Client
+
+	// NetworkAllowance is subtracted from TMax, when deriving the deadline for bidder responses,
+	// to leave time for the auction and the response to reach the caller.
+	NetworkAllowance time.Duration
+
+	// DefaultTMax is used for requests without TMax; zero means no deadline for such requests (besides the one of the context).
+	DefaultTMax time.Duration
+
+	// MaxResponseSize limits size of (decompressed) response body, in bytes; zero means no limit.
+	MaxResponseSize int64
+}
+
+// Outcome is the outcome of a bid request, sent to a bidder.
+type Outcome int8
+
+// Outcome options.
+const (
+	OutcomeBid   Outcome = 1 // response with bids
+	OutcomeNoBid Outcome = 2 // HTTP 204, or response without bids (possibly with NBR)
+	OutcomeEmpty Outcome = 3 // HTTP 200 with empty body
+	OutcomeError Outcome = 4 // request failed, see Result.Err
+)
+
+// ErrorKind classifies errors of bid requests, sent to bidders.
+type ErrorKind int8
+
+// ErrorKind options.
+const (
+	ErrorTimeout   ErrorKind = 1 // deadline exceeded (or network timeout)
+	ErrorHTTP      ErrorKind = 2 // network failure or unexpected HTTP status
+	ErrorDecode    ErrorKind = 3 // malformed or invalid response body
+	ErrorTransform ErrorKind = 4 // Endpoint.Transform failed
+)
+
+// String implements fmt.Stringer.
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrorTimeout:
+		return "timeout"
+	case ErrorHTTP:
+		return "http"
+	case ErrorDecode:
+		return "decode"
+	case ErrorTransform:
+		return "transform"
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int8(k))
+}
+
+// Error is an error of a bid request, sent to a bidder.
+type Error struct {
+	// Kind of the error.
+	Kind ErrorKind
+
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return "rtbhttp: " + e.Kind.String() + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Result is the result of a bid request, sent to a bidder.
+type Result struct {
+	// Endpoint the request was sent to.
+	Endpoint *Endpoint
+
+	// Request sent (after Endpoint.Transform).
+	Request *openrtb2.BidRequest
+
+	// Outcome of the request.
+	Outcome Outcome
+
+	// StatusCode of HTTP response; zero if none was received.
+	StatusCode int
+
+	// Latency from sending the request till reading the response body.
+	Latency time.Duration
+
+	// Response is the decoded bid response (for OutcomeBid and OutcomeNoBid with response body).
+	Response *openrtb2.BidResponse
+
+	// Err is set for OutcomeError; it is always *Error.
+	Err error
+}
+
+// Client sends bid requests to multiple bidders concurrently.
+type Client struct {
+	opts ClientOptions
+}
+
+// NewClient returns Client with opts.
+func NewClient(opts ClientOptions) *Client {
+	if opts.HTTPClient == nil {
+		opts.HTTPClient = &http.Client{Transport: NewTransport()}
+	}
+	return &Client{opts: opts}
+}
+
+// NewTransport returns HTTP transport, tuned for keep-alive connections to a moderate number of bidders:
+// idle connections are kept per host for reuse, and response decompression is left to Client.
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          1024,
+		MaxIdleConnsPerHost:   64,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   time.Second,
+		ExpectContinueTimeout: time.Second,
+		DisableCompression:    true,
+	}
+}
+
+// Do sends req to endpoints concurrently and returns results in endpoint order.
+//
+// Responses are awaited until TMax (minus ClientOptions.NetworkAllowance) elapses, or ctx is done.
+func (c *Client) Do(ctx context.Context, req *openrtb2.BidRequest, endpoints []Endpoint) []Result {
+	start := time.Now()
+
+	tmax := time.Duration(req.TMax) * time.Millisecond
+	if tmax <= 0 {
+		tmax = c.opts.DefaultTMax
+	}
+	if tmax > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, start.Add(tmax-c.opts.NetworkAllowance))
+		defer cancel()
+	}
+
+	results := make([]Result, len(endpoints))
+	var wg sync.WaitGroup
+	for i := range endpoints {
+		wg.Add(1)
+		go func(res *Result, e *Endpoint) {
+			defer wg.Done()
+			c.send(ctx, req, e, res)
+		}(&results[i], &endpoints[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (c *Client) send(ctx context.Context, req *openrtb2.BidRequest, e *Endpoint, res *Result) {
+	res.Endpoint = e
+	res.Request = req
+	fail := func(kind ErrorKind, err error) {
+		if kind != ErrorTransform && ctx.Err() != nil {
+			kind = ErrorTimeout
+		} else if ne, ok := err.(net.Error); ok && ne.Timeout() {
+			kind = ErrorTimeout
+		}
+		res.Outcome = OutcomeError
+		res.Err = &Error{Kind: kind, Err: err}
+	}
+
+	if e.Transform != nil {
+		cp := *req
+		out, err := e.Transform(&cp)
+		if err != nil {
+			fail(ErrorTransform, err)
+			return
+		}
+		res.Request = out
+	}
+
+	body, err := encodeRequest(res.Request, e.Gzip)
+	if err != nil {
+		fail(ErrorTransform, err)
+		return
+	}
+
+	hr, err := http.NewRequestWithContext(ctx, http.MethodPost, e.URL, body)
+	if err != nil {
+		fail(ErrorHTTP, err)
+		return
+	}
+	for k, vs := range e.Header {
+		hr.Header[k] = append([]string(nil), vs...)
+	}
+	version := e.Version
+	if version == "" {
+		version = DefaultVersion2
+	}
+	hr.Header.Set(HeaderVersion, version)
+	hr.Header.Set("Content-Type", "application/json")
+	hr.Header.Set("Accept-Encoding", "gzip")
+	if e.Gzip {
+		hr.Header.Set("Content-Encoding", "gzip")
+	}
+
+	start := time.Now()
+	defer func() { res.Latency = time.Since(start) }()
+
+	resp, err := c.opts.HTTPClient.Do(hr)
+	if err != nil {
+		fail(ErrorHTTP, err)
+		return
+	}
+	defer func() {
+		// drain body, so connection can be reused
+		_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4<<10))
+		_ = resp.Body.Close()
+	}()
+	res.StatusCode = resp.StatusCode
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		res.Outcome = OutcomeNoBid
+		return
+	case http.StatusOK:
+	default:
+		fail(ErrorHTTP, fmt.Errorf("unexpected status %d", resp.StatusCode))
+		return
+	}
+
+	r, err := decodedBody(resp.Header, resp.Body)
+	if err != nil {
+		fail(ErrorDecode, err)
+		return
+	}
+	if c.opts.MaxResponseSize > 0 {
+		r = &limitedReader{r: r, n: c.opts.MaxResponseSize}
+	}
+	buf, err := ioutil.ReadAll(r)
+	if err != nil {
+		if errors.Is(err, ErrBodyTooLarge) {
+			fail(ErrorDecode, err)
+		} else {
+			fail(ErrorHTTP, err)
+		}
+		return
+	}
+	if len(bytes.TrimSpace(buf)) == 0 {
+		res.Outcome = OutcomeEmpty
+		return
+	}
+
+	br := new(openrtb2.BidResponse)
+	if err := json.Unmarshal(buf, br); err != nil {
+		fail(ErrorDecode, err)
+		return
+	}
+	if err := validateResponse(res.Request, br); err != nil {
+		fail(ErrorDecode, err)
+		return
+	}
+
+	res.Response = br
+	res.Outcome = OutcomeNoBid
+	for _, sb := range br.SeatBid {
+		if len(sb.Bid) != 0 {
+			res.Outcome = OutcomeBid
+			break
+		}
+	}
+}
+
+func encodeRequest(req *openrtb2.BidRequest, compress bool) (io.Reader, error) {
+	buf, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if !compress {
+		return bytes.NewReader(buf), nil
+	}
+
+	var zbuf bytes.Buffer
+	zw := gzip.NewWriter(&zbuf)
+	if _, err := zw.Write(buf); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &zbuf, nil
+}
+
+// validateResponse checks that resp answers req: IDs match and bids reference known impressions.
+func validateResponse(req *openrtb2.BidRequest, resp *openrtb2.BidResponse) error {
+	if resp.ID != req.ID {
+		return fmt.Errorf("response id %q does not match request id %q", resp.ID, req.ID)
+	}
+
+	imps := make(map[string]struct{}, len(req.Imp))
+	for _, imp := range req.Imp {
+		imps[imp.ID] = struct{}{}
+	}
+	for _, sb := range resp.SeatBid {
+		for _, bid := range sb.Bid {
+			if _, ok := imps[bid.ImpID]; !ok {
+				return fmt.Errorf("bid %q references unknown impid %q", bid.ID, bid.ImpID)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package rtbhttp_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/prebid/openrtb/v20/rtbhttp"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		req     *openrtb2.BidRequest
+		servers []*httptest.Server
+	)
+
+	BeforeEach(func() {
+		req = &openrtb2.BidRequest{ID: "req", Imp: []openrtb2.Imp{{ID: "1"}}, TMax: 200}
+		servers = nil
+	})
+
+	AfterEach(func() {
+		for _, s := range servers {
+			s.Close()
+		}
+	})
+
+	serve := func(h http.HandlerFunc) string {
+		s := httptest.NewServer(h)
+		servers = append(servers, s)
+		return s.URL
+	}
+
+	It("should fan out requests and classify results", func() {
+		bidder := serve(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get(HeaderVersion)).To(Equal("2.5"))
+			Expect(r.Header.Get("Authorization")).To(Equal("token"))
+			Expect(r.Header.Get("Content-Encoding")).To(Equal("gzip"))
+
+			zr, err := gzip.NewReader(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+			var br openrtb2.BidRequest
+			Expect(json.NewDecoder(zr).Decode(&br)).To(Succeed())
+			Expect(br.BCat).To(Equal([]string{"IAB25"}))
+
+			w.Header().Set("Content-Encoding", "gzip")
+			zw := gzip.NewWriter(w)
+			_, _ = zw.Write([]byte(`{"id":"req","seatbid":[{"bid":[{"id":"bid","impid":"1","price":1.5}]}]}`))
+			_ = zw.Close()
+		})
+		noBid := serve(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+		empty := serve(func(http.ResponseWriter, *http.Request) {})
+		nbr := serve(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"req","nbr":8}`))
+		})
+		invalid := serve(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"req","seatbid":[{"bid":[{"id":"bid","impid":"2","price":1}]}]}`))
+		})
+		malformed := serve(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":`))
+		})
+		failing := serve(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+		slow := serve(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(500 * time.Millisecond):
+			}
+		})
+
+		results := NewClient(ClientOptions{NetworkAllowance: 100 * time.Millisecond}).Do(context.Background(), req, []Endpoint{
+			{
+				Name:    "bidder",
+				URL:     bidder,
+				Header:  http.Header{"Authorization": {"token"}},
+				Version: "2.5",
+				Gzip:    true,
+				Transform: func(r *openrtb2.BidRequest) (*openrtb2.BidRequest, error) {
+					r.BCat = []string{"IAB25"}
+					return r, nil
+				},
+			},
+			{Name: "nobid", URL: noBid},
+			{Name: "empty", URL: empty},
+			{Name: "nbr", URL: nbr},
+			{Name: "invalid", URL: invalid},
+			{Name: "malformed", URL: malformed},
+			{Name: "failing", URL: failing},
+			{Name: "slow", URL: slow},
+			{Name: "transform", URL: noBid, Transform: func(*openrtb2.BidRequest) (*openrtb2.BidRequest, error) {
+				return nil, errors.New("skip")
+			}},
+		})
+		Expect(results).To(HaveLen(9))
+		Expect(req.BCat).To(BeNil())
+
+		Expect(results[0].Endpoint.Name).To(Equal("bidder"))
+		Expect(results[0].Outcome).To(Equal(OutcomeBid))
+		Expect(results[0].StatusCode).To(Equal(http.StatusOK))
+		Expect(results[0].Latency).To(BeNumerically(">", 0))
+		Expect(results[0].Request.BCat).To(Equal([]string{"IAB25"}))
+		Expect(results[0].Response.SeatBid[0].Bid[0].Price).To(Equal(1.5))
+
+		Expect(results[1].Outcome).To(Equal(OutcomeNoBid))
+		Expect(results[1].StatusCode).To(Equal(http.StatusNoContent))
+		Expect(results[1].Response).To(BeNil())
+
+		Expect(results[2].Outcome).To(Equal(OutcomeEmpty))
+
+		Expect(results[3].Outcome).To(Equal(OutcomeNoBid))
+		Expect(results[3].Response.NBR.Val()).To(BeEquivalentTo(8))
+
+		kind := func(r Result) ErrorKind {
+			Expect(r.Outcome).To(Equal(OutcomeError))
+			var e *Error
+			Expect(errors.As(r.Err, &e)).To(BeTrue())
+			return e.Kind
+		}
+		Expect(kind(results[4])).To(Equal(ErrorDecode))
+		Expect(kind(results[5])).To(Equal(ErrorDecode))
+		Expect(kind(results[6])).To(Equal(ErrorHTTP))
+		Expect(results[6].StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(kind(results[7])).To(Equal(ErrorTimeout))
+		Expect(results[7].Latency).To(BeNumerically("<", 150*time.Millisecond))
+		Expect(kind(results[8])).To(Equal(ErrorTransform))
+	})
+
+	It("should reject oversized responses", func() {
+		url := serve(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"id":"req","seatbid":[{"bid":[{"id":"bid","impid":"1","price":1}]}]}`))
+		})
+
+		results := NewClient(ClientOptions{MaxResponseSize: 16}).Do(context.Background(), req, []Endpoint{{URL: url}})
+		Expect(results[0].Err).To(MatchError(ErrBodyTooLarge))
+		Expect(results[0].Err.(*Error).Kind).To(Equal(ErrorDecode))
+	})
+
+	It("should talk to NewHandler", func() {
+		url := serve(NewHandler(BidderFunc(func(_ context.Context, req *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			return &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "bid", ImpID: "1", Price: 2}}}},
+			}, nil
+		}), ServerOptions{}).ServeHTTP)
+
+		results := NewClient(ClientOptions{}).Do(context.Background(), req, []Endpoint{{URL: url, Gzip: true}})
+		Expect(results[0].Err).NotTo(HaveOccurred())
+		Expect(results[0].Outcome).To(Equal(OutcomeBid))
+		Expect(results[0].Response.ID).To(Equal("req"))
+	})
+})
@@ -0,0 +1,74 @@
+// Package rtbhttp provides HTTP transport of OpenRTB bid requests and responses: a server adapter for bidders and a fan-out client for exchanges
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb2.x/blob/main/2.6.md#24---transport
+// https://github.com/InteractiveAdvertisingBureau/openrtb/blob/main/OpenRTB%20v3.0%20FINAL.md#transport
+package rtbhttp
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HeaderVersion is the HTTP header, carrying OpenRTB specification version (e.g., "2.6").
+const HeaderVersion = "X-Openrtb-Version"
+
+// Default OpenRTB versions, sent in HeaderVersion when none is configured.
+const (
+	DefaultVersion2 = "2.6"
+	DefaultVersion3 = "3.0"
+)
+
+// versionAllowed reports whether version v matches one of allowed versions;
+// if allowed is empty, v must have the given major version.
+func versionAllowed(v string, allowed []string, major string) bool {
+	if len(allowed) == 0 {
+		return v == major || strings.HasPrefix(v, major+".")
+	}
+	for _, a := range allowed {
+		if v == a {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip reports whether Accept-Encoding header of h allows gzip.
+func acceptsGzip(h http.Header) bool {
+	for _, v := range h.Values("Accept-Encoding") {
+		for _, enc := range strings.Split(v, ",") {
+			enc = strings.TrimSpace(enc)
+			if i := strings.IndexByte(enc, ';'); i != -1 {
+				if strings.TrimSpace(enc[i+1:]) == "q=0" {
+					continue
+				}
+				enc = strings.TrimSpace(enc[:i])
+			}
+			if strings.EqualFold(enc, "gzip") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// decodedBody wraps body into gzip reader, if Content-Encoding header of h is gzip.
+func decodedBody(h http.Header, body io.Reader) (io.Reader, error) {
+	switch enc := strings.TrimSpace(h.Get("Content-Encoding")); {
+	case enc == "", strings.EqualFold(enc, "identity"):
+		return body, nil
+	case strings.EqualFold(enc, "gzip"):
+		return gzip.NewReader(body)
+	default:
+		return nil, &unsupportedEncodingError{enc}
+	}
+}
+
+type unsupportedEncodingError struct {
+	encoding string
+}
+
+func (e *unsupportedEncodingError) Error() string {
+	return "rtbhttp: unsupported content encoding " + e.encoding
+}
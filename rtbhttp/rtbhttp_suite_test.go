@@ -0,0 +1,13 @@
+package rtbhttp_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRTBHTTP(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "RTBHTTP Suite")
+}
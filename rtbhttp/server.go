@@ -0,0 +1,383 @@
+package rtbhttp
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Bidder responds to OpenRTB 2.x bid requests.
+type Bidder interface {
+	// Bid returns response to req.
+	// Nil response (or response with neither seat bids nor NBR) means no-bid.
+	//
+	// ctx deadline is derived from req.TMax (see ServerOptions).
+	Bid(ctx context.Context, req *openrtb2.BidRequest) (*openrtb2.BidResponse, error)
+}
+
+// BidderFunc is an adapter to use ordinary functions as Bidder.
+type BidderFunc func(ctx context.Context, req *openrtb2.BidRequest) (*openrtb2.BidResponse, error)
+
+// Bid implements Bidder.
+func (f BidderFunc) Bid(ctx context.Context, req *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+	return f(ctx, req)
+}
+
+// Bidder3 responds to OpenRTB 3.0 bid requests.
+type Bidder3 interface {
+	// Bid returns response to req.
+	// Nil response (or response with neither seat bids nor NBR) means no-bid.
+	//
+	// ctx deadline is derived from req.OpenRTB.Request.TMax (see ServerOptions).
+	Bid(ctx context.Context, req *openrtb3.Body) (*openrtb3.Body, error)
+}
+
+// Bidder3Func is an adapter to use ordinary functions as Bidder3.
+type Bidder3Func func(ctx context.Context, req *openrtb3.Body) (*openrtb3.Body, error)
+
+// Bid implements Bidder3.
+func (f Bidder3Func) Bid(ctx context.Context, req *openrtb3.Body) (*openrtb3.Body, error) {
+	return f(ctx, req)
+}
+
+// ServerOptions configure handlers, returned by NewHandler and NewHandler3.
+type ServerOptions struct {
+	// Versions lists accepted HeaderVersion values (e.g., "2.5", "2.6").
+	// Empty means any version with the major version of the handler is accepted.
+	Versions []string
+
+	// RequireVersion makes requests without HeaderVersion rejected.
+	RequireVersion bool
+
+	// NetworkAllowance is subtracted from TMax, when deriving Bid context deadline,
+	// to leave time for the response to reach the caller.
+	NetworkAllowance time.Duration
+
+	// DefaultTMax is used for requests without TMax; zero means no deadline for such requests.
+	DefaultTMax time.Duration
+
+	// MaxBodySize limits size of (decompressed) request body, in bytes; zero means no limit.
+	MaxBodySize int64
+
+	// ErrorHandler, if set, is called with errors of request decoding and Bid.
+	ErrorHandler func(r *http.Request, err error)
+}
+
+// Errors, passed to ServerOptions.ErrorHandler (wrapped).
+var (
+	ErrInvalidRequest     = errors.New("rtbhttp: invalid request")
+	ErrUnsupportedVersion = errors.New("rtbhttp: unsupported OpenRTB version")
+	ErrBodyTooLarge       = errors.New("rtbhttp: request body too large")
+)
+
+// NewHandler returns HTTP handler, serving OpenRTB 2.x bid requests with b.
+//
+// Responses:
+//   - 405 for methods other than POST;
+//   - 400 with NBR = openrtb3.NoBidInvalidRequest for malformed requests or unsupported versions;
+//   - 200 with NBR = openrtb3.NoBidInsufficientTime if TMax is exhausted by NetworkAllowance;
+//   - 204 for no-bid (including Bid, failing because of context deadline);
+//   - 500 with NBR = openrtb3.NoBidTechnicalError for other Bid errors;
+//   - 200 with bid response otherwise.
+//
+// Gzip-compressed request bodies are accepted; responses are gzip-compressed, if the client accepts it.
+func NewHandler(b Bidder, opts ServerOptions) http.Handler {
+	return &server{
+		opts:    opts,
+		major:   "2",
+		version: DefaultVersion2,
+		newExchange: func() exchange {
+			return &exchange2{bidder: b}
+		},
+	}
+}
+
+// NewHandler3 returns HTTP handler, serving OpenRTB 3.0 bid requests with b.
+//
+// Responses are the same as for NewHandler.
+func NewHandler3(b Bidder3, opts ServerOptions) http.Handler {
+	return &server{
+		opts:    opts,
+		major:   "3",
+		version: DefaultVersion3,
+		newExchange: func() exchange {
+			return &exchange3{bidder: b}
+		},
+	}
+}
+
+// exchange is a single request-response transaction of a specific OpenRTB version.
+type exchange interface {
+	// decode decodes and validates request.
+	decode(r io.Reader) error
+
+	// tmax returns request TMax, in milliseconds.
+	tmax() int64
+
+	// bid returns response to decoded request, or nil for no-bid.
+	bid(ctx context.Context) (interface{}, error)
+
+	// noBid returns no-bid response with reason.
+	noBid(nbr openrtb3.NoBidReason) interface{}
+}
+
+type server struct {
+	opts        ServerOptions
+	major       string
+	version     string
+	newExchange func() exchange
+}
+
+// ServeHTTP implements http.Handler.
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	x := s.newExchange()
+	version := r.Header.Get(HeaderVersion)
+	switch {
+	case version == "" && s.opts.RequireVersion:
+		s.fail(w, r, s.version, http.StatusBadRequest, x.noBid(openrtb3.NoBidInvalidRequest),
+			fmt.Errorf("%w: missing %s header", ErrUnsupportedVersion, HeaderVersion))
+		return
+	case version == "":
+		version = s.version
+	case !versionAllowed(version, s.opts.Versions, s.major):
+		s.fail(w, r, s.version, http.StatusBadRequest, x.noBid(openrtb3.NoBidInvalidRequest),
+			fmt.Errorf("%w: %q", ErrUnsupportedVersion, version))
+		return
+	}
+
+	body, err := decodedBody(r.Header, r.Body)
+	if err == nil {
+		if s.opts.MaxBodySize > 0 {
+			body = &limitedReader{r: body, n: s.opts.MaxBodySize}
+		}
+		err = x.decode(body)
+	}
+	if err != nil {
+		if !errors.Is(err, ErrBodyTooLarge) && !errors.Is(err, ErrInvalidRequest) {
+			err = fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+		}
+		s.fail(w, r, version, http.StatusBadRequest, x.noBid(openrtb3.NoBidInvalidRequest), err)
+		return
+	}
+
+	ctx := r.Context()
+	tmax := time.Duration(x.tmax()) * time.Millisecond
+	if tmax <= 0 {
+		tmax = s.opts.DefaultTMax
+	}
+	if tmax > 0 {
+		budget := tmax - s.opts.NetworkAllowance - time.Since(start)
+		if budget <= 0 {
+			s.write(w, r, version, http.StatusOK, x.noBid(openrtb3.NoBidInsufficientTime))
+			return
+		}
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, budget)
+		defer cancel()
+	}
+
+	resp, err := x.bid(ctx)
+	switch {
+	case err != nil && ctx.Err() != nil:
+		s.fail(w, r, version, http.StatusNoContent, nil, err)
+	case err != nil:
+		s.fail(w, r, version, http.StatusInternalServerError, x.noBid(openrtb3.NoBidTechnicalError), err)
+	case resp == nil:
+		s.write(w, r, version, http.StatusNoContent, nil)
+	default:
+		s.write(w, r, version, http.StatusOK, resp)
+	}
+}
+
+func (s *server) fail(w http.ResponseWriter, r *http.Request, version string, status int, body interface{}, err error) {
+	if s.opts.ErrorHandler != nil {
+		s.opts.ErrorHandler(r, err)
+	}
+	s.write(w, r, version, status, body)
+}
+
+func (s *server) write(w http.ResponseWriter, r *http.Request, version string, status int, body interface{}) {
+	h := w.Header()
+	h.Set(HeaderVersion, version)
+	if body == nil {
+		w.WriteHeader(status)
+		return
+	}
+
+	buf, err := json.Marshal(body)
+	if err != nil {
+		if s.opts.ErrorHandler != nil {
+			s.opts.ErrorHandler(r, err)
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	h.Set("Content-Type", "application/json")
+	h.Add("Vary", "Accept-Encoding")
+	if !acceptsGzip(r.Header) {
+		w.WriteHeader(status)
+		_, _ = w.Write(buf)
+		return
+	}
+
+	h.Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	zw := gzip.NewWriter(w)
+	_, _ = zw.Write(buf)
+	_ = zw.Close()
+}
+
+// limitedReader is like io.LimitedReader, but fails with ErrBodyTooLarge when limit is exceeded.
+type limitedReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrBodyTooLarge
+	}
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrBodyTooLarge
+	}
+	return n, err
+}
+
+type exchange2 struct {
+	bidder Bidder
+	req    *openrtb2.BidRequest
+}
+
+func (x *exchange2) decode(r io.Reader) error {
+	req := new(openrtb2.BidRequest)
+	err := json.NewDecoder(r).Decode(req)
+	x.req = req
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case req.ID == "":
+		return fmt.Errorf("%w: missing id", ErrInvalidRequest)
+	case len(req.Imp) == 0:
+		return fmt.Errorf("%w: missing imp", ErrInvalidRequest)
+	}
+	for i, imp := range req.Imp {
+		if imp.ID == "" {
+			return fmt.Errorf("%w: missing imp[%d].id", ErrInvalidRequest, i)
+		}
+	}
+	return nil
+}
+
+func (x *exchange2) tmax() int64 {
+	return x.req.TMax
+}
+
+func (x *exchange2) bid(ctx context.Context) (interface{}, error) {
+	resp, err := x.bidder.Bid(ctx, x.req)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil || len(resp.SeatBid) == 0 && resp.NBR == nil {
+		return nil, nil
+	}
+	if resp.ID == "" {
+		resp.ID = x.req.ID
+	}
+	return resp, nil
+}
+
+func (x *exchange2) noBid(nbr openrtb3.NoBidReason) interface{} {
+	resp := &openrtb2.BidResponse{NBR: nbr.Ptr()}
+	if x.req != nil {
+		resp.ID = x.req.ID
+	}
+	return resp
+}
+
+type exchange3 struct {
+	bidder Bidder3
+	req    *openrtb3.Body
+}
+
+func (x *exchange3) decode(r io.Reader) error {
+	body := new(openrtb3.Body)
+	err := json.NewDecoder(r).Decode(body)
+	x.req = body
+	if err != nil {
+		return err
+	}
+
+	req := body.OpenRTB.Request
+	switch {
+	case req == nil:
+		return fmt.Errorf("%w: missing openrtb.request", ErrInvalidRequest)
+	case req.ID == "":
+		return fmt.Errorf("%w: missing openrtb.request.id", ErrInvalidRequest)
+	case len(req.Item) == 0:
+		return fmt.Errorf("%w: missing openrtb.request.item", ErrInvalidRequest)
+	}
+	return nil
+}
+
+func (x *exchange3) tmax() int64 {
+	return x.req.OpenRTB.Request.TMax
+}
+
+func (x *exchange3) bid(ctx context.Context) (interface{}, error) {
+	body, err := x.bidder.Bid(ctx, x.req)
+	if err != nil {
+		return nil, err
+	}
+	if body == nil || body.OpenRTB.Response == nil {
+		return nil, nil
+	}
+
+	resp := body.OpenRTB.Response
+	if len(resp.SeatBid) == 0 && resp.NBR == openrtb3.NoBidUnknownError {
+		return nil, nil
+	}
+	if resp.ID == "" {
+		resp.ID = x.req.OpenRTB.Request.ID
+	}
+	return body, nil
+}
+
+func (x *exchange3) noBid(nbr openrtb3.NoBidReason) interface{} {
+	body := &openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+		Ver:      DefaultVersion3,
+		Response: &openrtb3.Response{NBR: nbr},
+	}}
+	if x.req != nil {
+		body.OpenRTB.DomainSpec = x.req.OpenRTB.DomainSpec
+		body.OpenRTB.DomainVer = x.req.OpenRTB.DomainVer
+		if x.req.OpenRTB.Request != nil {
+			body.OpenRTB.Response.ID = x.req.OpenRTB.Request.ID
+		}
+	}
+	return body
+}
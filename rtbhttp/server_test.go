@@ -0,0 +1,220 @@
+package rtbhttp_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "github.com/prebid/openrtb/v20/rtbhttp"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func gzipped(s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write([]byte(s))
+	_ = zw.Close()
+	return buf.Bytes()
+}
+
+var _ = Describe("NewHandler", func() {
+	var (
+		opts   ServerOptions
+		bidder BidderFunc
+		errs   []error
+	)
+
+	BeforeEach(func() {
+		errs = nil
+		opts = ServerOptions{
+			ErrorHandler: func(_ *http.Request, err error) { errs = append(errs, err) },
+		}
+		bidder = func(_ context.Context, req *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			return &openrtb2.BidResponse{
+				SeatBid: []openrtb2.SeatBid{{Bid: []openrtb2.Bid{{ID: "bid", ImpID: req.Imp[0].ID, Price: 1}}}},
+			}, nil
+		}
+	})
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		NewHandler(bidder, opts).ServeHTTP(w, r)
+		return w
+	}
+
+	post := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/bid", strings.NewReader(body))
+	}
+
+	It("should respond with bids", func() {
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get(HeaderVersion)).To(Equal(DefaultVersion2))
+		Expect(w.Header().Get("Content-Type")).To(Equal("application/json"))
+		Expect(w.Body.String()).To(MatchJSON(`{"id":"req","seatbid":[{"bid":[{"id":"bid","impid":"1","price":1}]}]}`))
+	})
+
+	It("should support gzip request and response bodies", func() {
+		r := httptest.NewRequest(http.MethodPost, "/bid", bytes.NewReader(gzipped(`{"id":"req","imp":[{"id":"1"}]}`)))
+		r.Header.Set("Content-Encoding", "gzip")
+		r.Header.Set("Accept-Encoding", "deflate, gzip")
+		r.Header.Set(HeaderVersion, "2.5")
+
+		w := serve(r)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get(HeaderVersion)).To(Equal("2.5"))
+		Expect(w.Header().Get("Content-Encoding")).To(Equal("gzip"))
+
+		zr, err := gzip.NewReader(w.Body)
+		Expect(err).NotTo(HaveOccurred())
+		buf, err := ioutil.ReadAll(zr)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(buf).To(MatchJSON(`{"id":"req","seatbid":[{"bid":[{"id":"bid","impid":"1","price":1}]}]}`))
+	})
+
+	It("should respond with 204 for no-bid", func() {
+		bidder = func(context.Context, *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			return &openrtb2.BidResponse{ID: "req"}, nil
+		}
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(w.Body.Len()).To(BeZero())
+	})
+
+	It("should pass no-bid reason, set by bidder", func() {
+		bidder = func(context.Context, *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			return &openrtb2.BidResponse{NBR: openrtb3.NoBidUnmatchedUser.Ptr()}, nil
+		}
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(MatchJSON(`{"id":"req","nbr":8}`))
+	})
+
+	DescribeTable("should reject malformed requests", func(body string) {
+		w := serve(post(body))
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+
+		var resp openrtb2.BidResponse
+		Expect(json.Unmarshal(w.Body.Bytes(), &resp)).To(Succeed())
+		Expect(resp.NBR.Val()).To(Equal(openrtb3.NoBidInvalidRequest))
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0]).To(MatchError(ErrInvalidRequest))
+	},
+		Entry("invalid JSON", `{"id":`),
+		Entry("missing ID", `{"imp":[{"id":"1"}]}`),
+		Entry("missing imp", `{"id":"req"}`),
+		Entry("missing imp ID", `{"id":"req","imp":[{}]}`),
+	)
+
+	It("should reject unsupported versions", func() {
+		opts.Versions = []string{"2.6"}
+		r := post(`{"id":"req","imp":[{"id":"1"}]}`)
+		r.Header.Set(HeaderVersion, "2.5")
+
+		w := serve(r)
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(MatchJSON(`{"id":"","nbr":2}`))
+		Expect(errs[0]).To(MatchError(ErrUnsupportedVersion))
+
+		opts.Versions = nil
+		opts.RequireVersion = true
+		Expect(serve(post(`{"id":"req","imp":[{"id":"1"}]}`)).Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("should reject oversized bodies", func() {
+		opts.MaxBodySize = 16
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(errs[0]).To(MatchError(ErrBodyTooLarge))
+	})
+
+	It("should reject methods other than POST", func() {
+		w := serve(httptest.NewRequest(http.MethodGet, "/bid", nil))
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+	})
+
+	It("should derive context deadline from TMax", func() {
+		opts.NetworkAllowance = 20 * time.Millisecond
+		var remaining time.Duration
+		bidder = func(ctx context.Context, _ *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			deadline, ok := ctx.Deadline()
+			Expect(ok).To(BeTrue())
+			remaining = time.Until(deadline)
+
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}
+
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}],"tmax":100}`))
+		Expect(w.Code).To(Equal(http.StatusNoContent))
+		Expect(remaining).To(BeNumerically("~", 80*time.Millisecond, 10*time.Millisecond))
+	})
+
+	It("should respond with insufficient time, if TMax is exhausted", func() {
+		opts.NetworkAllowance = 100 * time.Millisecond
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}],"tmax":50}`))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(MatchJSON(`{"id":"req","nbr":15}`))
+	})
+
+	It("should respond with technical error on bidder failure", func() {
+		bidder = func(context.Context, *openrtb2.BidRequest) (*openrtb2.BidResponse, error) {
+			return nil, errors.New("boom")
+		}
+		w := serve(post(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(w.Code).To(Equal(http.StatusInternalServerError))
+		Expect(w.Body.String()).To(MatchJSON(`{"id":"req","nbr":1}`))
+		Expect(errs).To(ConsistOf(MatchError("boom")))
+	})
+
+	It("should work over HTTP", func() {
+		srv := httptest.NewServer(NewHandler(bidder, opts))
+		defer srv.Close()
+
+		resp, err := http.Post(srv.URL, "application/json", strings.NewReader(`{"id":"req","imp":[{"id":"1"}]}`))
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var br openrtb2.BidResponse
+		Expect(json.NewDecoder(resp.Body).Decode(&br)).To(Succeed())
+		Expect(br.SeatBid[0].Bid[0].ID).To(Equal("bid"))
+	})
+})
+
+var _ = Describe("NewHandler3", func() {
+	It("should serve OpenRTB 3.0 requests", func() {
+		h := NewHandler3(Bidder3Func(func(_ context.Context, req *openrtb3.Body) (*openrtb3.Body, error) {
+			return &openrtb3.Body{OpenRTB: openrtb3.OpenRTB{
+				Ver:       "3.0",
+				DomainVer: req.OpenRTB.DomainVer,
+				Response:  &openrtb3.Response{SeatBid: []openrtb3.SeatBid{{Seat: "seat"}}},
+			}}, nil
+		}), ServerOptions{})
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/bid", strings.NewReader(
+			`{"openrtb":{"ver":"3.0","domainver":"1.0","request":{"id":"req","item":[{"id":"1"}]}}}`)))
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Header().Get(HeaderVersion)).To(Equal(DefaultVersion3))
+		Expect(w.Body.String()).To(MatchJSON(`{"openrtb":{"ver":"3.0","domainver":"1.0","response":{"id":"req","seatbid":[{"seat":"seat"}]}}}`))
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/bid", strings.NewReader(`{"openrtb":{"domainver":"1.0"}}`)))
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(MatchJSON(`{"openrtb":{"ver":"3.0","domainver":"1.0","response":{"nbr":2}}}`))
+	})
+})
@@ -0,0 +1,53 @@
+package rtbjson_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/rtbjson"
+)
+
+func benchmarkData(b *testing.B) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("..", "openrtb2", "testdata", "bid-request", "2.6", "pmp-with-direct-deal.json"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkUnmarshal(b *testing.B) {
+	data := benchmarkData(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var req openrtb2.BidRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPeek(b *testing.B) {
+	data := benchmarkData(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := rtbjson.Peek(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPeekPaths(b *testing.B) {
+	data := benchmarkData(b)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := rtbjson.PeekPaths(data, "id", "imp.bidfloor", "site.domain"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
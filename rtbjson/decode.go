@@ -0,0 +1,289 @@
+// Package rtbjson provides fast and hardened JSON decoding of OpenRTB bid requests:
+// partial decoding for pre-filtering, decoding with size, depth and cardinality limits, and strict and lenient decoding modes
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb2.x/blob/main/2.6.md#31---object-bidrequest-
+package rtbjson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// DecodeOptions configures Decode; zero limits mean no limit, except for nesting (see MaxDepth).
+type DecodeOptions struct {
+	MaxBytes    int // size of input
+	MaxImps     int // impressions (openrtb2 imp), or items (openrtb3 item)
+	MaxFormats  int // formats per object (openrtb2 format, adcom1 displayfmt)
+	MaxDeals    int // deals per object (openrtb2 deals, openrtb3 deal)
+	MaxEIDs     int // extended identifiers per object (eids)
+	MaxSegments int // segments per object (segment)
+	MaxExtBytes int // size of each extension object (ext)
+	MaxDepth    int // nesting of objects and arrays (at most 10000, as in encoding/json, even if zero)
+
+	Mode Mode // handling of unknown and mistyped fields
+}
+
+// DefaultDecodeOptions returns DecodeOptions with limits, generous for legitimate traffic.
+func DefaultDecodeOptions() DecodeOptions {
+	return DecodeOptions{
+		MaxBytes:    1 << 20,
+		MaxImps:     100,
+		MaxFormats:  50,
+		MaxDeals:    200,
+		MaxEIDs:     50,
+		MaxSegments: 200,
+		MaxExtBytes: 64 << 10,
+		MaxDepth:    32,
+	}
+}
+
+// Limit identifies a limit of DecodeOptions.
+type Limit int8
+
+// Limit options.
+const (
+	LimitBytes    Limit = 1 // DecodeOptions.MaxBytes
+	LimitImps     Limit = 2 // DecodeOptions.MaxImps
+	LimitFormats  Limit = 3 // DecodeOptions.MaxFormats
+	LimitDeals    Limit = 4 // DecodeOptions.MaxDeals
+	LimitEIDs     Limit = 5 // DecodeOptions.MaxEIDs
+	LimitSegments Limit = 6 // DecodeOptions.MaxSegments
+	LimitExtBytes Limit = 7 // DecodeOptions.MaxExtBytes
+	LimitDepth    Limit = 8 // DecodeOptions.MaxDepth
+)
+
+// String implements fmt.Stringer.
+func (l Limit) String() string {
+	switch l {
+	case LimitBytes:
+		return "bytes"
+	case LimitImps:
+		return "imps"
+	case LimitFormats:
+		return "formats"
+	case LimitDeals:
+		return "deals"
+	case LimitEIDs:
+		return "eids"
+	case LimitSegments:
+		return "segments"
+	case LimitExtBytes:
+		return "ext bytes"
+	case LimitDepth:
+		return "depth"
+	}
+	return fmt.Sprintf("Limit(%d)", int8(l))
+}
+
+// LimitError is returned for input, exceeding a limit of DecodeOptions.
+type LimitError struct {
+	Limit Limit
+	Max   int
+	Path  string // JSON path of the offending value (e.g., "imp[3].banner.format"); empty for LimitBytes
+}
+
+// Error implements error.
+func (e *LimitError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("rtbjson: %s limit (%d) exceeded", e.Limit, e.Max)
+	}
+	return fmt.Sprintf("rtbjson: %s limit (%d) exceeded at %s", e.Limit, e.Max, e.Path)
+}
+
+// NoBidReason returns no-bid reason for the error: openrtb3.NoBidInvalidRequest.
+func (e *LimitError) NoBidReason() openrtb3.NoBidReason {
+	return openrtb3.NoBidInvalidRequest
+}
+
+// NoBidReason returns no-bid reason for a decoding error:
+// openrtb3.NoBidInvalidRequest for invalid (or limit exceeding) input, openrtb3.NoBidTechnicalError otherwise.
+func NoBidReason(err error) openrtb3.NoBidReason {
+	var (
+		limitErr  *LimitError
+		syntaxErr *SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		jsonErr   *json.SyntaxError
+		strictErr *StrictError
+	)
+	if errors.As(err, &limitErr) || errors.As(err, &syntaxErr) || errors.As(err, &typeErr) || errors.As(err, &jsonErr) || errors.As(err, &strictErr) {
+		return openrtb3.NoBidInvalidRequest
+	}
+	return openrtb3.NoBidTechnicalError
+}
+
+// DecodeBidRequest decodes OpenRTB 2.x bid request from data, see Decode.
+func DecodeBidRequest(data []byte, opts DecodeOptions) (*openrtb2.BidRequest, error) {
+	req := new(openrtb2.BidRequest)
+	if err := Decode(data, req, opts); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// DecodeBody decodes OpenRTB 3.0 body from data, see Decode.
+func DecodeBody(data []byte, opts DecodeOptions) (*openrtb3.Body, error) {
+	body := new(openrtb3.Body)
+	if err := Decode(data, body, opts); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// Decode decodes JSON data into v (a pointer, as for json.Unmarshal), enforcing limits and mode of opts.
+//
+// Limits are checked by a scan of the input, which precedes decoding, and stops at the first exceeded limit,
+// so oversized input is rejected without allocations for its contents.
+// Modes, other than ModeDefault, take another scan of the input along with the type of v.
+func Decode(data []byte, v interface{}, opts DecodeOptions) error {
+	if opts.MaxBytes > 0 && len(data) > opts.MaxBytes {
+		return &LimitError{Limit: LimitBytes, Max: opts.MaxBytes}
+	}
+
+	l := &limiter{s: scanner{data: data}, opts: &opts}
+	if err := l.value(); err != nil {
+		return err
+	}
+	if err := l.s.end(); err != nil {
+		return err
+	}
+
+	if opts.Mode != ModeDefault {
+		var err error
+		if data, err = check(data, v, opts.Mode); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// limiter checks limits of DecodeOptions, while scanning input.
+type limiter struct {
+	s     scanner
+	opts  *DecodeOptions
+	depth int
+	path  []pathElem
+}
+
+// pathElem is an element of JSON path: object key or array index.
+type pathElem struct {
+	key   []byte
+	index int
+}
+
+// arrayLimits are limits of arrays by key.
+var arrayLimits = map[string]Limit{
+	"imp":        LimitImps,
+	"item":       LimitImps,
+	"format":     LimitFormats,
+	"displayfmt": LimitFormats,
+	"deals":      LimitDeals,
+	"deal":       LimitDeals,
+	"eids":       LimitEIDs,
+	"segment":    LimitSegments,
+}
+
+func (o *DecodeOptions) max(l Limit) int {
+	switch l {
+	case LimitImps:
+		return o.MaxImps
+	case LimitFormats:
+		return o.MaxFormats
+	case LimitDeals:
+		return o.MaxDeals
+	case LimitEIDs:
+		return o.MaxEIDs
+	case LimitSegments:
+		return o.MaxSegments
+	}
+	return 0
+}
+
+// maxDepth returns MaxDepth, bounded by maxNesting.
+func (o *DecodeOptions) maxDepth() int {
+	if o.MaxDepth <= 0 || o.MaxDepth > maxNesting {
+		return maxNesting
+	}
+	return o.MaxDepth
+}
+
+// value consumes the next value, checking limits.
+func (l *limiter) value() error {
+	b, err := l.s.peek()
+	if err != nil {
+		return err
+	}
+	if b != '{' && b != '[' {
+		return l.s.skip()
+	}
+
+	l.depth++
+	if max := l.opts.maxDepth(); l.depth > max {
+		return l.errorf(LimitDepth, max)
+	}
+	defer func() { l.depth-- }()
+
+	if b == '{' {
+		return l.s.object(func(key []byte) error {
+			l.path = append(l.path, pathElem{key: key, index: -1})
+			defer func() { l.path = l.path[:len(l.path)-1] }()
+
+			if string(key) != "ext" || l.opts.MaxExtBytes <= 0 {
+				return l.value()
+			}
+			if _, err := l.s.peek(); err != nil {
+				return err
+			}
+			start := l.s.off
+			if err := l.value(); err != nil {
+				return err
+			}
+			if l.s.off-start > l.opts.MaxExtBytes {
+				return l.errorf(LimitExtBytes, l.opts.MaxExtBytes)
+			}
+			return nil
+		})
+	}
+
+	max := 0
+	if n := len(l.path); n != 0 && l.path[n-1].index == -1 {
+		if limit, ok := arrayLimits[string(l.path[n-1].key)]; ok {
+			max = l.opts.max(limit)
+		}
+	}
+	return l.s.array(func(i int) error {
+		if max > 0 && i >= max {
+			return l.errorf(arrayLimits[string(l.path[len(l.path)-1].key)], max)
+		}
+		l.path = append(l.path, pathElem{index: i})
+		defer func() { l.path = l.path[:len(l.path)-1] }()
+		return l.value()
+	})
+}
+
+func (l *limiter) errorf(limit Limit, max int) error {
+	return &LimitError{Limit: limit, Max: max, Path: formatPath(l.path)}
+}
+
+// formatPath returns JSON path, e.g., "imp[0].banner.format".
+func formatPath(path []pathElem) string {
+	var sb strings.Builder
+	for _, e := range path {
+		if e.index != -1 {
+			sb.WriteByte('[')
+			sb.WriteString(strconv.Itoa(e.index))
+			sb.WriteByte(']')
+			continue
+		}
+		if sb.Len() != 0 {
+			sb.WriteByte('.')
+		}
+		sb.Write(e.key)
+	}
+	return sb.String()
+}
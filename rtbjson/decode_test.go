@@ -0,0 +1,109 @@
+package rtbjson_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	. "github.com/prebid/openrtb/v20/rtbjson"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+// repeat returns JSON array of n elements.
+func repeat(elem string, n int) string {
+	return "[" + strings.TrimSuffix(strings.Repeat(elem+",", n), ",") + "]"
+}
+
+var _ = Describe("Decode", func() {
+	It("should decode testdata within default limits", func() {
+		data := readFile("2.6/pmp-with-direct-deal.json")
+		req, err := DecodeBidRequest(data, DefaultDecodeOptions())
+		Expect(err).NotTo(HaveOccurred())
+
+		var expected openrtb2.BidRequest
+		Expect(json.Unmarshal(data, &expected)).To(Succeed())
+		Expect(req).To(Equal(&expected))
+
+		data, err = ioutil.ReadFile(filepath.Join("..", "openrtb3", "testdata", "request.json"))
+		Expect(err).NotTo(HaveOccurred())
+		body, err := DecodeBody(data, DefaultDecodeOptions())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body.OpenRTB.Request).NotTo(BeNil())
+	})
+
+	DescribeTable(
+		"should enforce limits",
+
+		func(opts DecodeOptions, data string, expected *LimitError) {
+			_, err := DecodeBidRequest([]byte(data), opts)
+			Expect(err).To(Equal(expected))
+			Expect(NoBidReason(err)).To(Equal(openrtb3.NoBidInvalidRequest))
+
+			_, err = DecodeBidRequest([]byte(data), DecodeOptions{})
+			Expect(err).NotTo(HaveOccurred())
+		},
+
+		Entry("bytes", DecodeOptions{MaxBytes: 10}, `{"id":"12345"}`,
+			&LimitError{Limit: LimitBytes, Max: 10}),
+		Entry("imps", DecodeOptions{MaxImps: 2}, `{"id":"1","imp":`+repeat(`{"id":"1"}`, 3)+`}`,
+			&LimitError{Limit: LimitImps, Max: 2, Path: "imp"}),
+		Entry("formats", DecodeOptions{MaxFormats: 2}, `{"id":"1","imp":[{"id":"1"},{"id":"2","banner":{"format":`+repeat(`{"w":1,"h":1}`, 3)+`}}]}`,
+			&LimitError{Limit: LimitFormats, Max: 2, Path: "imp[1].banner.format"}),
+		Entry("deals", DecodeOptions{MaxDeals: 1}, `{"id":"1","imp":[{"id":"1","pmp":{"deals":`+repeat(`{"id":"d"}`, 2)+`}}]}`,
+			&LimitError{Limit: LimitDeals, Max: 1, Path: "imp[0].pmp.deals"}),
+		Entry("eids", DecodeOptions{MaxEIDs: 1}, `{"id":"1","imp":[],"user":{"eids":`+repeat(`{"source":"s"}`, 2)+`}}`,
+			&LimitError{Limit: LimitEIDs, Max: 1, Path: "user.eids"}),
+		Entry("segments", DecodeOptions{MaxSegments: 3}, `{"id":"1","imp":[],"user":{"data":[{"segment":`+repeat(`{"id":"s"}`, 4)+`}]}}`,
+			&LimitError{Limit: LimitSegments, Max: 3, Path: "user.data[0].segment"}),
+		Entry("ext bytes", DecodeOptions{MaxExtBytes: 16}, `{"id":"1","imp":[{"id":"1","ext":{"bidder":"0123456789"}}]}`,
+			&LimitError{Limit: LimitExtBytes, Max: 16, Path: "imp[0].ext"}),
+		Entry("depth", DecodeOptions{MaxDepth: 4}, `{"id":"1","imp":[],"ext":{"a":{"b":{"c":{}}}}}`,
+			&LimitError{Limit: LimitDepth, Max: 4, Path: "ext.a.b.c"}),
+	)
+
+	It("should bound nesting even without limits", func() {
+		deep := []byte(`{"id":"1","ext":` + strings.Repeat("[", 3000000))
+		for _, opts := range []DecodeOptions{{}, {MaxDepth: 1 << 20}, {Mode: ModeStrict}, {Mode: ModeLenient}} {
+			var req openrtb2.BidRequest
+			err := Decode(deep, &req, opts)
+			Expect(err).To(BeAssignableToTypeOf(&LimitError{}))
+			Expect(err.(*LimitError).Limit).To(Equal(LimitDepth))
+			Expect(err.(*LimitError).Max).To(Equal(10000))
+		}
+	})
+
+	It("should enforce limits of openrtb3 items and deals", func() {
+		_, err := DecodeBody([]byte(`{"openrtb":{"request":{"id":"1","item":`+repeat(`{"id":"1"}`, 3)+`}}}`), DecodeOptions{MaxImps: 2})
+		Expect(err).To(Equal(&LimitError{Limit: LimitImps, Max: 2, Path: "openrtb.request.item"}))
+
+		_, err = DecodeBody([]byte(`{"openrtb":{"request":{"id":"1","item":[{"id":"1","deal":`+repeat(`{"id":"d"}`, 3)+`}]}}}`), DecodeOptions{MaxDeals: 2})
+		Expect(err).To(Equal(&LimitError{Limit: LimitDeals, Max: 2, Path: "openrtb.request.item[0].deal"}))
+	})
+
+	It("should stop scanning at the first exceeded limit", func() {
+		data := `{"id":"1","imp":` + repeat(`{"id":"1"}`, 100000) + `}`
+		_, err := DecodeBidRequest([]byte(data[:len(data)-10]), DecodeOptions{MaxImps: 100})
+		Expect(err).To(Equal(&LimitError{Limit: LimitImps, Max: 100, Path: "imp"}))
+	})
+
+	It("should report errors of decoding", func() {
+		_, err := DecodeBidRequest([]byte(`{"id":1}`), DefaultDecodeOptions())
+		Expect(err).To(BeAssignableToTypeOf(&json.UnmarshalTypeError{}))
+		Expect(NoBidReason(err)).To(Equal(openrtb3.NoBidInvalidRequest))
+
+		_, err = DecodeBidRequest([]byte(`{"id":"1"`), DefaultDecodeOptions())
+		Expect(err).To(BeAssignableToTypeOf(&SyntaxError{}))
+		Expect(NoBidReason(fmt.Errorf("wrapped: %w", err))).To(Equal(openrtb3.NoBidInvalidRequest))
+
+		Expect(NoBidReason(errors.New("other"))).To(Equal(openrtb3.NoBidTechnicalError))
+	})
+})
@@ -0,0 +1,308 @@
+package rtbjson
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Peeked holds fields of a bid request, commonly used for pre-filtering.
+type Peeked struct {
+	ID         string                // id
+	TMax       int64                 // tmax
+	Imps       []PeekedImp           // imp
+	SiteDomain string                // site.domain
+	AppBundle  string                // app.bundle
+	DeviceIP   string                // device.ip
+	SChain     *openrtb2.SupplyChain // source.schain
+
+	data []byte
+}
+
+// PeekedImp holds fields of an impression.
+type PeekedImp struct {
+	ID          string   // imp[].id
+	BidFloor    *float64 // imp[].bidfloor; nil if absent (as openrtb2.Imp.BidFloor)
+	BidFloorCur string   // imp[].bidfloorcur
+}
+
+// Peek extracts Peeked fields from JSON of a bid request, without decoding it entirely.
+//
+// The whole input is scanned (and its syntax validated), but other fields are skipped without allocations.
+// Data is retained by Peeked for Decode, and must not be modified.
+func Peek(data []byte) (*Peeked, error) {
+	p := &Peeked{data: data}
+	s := &scanner{data: data}
+
+	err := s.object(func(key []byte) error {
+		switch string(key) {
+		case "id":
+			return s.string(&p.ID, "id")
+		case "tmax":
+			return s.int(&p.TMax, "tmax")
+		case "imp":
+			return s.nullable(func() error {
+				return s.array(func(i int) error {
+					p.Imps = append(p.Imps, PeekedImp{})
+					imp := &p.Imps[i]
+					return s.nullable(func() error {
+						return s.object(func(key []byte) error {
+							switch string(key) {
+							case "id":
+								return s.string(&imp.ID, "imp.id")
+							case "bidfloor":
+								return s.float(&imp.BidFloor, "imp.bidfloor")
+							case "bidfloorcur":
+								return s.string(&imp.BidFloorCur, "imp.bidfloorcur")
+							}
+							return s.skip()
+						})
+					})
+				})
+			})
+		case "site":
+			return s.field("domain", &p.SiteDomain, "site.domain")
+		case "app":
+			return s.field("bundle", &p.AppBundle, "app.bundle")
+		case "device":
+			return s.field("ip", &p.DeviceIP, "device.ip")
+		case "source":
+			return s.nullable(func() error {
+				return s.object(func(key []byte) error {
+					if string(key) != "schain" {
+						return s.skip()
+					}
+					start := s.off
+					raw, err := s.value()
+					if err != nil {
+						return err
+					}
+					if err := json.Unmarshal(raw, &p.SChain); err != nil {
+						if te, ok := err.(*json.UnmarshalTypeError); ok {
+							te.Offset += int64(start)
+							te.Field = "source.schain." + te.Field
+						}
+						return err
+					}
+					return nil
+				})
+			})
+		}
+		return s.skip()
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.end(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Decode decodes the entire bid request from the data, Peeked was extracted from.
+func (p *Peeked) Decode() (*openrtb2.BidRequest, error) {
+	req := new(openrtb2.BidRequest)
+	if err := json.Unmarshal(p.data, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// PeekPaths extracts raw JSON values at dot-separated paths (e.g., "site.domain") from data, in a single scan.
+//
+// Arrays are traversed: "imp.bidfloor" matches floors of all impressions, in order.
+// Values are slices of data (not copied); paths without matches are absent from the result.
+func PeekPaths(data []byte, paths ...string) (map[string][]json.RawMessage, error) {
+	root := &pathNode{}
+	for _, path := range paths {
+		n := root
+		for _, seg := range strings.Split(path, ".") {
+			if n.children == nil {
+				n.children = make(map[string]*pathNode)
+			}
+			c, ok := n.children[seg]
+			if !ok {
+				c = &pathNode{}
+				n.children[seg] = c
+			}
+			n = c
+		}
+		n.paths = append(n.paths, path)
+	}
+
+	res := make(map[string][]json.RawMessage)
+	s := &scanner{data: data}
+	if err := s.walk(root, res); err != nil {
+		return nil, err
+	}
+	if err := s.end(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// pathNode is a node of a trie of paths.
+type pathNode struct {
+	children map[string]*pathNode
+	paths    []string // paths, ending at the node
+}
+
+// walk consumes the next value, collecting values at paths of n (and its children).
+func (s *scanner) walk(n *pathNode, res map[string][]json.RawMessage) error {
+	b, err := s.peek()
+	if err != nil {
+		return err
+	}
+	start := s.off
+
+	switch {
+	case len(n.children) == 0 || b != '{' && b != '[':
+		_, err = s.value()
+	case b == '{':
+		err = s.object(func(key []byte) error {
+			if c, ok := n.children[string(key)]; ok {
+				return s.walk(c, res)
+			}
+			return s.skip()
+		})
+	default:
+		elem := n
+		if len(n.paths) != 0 {
+			elem = &pathNode{children: n.children} // elements are not collected themselves
+		}
+		err = s.array(func(int) error {
+			return s.walk(elem, res)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, path := range n.paths {
+		res[path] = append(res[path], s.data[start:s.off])
+	}
+	return nil
+}
+
+// skip consumes the next value.
+func (s *scanner) skip() error {
+	_, err := s.value()
+	return err
+}
+
+// nullable consumes null, or calls fn to consume the next value.
+func (s *scanner) nullable(fn func() error) error {
+	if b, err := s.peek(); err != nil {
+		return err
+	} else if b == 'n' {
+		return s.literal("null")
+	}
+	return fn()
+}
+
+// field consumes an object (or null), decoding its string field name to dst.
+func (s *scanner) field(name string, dst *string, path string) error {
+	return s.nullable(func() error {
+		return s.object(func(key []byte) error {
+			if string(key) == name {
+				return s.string(dst, path)
+			}
+			return s.skip()
+		})
+	})
+}
+
+var (
+	stringType  = reflect.TypeOf("")
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+)
+
+// string consumes a string (or null) into dst.
+func (s *scanner) string(dst *string, path string) error {
+	return s.nullable(func() error {
+		b, _ := s.peek()
+		if b != '"' {
+			return s.typeError(stringType, path)
+		}
+		raw, err := s.str()
+		if err != nil {
+			return err
+		}
+		*dst = string(unquote(raw))
+		return nil
+	})
+}
+
+// int consumes an integer (or null) into dst.
+func (s *scanner) int(dst *int64, path string) error {
+	return s.nullable(func() error {
+		start := s.off
+		raw, err := s.value()
+		if err != nil {
+			return err
+		}
+		n, err := strconv.ParseInt(string(raw), 10, 64)
+		if err != nil {
+			s.off = start
+			return s.typeError(int64Type, path)
+		}
+		*dst = n
+		return nil
+	})
+}
+
+// float consumes a number (or null) into dst, allocating it.
+func (s *scanner) float(dst **float64, path string) error {
+	return s.nullable(func() error {
+		start := s.off
+		raw, err := s.value()
+		if err != nil {
+			return err
+		}
+		if raw[0] != '-' && (raw[0] < '0' || raw[0] > '9') {
+			s.off = start
+			return s.typeError(float64Type, path)
+		}
+		f, err := strconv.ParseFloat(string(raw), 64)
+		if err != nil {
+			s.off = start
+			return s.typeError(float64Type, path)
+		}
+		*dst = &f
+		return nil
+	})
+}
+
+// typeError returns error for the value at current offset, not matching type t.
+func (s *scanner) typeError(t reflect.Type, path string) error {
+	start := s.off
+	raw, err := s.value()
+	if err != nil {
+		return err
+	}
+	return &json.UnmarshalTypeError{Value: jsonType(raw), Type: t, Offset: int64(start), Field: path}
+}
+
+// jsonType returns JSON type of raw value, as encoding/json reports it.
+func jsonType(raw []byte) string {
+	switch raw[0] {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "bool"
+	case 'n':
+		return "null"
+	}
+	if _, err := strconv.ParseInt(string(raw), 10, 64); err != nil {
+		return "number " + string(raw)
+	}
+	return "number"
+}
@@ -0,0 +1,175 @@
+package rtbjson_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	. "github.com/prebid/openrtb/v20/rtbjson"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func readFile(name string) []byte {
+	data, err := ioutil.ReadFile(filepath.Join("..", "openrtb2", "testdata", "bid-request", name))
+	Expect(err).NotTo(HaveOccurred())
+	return data
+}
+
+var _ = Describe("Peek", func() {
+	DescribeTable(
+		"should match full decoding",
+
+		func(name string) {
+			data := readFile(name)
+			var expected openrtb2.BidRequest
+			Expect(json.Unmarshal(data, &expected)).To(Succeed())
+
+			p, err := Peek(data)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.ID).To(Equal(expected.ID))
+			Expect(p.TMax).To(Equal(expected.TMax))
+			Expect(p.Imps).To(HaveLen(len(expected.Imp)))
+			for i, imp := range expected.Imp {
+				Expect(p.Imps[i]).To(Equal(PeekedImp{ID: imp.ID, BidFloor: imp.BidFloor, BidFloorCur: imp.BidFloorCur}))
+			}
+			if expected.Site != nil {
+				Expect(p.SiteDomain).To(Equal(expected.Site.Domain))
+			}
+			if expected.App != nil {
+				Expect(p.AppBundle).To(Equal(expected.App.Bundle))
+			}
+			if expected.Device != nil {
+				Expect(p.DeviceIP).To(Equal(expected.Device.IP))
+			}
+
+			req, err := p.Decode()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(req).To(Equal(&expected))
+		},
+
+		Entry("2.5 simple banner", "2.5/simple-banner.json"),
+		Entry("2.5 mobile", "2.5/mobile.json"),
+		Entry("2.6 expandable creative", "2.6/expandable-creative.json"),
+		Entry("2.6 mobile", "2.6/mobile.json"),
+		Entry("2.6 video", "2.6/video.json"),
+		Entry("2.6 pmp with direct deal", "2.6/pmp-with-direct-deal.json"),
+	)
+
+	It("should extract supply chain and escaped strings", func() {
+		p, err := Peek([]byte(`{
+			"id": "a\"bé",
+			"imp": [{"id": "1", "bidfloor": 1.5e0, "banner": {"format": [{"w": 300, "h": 250}]}}, {"id": "2"}],
+			"site": null,
+			"app": {"bundle": "com.example", "publisher": {"id": "p"}},
+			"device": {"ip": "1.2.3.0"},
+			"source": {"tid": "t", "schain": {"complete": 1, "nodes": [{"asi": "exchange.com", "sid": "1", "hp": 1}], "ver": "1.0"}},
+			"ext": {"id": "nested"}
+		}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.ID).To(Equal(`a"bé`))
+		Expect(p.Imps).To(Equal([]PeekedImp{{ID: "1", BidFloor: openrtb2.Float64Ptr(1.5)}, {ID: "2"}}))
+		Expect(p.AppBundle).To(Equal("com.example"))
+		Expect(p.DeviceIP).To(Equal("1.2.3.0"))
+		Expect(p.SChain).NotTo(BeNil())
+		Expect(p.SChain.Nodes).To(HaveLen(1))
+		Expect(p.SChain.Nodes[0].ASI).To(Equal("exchange.com"))
+	})
+
+	DescribeTable(
+		"should reject",
+
+		func(data string, expected interface{}) {
+			_, err := Peek([]byte(data))
+			Expect(err).To(BeAssignableToTypeOf(expected))
+			Expect(json.Unmarshal([]byte(data), new(openrtb2.BidRequest))).NotTo(Succeed())
+		},
+
+		Entry("truncated input", `{"id":"1","imp":[{"id":"1"}`, &SyntaxError{}),
+		Entry("trailing data", `{"id":"1"} {}`, &SyntaxError{}),
+		Entry("invalid number", `{"id":"1","ext":{"x":01}}`, &SyntaxError{}),
+		Entry("invalid escape", `{"id":"\x"}`, &SyntaxError{}),
+		Entry("missing comma", `{"id":"1" "tmax":1}`, &SyntaxError{}),
+		Entry("invalid literal", `{"id":"1","test":tru}`, &SyntaxError{}),
+		Entry("mistyped id", `{"id":1}`, &json.UnmarshalTypeError{}),
+		Entry("mistyped tmax", `{"id":"1","tmax":100.5}`, &json.UnmarshalTypeError{}),
+		Entry("mistyped bidfloor", `{"id":"1","imp":[{"id":"1","bidfloor":"1.5"}]}`, &json.UnmarshalTypeError{}),
+		Entry("mistyped schain", `{"id":"1","source":{"schain":{"complete":"1"}}}`, &json.UnmarshalTypeError{}),
+	)
+
+	It("should distinguish absent floors from explicit zero", func() {
+		p, err := Peek([]byte(`{"id":"1","imp":[{"id":"1"},{"id":"2","bidfloor":0},{"id":"3","bidfloor":null}]}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Imps).To(HaveLen(3))
+		Expect(p.Imps[0].BidFloor).To(BeNil())
+		Expect(p.Imps[1].BidFloor).To(Equal(openrtb2.Float64Ptr(0)))
+		Expect(p.Imps[2].BidFloor).To(BeNil())
+
+		req, err := p.Decode()
+		Expect(err).NotTo(HaveOccurred())
+		for i, imp := range req.Imp {
+			Expect(p.Imps[i].BidFloor).To(Equal(imp.BidFloor))
+		}
+	})
+
+	It("should reject deeply nested input", func() {
+		data := []byte(`{"x":` + strings.Repeat("[", 3000000))
+		_, err := Peek(data)
+		Expect(err).To(MatchError(ContainSubstring("exceeded max depth")))
+		Expect(err).To(BeAssignableToTypeOf(&SyntaxError{}))
+
+		_, err = Peek([]byte(`{"x":` + strings.Repeat("[", 9999) + strings.Repeat("]", 9999) + `}`))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should report paths of mistyped fields", func() {
+		_, err := Peek([]byte(`{"id":"1","imp":[{"id":"1","bidfloor":"1.5"}]}`))
+		Expect(err).To(MatchError(ContainSubstring("imp.bidfloor")))
+	})
+})
+
+var _ = Describe("PeekPaths", func() {
+	It("should extract values at paths", func() {
+		data := readFile("2.6/pmp-with-direct-deal.json")
+		res, err := PeekPaths(data, "id", "imp.id", "imp.pmp.deals.id", "site.publisher", "site.publisher.id", "missing.path")
+		Expect(err).NotTo(HaveOccurred())
+
+		var expected openrtb2.BidRequest
+		Expect(json.Unmarshal(data, &expected)).To(Succeed())
+
+		Expect(res).To(HaveLen(5))
+		Expect(res["id"]).To(Equal([]json.RawMessage{json.RawMessage(`"` + expected.ID + `"`)}))
+		Expect(res["imp.id"]).To(HaveLen(len(expected.Imp)))
+		Expect(res["imp.pmp.deals.id"]).To(HaveLen(len(expected.Imp[0].PMP.Deals)))
+		Expect(res["site.publisher"]).To(HaveLen(1))
+		Expect(res["site.publisher"][0]).To(MatchJSON(mustMarshal(expected.Site.Publisher)))
+		Expect(res["site.publisher.id"]).To(Equal([]json.RawMessage{json.RawMessage(`"` + expected.Site.Publisher.ID + `"`)}))
+	})
+
+	It("should collect arrays at paths as a whole", func() {
+		res, err := PeekPaths([]byte(`{"imp":[{"id":"1"},{"id":"2"}]}`), "imp", "imp.id")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(res["imp"]).To(HaveLen(1))
+		Expect(res["imp"][0]).To(MatchJSON(`[{"id":"1"},{"id":"2"}]`))
+		Expect(res["imp.id"]).To(Equal([]json.RawMessage{json.RawMessage(`"1"`), json.RawMessage(`"2"`)}))
+	})
+
+	It("should reject invalid input", func() {
+		_, err := PeekPaths([]byte(`{"id":"1",}`), "id")
+		Expect(err).To(BeAssignableToTypeOf(&SyntaxError{}))
+
+		_, err = PeekPaths([]byte(`{"x":`+strings.Repeat(`{"x":`, 3000000)), "x.x")
+		Expect(err).To(BeAssignableToTypeOf(&SyntaxError{}))
+	})
+})
+
+func mustMarshal(v interface{}) []byte {
+	buf, err := json.Marshal(v)
+	Expect(err).NotTo(HaveOccurred())
+	return buf
+}
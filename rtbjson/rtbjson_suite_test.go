@@ -0,0 +1,13 @@
+package rtbjson_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestRtbjson(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Rtbjson Suite")
+}
@@ -0,0 +1,307 @@
+package rtbjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SyntaxError is a JSON syntax error.
+type SyntaxError struct {
+	Msg    string
+	Offset int // offset in input, after which the error occurred
+}
+
+// Error implements error.
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("rtbjson: %s at offset %d", e.Msg, e.Offset)
+}
+
+// maxNesting is the maximum nesting of objects and arrays, as in encoding/json:
+// deeper input is rejected with *SyntaxError, rather than exhausting the stack.
+const maxNesting = 10000
+
+// scanner scans JSON input without allocations, validating its syntax.
+type scanner struct {
+	data  []byte
+	off   int
+	depth int // nesting of objects and arrays
+}
+
+func (s *scanner) errorf(format string, args ...interface{}) error {
+	return &SyntaxError{Msg: fmt.Sprintf(format, args...), Offset: s.off}
+}
+
+// ws skips whitespace.
+func (s *scanner) ws() {
+	for s.off < len(s.data) {
+		switch s.data[s.off] {
+		case ' ', '\t', '\n', '\r':
+			s.off++
+		default:
+			return
+		}
+	}
+}
+
+// peek returns next non-whitespace byte, without consuming it.
+func (s *scanner) peek() (byte, error) {
+	s.ws()
+	if s.off >= len(s.data) {
+		return 0, s.errorf("unexpected end of JSON input")
+	}
+	return s.data[s.off], nil
+}
+
+// expect consumes next non-whitespace byte, which must be c.
+func (s *scanner) expect(c byte) error {
+	b, err := s.peek()
+	if err != nil {
+		return err
+	}
+	if b != c {
+		return s.errorf("invalid character %q, expecting %q", b, c)
+	}
+	s.off++
+	return nil
+}
+
+// end checks, that only whitespace remains.
+func (s *scanner) end() error {
+	s.ws()
+	if s.off != len(s.data) {
+		return s.errorf("invalid character %q after top-level value", s.data[s.off])
+	}
+	return nil
+}
+
+// value consumes the next value, returning its raw JSON.
+func (s *scanner) value() ([]byte, error) {
+	b, err := s.peek()
+	if err != nil {
+		return nil, err
+	}
+	start := s.off
+	switch {
+	case b == '{':
+		err = s.object(func([]byte) error {
+			_, err := s.value()
+			return err
+		})
+	case b == '[':
+		err = s.array(func(int) error {
+			_, err := s.value()
+			return err
+		})
+	case b == '"':
+		_, err = s.str()
+	case b == '-' || b >= '0' && b <= '9':
+		err = s.number()
+	case b == 't':
+		err = s.literal("true")
+	case b == 'f':
+		err = s.literal("false")
+	case b == 'n':
+		err = s.literal("null")
+	default:
+		err = s.errorf("invalid character %q looking for beginning of value", b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.data[start:s.off], nil
+}
+
+// enter checks nesting of an object or array, just opened; leave must be called once it is closed.
+func (s *scanner) enter() error {
+	s.depth++
+	if s.depth > maxNesting {
+		return s.errorf("exceeded max depth")
+	}
+	return nil
+}
+
+func (s *scanner) leave() {
+	s.depth--
+}
+
+// object consumes an object, calling fn with each (unescaped) key; fn must consume the value.
+func (s *scanner) object(fn func(key []byte) error) error {
+	if err := s.expect('{'); err != nil {
+		return err
+	}
+	defer s.leave()
+	if err := s.enter(); err != nil {
+		return err
+	}
+	if b, err := s.peek(); err != nil {
+		return err
+	} else if b == '}' {
+		s.off++
+		return nil
+	}
+	for {
+		raw, err := s.str()
+		if err != nil {
+			return err
+		}
+		if err := s.expect(':'); err != nil {
+			return err
+		}
+		if err := fn(unquote(raw)); err != nil {
+			return err
+		}
+
+		b, err := s.peek()
+		if err != nil {
+			return err
+		}
+		s.off++
+		switch b {
+		case ',':
+		case '}':
+			return nil
+		default:
+			s.off--
+			return s.errorf("invalid character %q after object key:value pair", b)
+		}
+	}
+}
+
+// array consumes an array, calling fn with index of each element; fn must consume the element.
+func (s *scanner) array(fn func(i int) error) error {
+	if err := s.expect('['); err != nil {
+		return err
+	}
+	defer s.leave()
+	if err := s.enter(); err != nil {
+		return err
+	}
+	if b, err := s.peek(); err != nil {
+		return err
+	} else if b == ']' {
+		s.off++
+		return nil
+	}
+	for i := 0; ; i++ {
+		if err := fn(i); err != nil {
+			return err
+		}
+
+		b, err := s.peek()
+		if err != nil {
+			return err
+		}
+		s.off++
+		switch b {
+		case ',':
+		case ']':
+			return nil
+		default:
+			s.off--
+			return s.errorf("invalid character %q after array element", b)
+		}
+	}
+}
+
+// str consumes a string, returning its raw JSON (with quotes).
+func (s *scanner) str() ([]byte, error) {
+	if err := s.expect('"'); err != nil {
+		return nil, err
+	}
+	start := s.off - 1
+	for s.off < len(s.data) {
+		c := s.data[s.off]
+		s.off++
+		switch {
+		case c == '"':
+			return s.data[start:s.off], nil
+		case c == '\\':
+			if s.off >= len(s.data) {
+				break
+			}
+			switch s.data[s.off] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+				s.off++
+			case 'u':
+				if s.off+5 > len(s.data) {
+					s.off = len(s.data)
+					break
+				}
+				for _, h := range s.data[s.off+1 : s.off+5] {
+					if !isHex(h) {
+						return nil, s.errorf("invalid character %q in \\u hexadecimal character escape", h)
+					}
+				}
+				s.off += 5
+			default:
+				return nil, s.errorf("invalid character %q in string escape code", s.data[s.off])
+			}
+		case c < 0x20:
+			s.off--
+			return nil, s.errorf("invalid character %q in string literal", c)
+		}
+	}
+	return nil, s.errorf("unexpected end of JSON input")
+}
+
+// number consumes a number.
+func (s *scanner) number() error {
+	start := s.off
+	if s.off < len(s.data) && s.data[s.off] == '-' {
+		s.off++
+	}
+	switch {
+	case s.off < len(s.data) && s.data[s.off] == '0':
+		s.off++
+	case s.digits() == 0:
+		return s.errorf("invalid number %q", s.data[start:s.off])
+	}
+	if s.off < len(s.data) && s.data[s.off] == '.' {
+		s.off++
+		if s.digits() == 0 {
+			return s.errorf("invalid number %q", s.data[start:s.off])
+		}
+	}
+	if s.off < len(s.data) && (s.data[s.off] == 'e' || s.data[s.off] == 'E') {
+		s.off++
+		if s.off < len(s.data) && (s.data[s.off] == '+' || s.data[s.off] == '-') {
+			s.off++
+		}
+		if s.digits() == 0 {
+			return s.errorf("invalid number %q", s.data[start:s.off])
+		}
+	}
+	return nil
+}
+
+func (s *scanner) digits() int {
+	n := 0
+	for s.off < len(s.data) && s.data[s.off] >= '0' && s.data[s.off] <= '9' {
+		s.off++
+		n++
+	}
+	return n
+}
+
+func (s *scanner) literal(lit string) error {
+	if !bytes.HasPrefix(s.data[s.off:], []byte(lit)) {
+		return s.errorf("invalid literal, expecting %s", lit)
+	}
+	s.off += len(lit)
+	return nil
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// unquote returns contents of raw (valid) JSON string; strings without escapes are not copied.
+func unquote(raw []byte) []byte {
+	if bytes.IndexByte(raw, '\\') == -1 {
+		return raw[1 : len(raw)-1]
+	}
+	var str string
+	_ = json.Unmarshal(raw, &str)
+	return []byte(str)
+}
@@ -0,0 +1,251 @@
+package rtbjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/internal/jsonfields"
+)
+
+// Mode is a decoding mode of Decode.
+type Mode int8
+
+// Mode options.
+const (
+	ModeDefault Mode = 0 // as encoding/json: unknown fields are ignored, mistyped fields fail decoding
+	ModeStrict  Mode = 1 // unknown (including case-mismatched) and mistyped fields are reported with StrictError
+	ModeLenient Mode = 2 // numeric strings are coerced to numbers, and booleans to int8 flags, where numbers are expected
+)
+
+// FieldError describes a field of input, that is unknown to, or mistyped for the decoded type.
+type FieldError struct {
+	Path    string // JSON path of the field (e.g., "imp[0].bidfloorcurr")
+	Unknown bool   // whether the field is unknown
+	Value   string // JSON type of mistyped value (e.g., "string")
+	Type    string // expected Go type of mistyped value (e.g., "adcom1.APIFramework")
+}
+
+// Error implements error.
+func (e *FieldError) Error() string {
+	if e.Unknown {
+		return e.Path + ": unknown field"
+	}
+	return fmt.Sprintf("%s: %s, expected %s", e.Path, e.Value, e.Type)
+}
+
+// StrictError is returned by Decode in ModeStrict for input with unknown or mistyped fields.
+type StrictError struct {
+	Fields []FieldError
+}
+
+// Error implements error.
+func (e *StrictError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i := range e.Fields {
+		msgs[i] = e.Fields[i].Error()
+	}
+	return "rtbjson: " + strings.Join(msgs, "; ")
+}
+
+// edit replaces data[start:end] with repl.
+type edit struct {
+	start, end int
+	repl       string
+}
+
+// typed walks input along with Go type, it is decoded to,
+// collecting unknown and mistyped fields (ModeStrict) or coercions of values (ModeLenient).
+type typed struct {
+	s      scanner
+	mode   Mode
+	path   []pathElem
+	fields []FieldError
+	edits  []edit
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+// check walks data along with type of v; it returns data with coercions applied (ModeLenient).
+func check(data []byte, v interface{}, mode Mode) ([]byte, error) {
+	t := reflect.TypeOf(v)
+	if t == nil || t.Kind() != reflect.Ptr {
+		return data, nil // reported by json.Unmarshal
+	}
+
+	w := &typed{s: scanner{data: data}, mode: mode}
+	if err := w.value(t.Elem()); err != nil {
+		return nil, err
+	}
+	if len(w.fields) != 0 {
+		return nil, &StrictError{Fields: w.fields}
+	}
+	if len(w.edits) == 0 {
+		return data, nil
+	}
+
+	var buf []byte
+	prev := 0
+	for _, e := range w.edits {
+		buf = append(buf, data[prev:e.start]...)
+		buf = append(buf, e.repl...)
+		prev = e.end
+	}
+	return append(buf, data[prev:]...), nil
+}
+
+// value consumes the next value, decoded to type t.
+func (w *typed) value(t reflect.Type) error {
+	b, err := w.s.peek()
+	if err != nil {
+		return err
+	}
+	if b == 'n' || t == rawMessageType || t.Kind() == reflect.Interface {
+		return w.s.skip() // null is valid for any type
+	}
+	if t.Implements(unmarshalerType) || reflect.PtrTo(t).Implements(unmarshalerType) {
+		return w.s.skip()
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return w.value(t.Elem())
+	case reflect.Struct:
+		if b != '{' {
+			return w.mismatch(t)
+		}
+		fields := jsonfields.Of(t).ByName
+		return w.s.object(func(key []byte) error {
+			w.path = append(w.path, pathElem{key: key, index: -1})
+			defer func() { w.path = w.path[:len(w.path)-1] }()
+
+			f, ok := fields[string(key)]
+			if !ok {
+				if w.mode == ModeStrict {
+					w.fields = append(w.fields, FieldError{Path: formatPath(w.path), Unknown: true})
+				}
+				return w.s.skip()
+			}
+			return w.value(f.Type)
+		})
+	case reflect.Map:
+		if b != '{' {
+			return w.mismatch(t)
+		}
+		return w.s.object(func(key []byte) error {
+			w.path = append(w.path, pathElem{key: key, index: -1})
+			defer func() { w.path = w.path[:len(w.path)-1] }()
+			return w.value(t.Elem())
+		})
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			if b != '"' {
+				return w.mismatch(t)
+			}
+			return w.s.skip()
+		}
+		if b != '[' {
+			return w.mismatch(t)
+		}
+		return w.s.array(func(i int) error {
+			w.path = append(w.path, pathElem{index: i})
+			defer func() { w.path = w.path[:len(w.path)-1] }()
+			return w.value(t.Elem())
+		})
+	case reflect.String:
+		if b != '"' {
+			return w.mismatch(t)
+		}
+		return w.s.skip()
+	case reflect.Bool:
+		if b != 't' && b != 'f' {
+			return w.mismatch(t)
+		}
+		return w.s.skip()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return w.number(t, b)
+	}
+	return w.s.skip()
+}
+
+// number consumes the next value, decoded to numeric type t.
+func (w *typed) number(t reflect.Type, b byte) error {
+	start := w.s.off
+	raw, err := w.s.value()
+	if err != nil {
+		return err
+	}
+
+	repl := ""
+	switch {
+	case b == '"' && w.mode == ModeLenient:
+		repl = string(unquote(raw))
+		if !isNumber(repl) {
+			repl = ""
+		}
+	case (b == 't' || b == 'f') && w.mode == ModeLenient && t.Kind() == reflect.Int8:
+		repl = "0"
+		if b == 't' {
+			repl = "1"
+		}
+	case b == '-' || b >= '0' && b <= '9':
+		repl = string(raw)
+	}
+
+	if repl != "" && fitsNumber(repl, t) {
+		if repl != string(raw) {
+			w.edits = append(w.edits, edit{start: start, end: w.s.off, repl: repl})
+		}
+		return nil
+	}
+	if w.mode == ModeStrict {
+		w.fields = append(w.fields, FieldError{Path: formatPath(w.path), Value: jsonType(raw), Type: typeName(t)})
+	}
+	return nil
+}
+
+// mismatch consumes the next value, reporting it as mistyped for t (in ModeStrict).
+func (w *typed) mismatch(t reflect.Type) error {
+	raw, err := w.s.value()
+	if err != nil {
+		return err
+	}
+	if w.mode == ModeStrict {
+		w.fields = append(w.fields, FieldError{Path: formatPath(w.path), Value: jsonType(raw), Type: typeName(t)})
+	}
+	return nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// isNumber reports whether s is a JSON number.
+func isNumber(s string) bool {
+	sc := &scanner{data: []byte(s)}
+	return s != "" && sc.number() == nil && sc.off == len(s)
+}
+
+// fitsNumber reports whether JSON number s can be decoded to numeric type t.
+func fitsNumber(s string, t reflect.Type) bool {
+	var err error
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		_, err = strconv.ParseInt(s, 10, t.Bits())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		_, err = strconv.ParseUint(s, 10, t.Bits())
+	default:
+		_, err = strconv.ParseFloat(s, t.Bits())
+	}
+	return err == nil
+}
+
+// typeName returns name of t, as its package refers to it (e.g., "adcom1.APIFramework", "[]string").
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.String()
+}
@@ -0,0 +1,120 @@
+package rtbjson_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+
+	. "github.com/prebid/openrtb/v20/rtbjson"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/native1/request"
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ModeStrict", func() {
+	strict := DecodeOptions{Mode: ModeStrict}
+
+	DescribeTable(
+		"should accept testdata",
+
+		func(v interface{}, file string) {
+			data, err := ioutil.ReadFile(filepath.Join("..", file))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(Decode(data, v, strict)).To(Succeed())
+		},
+
+		Entry("openrtb2 bid request", new(openrtb2.BidRequest), "openrtb2/testdata/bid-request/2.6/video.json"),
+		Entry("openrtb2 bid response", new(openrtb2.BidResponse), "openrtb2/testdata/bid-response/2.6/ad-served-on-win-notice.json"),
+		Entry("openrtb3 body", new(openrtb3.Body), "openrtb3/testdata/request.json"),
+		Entry("adcom1 request context", new(adcom1.RequestContext), "adcom1/testdata/request-context.json"),
+		Entry("native1 request", new(request.Request), "native1/request/testdata/v1.2/social-context.json"),
+	)
+
+	It("should report unknown and mistyped fields", func() {
+		var req openrtb2.BidRequest
+		err := Decode([]byte(`{
+			"id": "1",
+			"imp": [{"id": "1", "bidfloorcurr": "USD", "secure": "1", "video": {"mimes": ["video/mp4"], "plcmnt": 1, "api": ["7"]}}],
+			"device": {"js": true, "ext": {"anything": 1}},
+			"tmax": 100.5,
+			"Test": 1
+		}`), &req, strict)
+
+		Expect(err).To(Equal(&StrictError{Fields: []FieldError{
+			{Path: "imp[0].bidfloorcurr", Unknown: true},
+			{Path: "imp[0].secure", Value: "string", Type: "int8"},
+			{Path: "imp[0].video.plcmnt", Unknown: true},
+			{Path: "imp[0].video.api[0]", Value: "string", Type: "adcom1.APIFramework"},
+			{Path: "device.js", Value: "bool", Type: "int8"},
+			{Path: "tmax", Value: "number 100.5", Type: "int64"},
+			{Path: "Test", Unknown: true},
+		}}))
+		Expect(err).To(MatchError(ContainSubstring("imp[0].video.api[0]: string, expected adcom1.APIFramework")))
+		Expect(NoBidReason(err)).To(Equal(openrtb3.NoBidInvalidRequest))
+	})
+
+	It("should report fields of embedded structs", func() {
+		err := Decode([]byte(`{"id":"1","domain":"example.com","pub":{"id":"p"},"domian":"x"}`), new(adcom1.Site), strict)
+		Expect(err).To(Equal(&StrictError{Fields: []FieldError{{Path: "domian", Unknown: true}}}))
+	})
+
+	It("should report mistyped objects", func() {
+		err := Decode([]byte(`{"id":"1","imp":{"id":"1"},"site":"example.com"}`), new(openrtb2.BidRequest), strict)
+		Expect(err).To(Equal(&StrictError{Fields: []FieldError{
+			{Path: "imp", Value: "object", Type: "[]openrtb2.Imp"},
+			{Path: "site", Value: "string", Type: "openrtb2.Site"},
+		}}))
+	})
+
+	It("should be ignored by default", func() {
+		var req openrtb2.BidRequest
+		Expect(Decode([]byte(`{"id":"1","imp":[{"id":"1","bidfloorcurr":"USD"}]}`), &req, DecodeOptions{})).To(Succeed())
+		Expect(req.Imp[0].BidFloorCur).To(BeEmpty())
+	})
+})
+
+var _ = Describe("ModeLenient", func() {
+	lenient := DecodeOptions{Mode: ModeLenient}
+
+	It("should coerce numeric strings and booleans", func() {
+		var req openrtb2.BidRequest
+		Expect(Decode([]byte(`{
+			"id": "1",
+			"imp": [{"id": "1", "secure": "1", "bidfloor": "0.5", "video": {"mimes": ["video/mp4"], "w": "640", "api": ["7"]}}],
+			"device": {"js": true, "dnt": false, "ua": "1"},
+			"tmax": "120",
+			"unknown": "1"
+		}`), &req, lenient)).To(Succeed())
+
+		Expect(req.Imp[0].Secure).To(Equal(openrtb2.Int8Ptr(1)))
+		Expect(req.Imp[0].BidFloor).To(Equal(openrtb2.Float64Ptr(0.5)))
+		Expect(req.Imp[0].Video.W).To(Equal(openrtb2.Int64Ptr(640)))
+		Expect(req.Imp[0].Video.API).To(Equal([]adcom1.APIFramework{adcom1.APIOMID10}))
+		Expect(req.Device.JS).To(Equal(openrtb2.Int8Ptr(1)))
+		Expect(req.Device.DNT).To(Equal(openrtb2.Int8Ptr(0)))
+		Expect(req.Device.UA).To(Equal("1"))
+		Expect(req.TMax).To(Equal(int64(120)))
+	})
+
+	DescribeTable(
+		"should not coerce",
+
+		func(data string) {
+			var req openrtb2.BidRequest
+			err := Decode([]byte(data), &req, lenient)
+			Expect(err).To(BeAssignableToTypeOf(&json.UnmarshalTypeError{}))
+		},
+
+		Entry("non-numeric strings", `{"id":"1","tmax":"fast"}`),
+		Entry("fractions to integers", `{"id":"1","tmax":"1.5"}`),
+		Entry("overflowing values", `{"id":"1","imp":[{"id":"1","secure":"300"}]}`),
+		Entry("booleans to int64", `{"id":"1","tmax":true}`),
+		Entry("numbers to strings", `{"id":1}`),
+	)
+})
@@ -0,0 +1,127 @@
+package skadn
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// separator of signed fields (U+2063 INVISIBLE SEPARATOR).
+const separator = "\u2063"
+
+// Message returns the message, signed by the ad network for fidelity type fid of bid b, as defined by Apple:
+//
+//	1.0:        network, campaign, itunesitem, nonce, timestamp
+//	2.0, 2.1:   version, network, campaign, itunesitem, nonce, sourceapp, timestamp
+//	2.2, 3.0:   version, network, campaign, itunesitem, nonce, sourceapp, fidelity, timestamp
+//	4.0:        version, network, sourceidentifier, itunesitem, nonce, sourceapp, fidelity, timestamp
+//
+// separated by U+2063; nonce and timestamp of versions 2.2 and later are those of the fidelity type.
+// Versions before 2.2 have only the signature of StoreKit-rendered ad.
+func Message(b *Bid, fid FidelityType) (string, error) {
+	v, ok := parseVersion(b.Version)
+	if !ok {
+		return "", invalid("version", ErrVersion)
+	}
+
+	var fields []string
+	switch {
+	case v < v20:
+		if fid != FidelityStoreKit {
+			return "", invalid("fidelities", ErrFidelity)
+		}
+		fields = []string{b.Network, b.Campaign, b.ITunesItem, b.Nonce, b.Timestamp}
+	case v < v22:
+		if fid != FidelityStoreKit {
+			return "", invalid("fidelities", ErrFidelity)
+		}
+		fields = []string{b.Version, b.Network, b.Campaign, b.ITunesItem, b.Nonce, b.SourceApp, b.Timestamp}
+	default:
+		f := b.fidelity(fid)
+		if f == nil {
+			return "", invalid("fidelities", ErrFidelity)
+		}
+		source := b.Campaign
+		if v >= v40 {
+			source = b.SourceIdentifier
+		}
+		fields = []string{b.Version, b.Network, source, b.ITunesItem, f.Nonce, b.SourceApp, strconv.Itoa(int(fid)), f.Timestamp}
+	}
+	return strings.Join(fields, separator), nil
+}
+
+// fidelity returns signature of fidelity type fid; nil if absent.
+func (b *Bid) fidelity(fid FidelityType) *Fidelity {
+	for i := range b.Fidelities {
+		if b.Fidelities[i].Fidelity == fid {
+			return &b.Fidelities[i]
+		}
+	}
+	return nil
+}
+
+// VerifySignature checks signature of fidelity type fid of bid b (see Message) against public key of the ad network:
+// base64-encoded ECDSA signature (ASN.1 DER) of SHA-256 hash of the message.
+//
+// *Error (with openrtb3.LossInvalidSKAdNetwork) is returned on failure: ErrFidelity, if there is no such signature, or ErrSignature.
+func VerifySignature(key *ecdsa.PublicKey, b *Bid, fid FidelityType) error {
+	msg, err := Message(b, fid)
+	if err != nil {
+		return err
+	}
+
+	field, signature := "signature", b.Signature
+	if f := b.fidelity(fid); f != nil {
+		field, signature = "fidelities", f.Signature
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	hash := sha256.Sum256([]byte(msg))
+	if err != nil || !ecdsa.VerifyASN1(key, hash[:], sig) {
+		return invalid(field, ErrSignature)
+	}
+	return nil
+}
+
+// Keys resolves ad network identifiers (Bid.Network) to public keys.
+type Keys interface {
+	PublicKey(network string) *ecdsa.PublicKey
+}
+
+// KeyMap is Keys of public keys by lowercase network identifier (e.g., "example123.skadnetwork").
+type KeyMap map[string]*ecdsa.PublicKey
+
+// PublicKey implements Keys.
+func (m KeyMap) PublicKey(network string) *ecdsa.PublicKey {
+	return m[strings.ToLower(network)]
+}
+
+// ParsePublicKey parses PEM-encoded ECDSA public key ("PUBLIC KEY"), as registered by ad networks with Apple.
+func ParsePublicKey(data []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PUBLIC KEY" {
+		return nil, ErrNoKey
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	ec, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, ErrNoKey
+	}
+	return ec, nil
+}
+
+// LoadPublicKey reads public key file (see ParsePublicKey).
+func LoadPublicKey(path string) (*ecdsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKey(data)
+}
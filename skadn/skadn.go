@@ -0,0 +1,139 @@
+// Package skadn provides SKAdNetwork extension (imp.ext.skadn and bid.ext.skadn) of OpenRTB 2.x:
+// typed objects, validation of bids against requests, and verification of signatures of ad networks
+//
+// Extensions are accessed with ReadImp, WriteImp, ReadBid and WriteBid functions,
+// as methods can not be declared on openrtb2.Imp and openrtb2.Bid outside of package openrtb2,
+// which does not depend on its extensions.
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb/blob/master/extensions/community_extensions/skadnetwork.md
+package skadn
+
+import (
+	"encoding/json"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Imp is SKAdNetwork extension of an impression (imp.ext.skadn), offered by the publisher app.
+type Imp struct {
+	Version     string          `json:"version,omitempty"`     // DEPRECATED: version of SKAdNetwork supported (2.0 and earlier), replaced by Versions
+	Versions    []string        `json:"versions,omitempty"`    // versions of SKAdNetwork supported
+	SourceApp   string          `json:"sourceapp,omitempty"`   // ID of the publisher app in Apple’s App Store
+	SKADNetIDs  []string        `json:"skadnetids,omitempty"`  // SKAdNetworkIdentifier entries of the publisher app’s Info.plist
+	SKADNetList *SKADNetList    `json:"skadnetlist,omitempty"` // IABTL registry IDs of ad networks, alternative to SKADNetIDs
+	ProductPage int8            `json:"productpage,omitempty"` // whether custom product pages are supported (1)
+	SKOverlay   int8            `json:"skoverlay,omitempty"`   // whether SKOverlay is supported (1)
+	Ext         json.RawMessage `json:"ext,omitempty"`
+}
+
+// SKADNetList lists ad networks by IDs of the IABTL SKAdNetwork ID registry.
+type SKADNetList struct {
+	Max  int64           `json:"max,omitempty"`  // version of the registry, the list refers to
+	Excl []int64         `json:"excl,omitempty"` // registry IDs, excluded from the ones up to Max
+	Addl []string        `json:"addl,omitempty"` // SKAdNetworkIdentifier entries, not in the registry
+	Ext  json.RawMessage `json:"ext,omitempty"`
+}
+
+// Bid is SKAdNetwork extension of a bid (bid.ext.skadn), signed by the ad network.
+type Bid struct {
+	Version          string          `json:"version,omitempty"`          // version of SKAdNetwork, the signature is made for
+	Network          string          `json:"network,omitempty"`          // ad network identifier (one of Imp.SKADNetIDs)
+	Campaign         string          `json:"campaign,omitempty"`         // campaign ID (versions before 4.0)
+	SourceIdentifier string          `json:"sourceidentifier,omitempty"` // hierarchical source identifier (version 4.0), replaces Campaign
+	ITunesItem       string          `json:"itunesitem,omitempty"`       // ID of the advertiser’s app in Apple’s App Store
+	ProductPageID    string          `json:"productpageid,omitempty"`    // ID of custom product page
+	Fidelities       []Fidelity      `json:"fidelities,omitempty"`       // signatures by fidelity type (version 2.2 and later)
+	Nonce            string          `json:"nonce,omitempty"`            // DEPRECATED: UUID, used in the signature (version 2.1 and earlier), replaced by Fidelities
+	SourceApp        string          `json:"sourceapp,omitempty"`        // ID of the publisher app (Imp.SourceApp)
+	Timestamp        string          `json:"timestamp,omitempty"`        // DEPRECATED: Unix time in milliseconds, used in the signature (version 2.1 and earlier), replaced by Fidelities
+	Signature        string          `json:"signature,omitempty"`        // DEPRECATED: signature of StoreKit-rendered ad (version 2.1 and earlier), replaced by Fidelities
+	SKOverlay        *SKOverlay      `json:"skoverlay,omitempty"`        // SKOverlay presentation, if Imp.SKOverlay is supported
+	Ext              json.RawMessage `json:"ext,omitempty"`
+}
+
+// SKOverlay configures presentation of SKOverlay.
+type SKOverlay struct {
+	Delay        *int64          `json:"delay,omitempty"`        // seconds into the ad, when the overlay is shown (0: at once)
+	EndCardDelay *int64          `json:"endcarddelay,omitempty"` // seconds into the end card, when the overlay is shown (0: at once)
+	Dismissible  int8            `json:"dismissible,omitempty"`  // whether the overlay may be dismissed by the user (1)
+	Ext          json.RawMessage `json:"ext,omitempty"`
+}
+
+// FidelityType is a type of ad presentation, attributed by SKAdNetwork.
+type FidelityType int8
+
+// FidelityType options.
+const (
+	FidelityViewThrough FidelityType = 0 // view-through ad
+	FidelityStoreKit    FidelityType = 1 // StoreKit-rendered ad
+)
+
+// Fidelity is a signature of a bid for a fidelity type.
+type Fidelity struct {
+	Fidelity  FidelityType    `json:"fidelity"`
+	Nonce     string          `json:"nonce,omitempty"`     // UUID, used in the signature
+	Timestamp string          `json:"timestamp,omitempty"` // Unix time in milliseconds, used in the signature
+	Signature string          `json:"signature,omitempty"` // base64-encoded ECDSA signature by the ad network
+	Ext       json.RawMessage `json:"ext,omitempty"`
+}
+
+// ReadImp returns SKAdNetwork extension of imp; nil if absent.
+func ReadImp(imp *openrtb2.Imp) (*Imp, error) {
+	var ext struct {
+		SKADN *Imp `json:"skadn"`
+	}
+	err := readExt(imp.Ext, &ext)
+	return ext.SKADN, err
+}
+
+// WriteImp sets (or, if s is nil, removes) SKAdNetwork extension of imp, preserving other extensions.
+func WriteImp(imp *openrtb2.Imp, s *Imp) (err error) {
+	imp.Ext, err = writeExt(imp.Ext, s, s == nil)
+	return err
+}
+
+// ReadBid returns SKAdNetwork extension of bid; nil if absent.
+func ReadBid(bid *openrtb2.Bid) (*Bid, error) {
+	var ext struct {
+		SKADN *Bid `json:"skadn"`
+	}
+	err := readExt(bid.Ext, &ext)
+	return ext.SKADN, err
+}
+
+// WriteBid sets (or, if s is nil, removes) SKAdNetwork extension of bid, preserving other extensions.
+func WriteBid(bid *openrtb2.Bid, s *Bid) (err error) {
+	bid.Ext, err = writeExt(bid.Ext, s, s == nil)
+	return err
+}
+
+func readExt(ext json.RawMessage, v interface{}) error {
+	if len(ext) == 0 {
+		return nil
+	}
+	return json.Unmarshal(ext, v)
+}
+
+func writeExt(ext json.RawMessage, s interface{}, remove bool) (json.RawMessage, error) {
+	attrs := make(map[string]json.RawMessage)
+	if len(ext) != 0 {
+		if err := json.Unmarshal(ext, &attrs); err != nil {
+			return ext, err
+		}
+	}
+
+	if remove {
+		delete(attrs, "skadn")
+	} else {
+		data, err := json.Marshal(s)
+		if err != nil {
+			return ext, err
+		}
+		attrs["skadn"] = data
+	}
+
+	if len(attrs) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(attrs)
+}
@@ -0,0 +1,13 @@
+package skadn_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSkadn(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Skadn Suite")
+}
@@ -0,0 +1,186 @@
+package skadn_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"strings"
+
+	. "github.com/prebid/openrtb/v20/skadn"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ext", func() {
+	It("should read and write imp.ext.skadn", func() {
+		imp := &openrtb2.Imp{Ext: json.RawMessage(`{"gpid":"x","skadn":{"versions":["2.2","3.0"],"sourceapp":"880047117","skadnetids":["cstr6suwn9.skadnetwork"],"skoverlay":1}}`)}
+		s, err := ReadImp(imp)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(Equal(&Imp{Versions: []string{"2.2", "3.0"}, SourceApp: "880047117", SKADNetIDs: []string{"cstr6suwn9.skadnetwork"}, SKOverlay: 1}))
+
+		s.ProductPage = 1
+		Expect(WriteImp(imp, s)).To(Succeed())
+		Expect(imp.Ext).To(MatchJSON(`{"gpid":"x","skadn":{"versions":["2.2","3.0"],"sourceapp":"880047117","skadnetids":["cstr6suwn9.skadnetwork"],"productpage":1,"skoverlay":1}}`))
+		Expect(WriteImp(imp, nil)).To(Succeed())
+		Expect(imp.Ext).To(MatchJSON(`{"gpid":"x"}`))
+	})
+
+	It("should read and write bid.ext.skadn", func() {
+		bid := &openrtb2.Bid{}
+		Expect(ReadBid(bid)).To(BeNil())
+
+		b := &Bid{Version: "2.2", Network: "cdkw7geqsh.skadnetwork", Fidelities: []Fidelity{{Fidelity: FidelityViewThrough, Nonce: "n"}}}
+		Expect(WriteBid(bid, b)).To(Succeed())
+		Expect(bid.Ext).To(MatchJSON(`{"skadn":{"version":"2.2","network":"cdkw7geqsh.skadnetwork","fidelities":[{"fidelity":0,"nonce":"n"}]}}`))
+		Expect(ReadBid(bid)).To(Equal(b))
+		Expect(WriteBid(bid, nil)).To(Succeed())
+		Expect(bid.Ext).To(BeNil())
+	})
+})
+
+var _ = Describe("Validate", func() {
+	imp := &Imp{
+		Versions:   []string{"2.2", "4.0"},
+		SourceApp:  "880047117",
+		SKADNetIDs: []string{"cdkw7geqsh.skadnetwork"},
+	}
+	valid := func() *Bid {
+		return &Bid{
+			Version:    "2.2",
+			Network:    "cdkw7geqsh.skadnetwork",
+			Campaign:   "45",
+			ITunesItem: "123456789",
+			SourceApp:  "880047117",
+			Fidelities: []Fidelity{{Fidelity: FidelityStoreKit, Nonce: "n", Timestamp: "1594406341", Signature: "s"}},
+		}
+	}
+
+	DescribeTable(
+		"bids",
+		func(imp *Imp, modify func(*Bid), field string, expected error) {
+			b := valid()
+			modify(b)
+			err := Validate(imp, b)
+			if expected == nil {
+				Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			var e *Error
+			Expect(errors.As(err, &e)).To(BeTrue())
+			Expect(e.Reason).To(Equal(openrtb3.LossInvalidSKAdNetwork))
+			Expect(e.Field).To(Equal(field))
+			Expect(errors.Is(err, expected)).To(BeTrue())
+		},
+		Entry("valid", imp, func(b *Bid) {}, "", nil),
+		Entry("network in other case", imp, func(b *Bid) { b.Network = "CDKW7GEQSH.skadnetwork" }, "", nil),
+		Entry("4.0", imp, func(b *Bid) { b.Version, b.Campaign, b.SourceIdentifier = "4.0", "", "1234" }, "", nil),
+		Entry("no imp", nil, func(b *Bid) {}, "", ErrNoImp),
+		Entry("unsupported version", imp, func(b *Bid) { b.Version = "3.0" }, "version", ErrVersion),
+		Entry("malformed version", imp, func(b *Bid) { b.Version = "x" }, "version", ErrVersion),
+		Entry("unlisted network", imp, func(b *Bid) { b.Network = "other.skadnetwork" }, "network", ErrNetwork),
+		Entry("other source app", imp, func(b *Bid) { b.SourceApp = "1" }, "sourceapp", ErrSourceApp),
+		Entry("no itunesitem", imp, func(b *Bid) { b.ITunesItem = "" }, "itunesitem", ErrMissing),
+		Entry("no campaign", imp, func(b *Bid) { b.Campaign = "" }, "campaign", ErrMissing),
+		Entry("4.0 without source identifier", imp, func(b *Bid) { b.Version = "4.0" }, "sourceidentifier", ErrMissing),
+		Entry("no fidelities", imp, func(b *Bid) { b.Fidelities = nil }, "fidelities", ErrMissing),
+		Entry("1.0 without source app", &Imp{Version: "1.0", SourceApp: "880047117"}, func(b *Bid) {
+			b.Version, b.SourceApp, b.Nonce, b.Timestamp, b.Signature = "1.0", "", "n", "1594406341", "s"
+		}, "", nil),
+		Entry("2.0 without source app", &Imp{Version: "2.0", SourceApp: "880047117"}, func(b *Bid) {
+			b.Version, b.SourceApp, b.Nonce, b.Timestamp, b.Signature = "2.0", "", "n", "1594406341", "s"
+		}, "sourceapp", ErrSourceApp),
+		Entry("2.0 without signature", &Imp{Version: "2.0", SourceApp: "880047117"}, func(b *Bid) { b.Version = "2.0" }, "signature", ErrMissing),
+	)
+})
+
+var _ = Describe("signature", func() {
+	var key *ecdsa.PrivateKey
+
+	BeforeEach(func() {
+		data, err := ioutil.ReadFile("testdata/network.pem")
+		Expect(err).NotTo(HaveOccurred())
+		block, _ := pem.Decode(data)
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	sign := func(fields ...string) string {
+		hash := sha256.Sum256([]byte(strings.Join(fields, "\u2063")))
+		sig, err := ecdsa.SignASN1(rand.Reader, key, hash[:])
+		Expect(err).NotTo(HaveOccurred())
+		return base64.StdEncoding.EncodeToString(sig)
+	}
+
+	It("should build messages by version", func() {
+		b := &Bid{
+			Version: "1.0", Network: "n", Campaign: "c", SourceIdentifier: "si", ITunesItem: "i", SourceApp: "sa", Nonce: "no", Timestamp: "t",
+			Fidelities: []Fidelity{{Fidelity: FidelityViewThrough, Nonce: "no0", Timestamp: "t0"}},
+		}
+		Expect(Message(b, FidelityStoreKit)).To(Equal("n\u2063c\u2063i\u2063no\u2063t"))
+		b.Version = "2.1"
+		Expect(Message(b, FidelityStoreKit)).To(Equal("2.1\u2063n\u2063c\u2063i\u2063no\u2063sa\u2063t"))
+		b.Version = "3.0"
+		Expect(Message(b, FidelityViewThrough)).To(Equal("3.0\u2063n\u2063c\u2063i\u2063no0\u2063sa\u20630\u2063t0"))
+		b.Version = "4.0"
+		Expect(Message(b, FidelityViewThrough)).To(Equal("4.0\u2063n\u2063si\u2063i\u2063no0\u2063sa\u20630\u2063t0"))
+
+		_, err := Message(b, FidelityStoreKit)
+		Expect(errors.Is(err, ErrFidelity)).To(BeTrue())
+	})
+
+	It("should verify signatures", func() {
+		pub, err := LoadPublicKey("testdata/network.pub.pem")
+		Expect(err).NotTo(HaveOccurred())
+
+		b := &Bid{
+			Version: "3.0", Network: "cdkw7geqsh.skadnetwork", Campaign: "45", ITunesItem: "123456789", SourceApp: "880047117",
+			Fidelities: []Fidelity{
+				{Fidelity: FidelityViewThrough, Nonce: "473b1a16-b4ef-43ad-9591-fcf3aefa82a7", Timestamp: "1594406341"},
+				{Fidelity: FidelityStoreKit, Nonce: "3c3ea5c9-4fb4-4d8e-a0c3-c6a8e7f2a4c5", Timestamp: "1594406342"},
+			},
+		}
+		b.Fidelities[0].Signature = sign("3.0", "cdkw7geqsh.skadnetwork", "45", "123456789", "473b1a16-b4ef-43ad-9591-fcf3aefa82a7", "880047117", "0", "1594406341")
+		b.Fidelities[1].Signature = sign("3.0", "cdkw7geqsh.skadnetwork", "45", "123456789", "3c3ea5c9-4fb4-4d8e-a0c3-c6a8e7f2a4c5", "880047117", "1", "1594406342")
+
+		Expect(VerifySignature(pub, b, FidelityViewThrough)).To(Succeed())
+		Expect(VerifySignature(pub, b, FidelityStoreKit)).To(Succeed())
+
+		imp := &openrtb2.Imp{Ext: json.RawMessage(`{"skadn":{"versions":["3.0"],"sourceapp":"880047117","skadnetids":["cdkw7geqsh.skadnetwork"]}}`)}
+		bid := &openrtb2.Bid{}
+		Expect(WriteBid(bid, b)).To(Succeed())
+		Expect(CheckBid(imp, bid, KeyMap{"cdkw7geqsh.skadnetwork": pub})).To(Succeed())
+		Expect(errors.Is(CheckBid(imp, bid, KeyMap{}), ErrNoKey)).To(BeTrue())
+
+		b.Campaign = "46"
+		Expect(errors.Is(VerifySignature(pub, b, FidelityStoreKit), ErrSignature)).To(BeTrue())
+		Expect(WriteBid(bid, b)).To(Succeed())
+		Expect(errors.Is(CheckBid(imp, bid, KeyMap{"cdkw7geqsh.skadnetwork": pub}), ErrSignature)).To(BeTrue())
+		Expect(CheckBid(imp, bid, nil)).To(Succeed())
+	})
+
+	It("should verify signatures of version 2.0", func() {
+		pub, err := LoadPublicKey("testdata/network.pub.pem")
+		Expect(err).NotTo(HaveOccurred())
+
+		b := &Bid{Version: "2.0", Network: "n", Campaign: "45", ITunesItem: "1", SourceApp: "2", Nonce: "no", Timestamp: "t"}
+		b.Signature = sign("2.0", "n", "45", "1", "no", "2", "t")
+		Expect(VerifySignature(pub, b, FidelityStoreKit)).To(Succeed())
+		Expect(errors.Is(VerifySignature(pub, b, FidelityViewThrough), ErrFidelity)).To(BeTrue())
+	})
+
+	It("should pass bids without extension", func() {
+		Expect(CheckBid(&openrtb2.Imp{}, &openrtb2.Bid{}, nil)).To(Succeed())
+		Expect(errors.Is(CheckBid(&openrtb2.Imp{}, &openrtb2.Bid{Ext: json.RawMessage(`{"skadn":{}}`)}, nil), ErrNoImp)).To(BeTrue())
+	})
+})
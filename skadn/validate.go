@@ -0,0 +1,163 @@
+package skadn
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+	"github.com/prebid/openrtb/v20/openrtb3"
+)
+
+// Errors.
+var (
+	ErrNoImp     = errors.New("skadn: impression does not support SKAdNetwork")
+	ErrVersion   = errors.New("skadn: version is not supported")
+	ErrNetwork   = errors.New("skadn: network is not listed by the impression")
+	ErrSourceApp = errors.New("skadn: source app does not match the impression")
+	ErrMissing   = errors.New("skadn: required field is missing")
+	ErrFidelity  = errors.New("skadn: no signature of fidelity type")
+	ErrSignature = errors.New("skadn: signature mismatch")
+	ErrNoKey     = errors.New("skadn: no key found")
+)
+
+// Error reports invalid SKAdNetwork extension of a bid, with loss reason to be reported (openrtb3.LossInvalidSKAdNetwork).
+type Error struct {
+	Reason openrtb3.LossReason
+	Field  string // field of bid.ext.skadn (e.g., "network")
+	Err    error
+}
+
+// Error implements error.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s (loss reason %d)", e.Err, e.Field, e.Reason)
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func invalid(field string, err error) *Error {
+	return &Error{Reason: openrtb3.LossInvalidSKAdNetwork, Field: field, Err: err}
+}
+
+// Validate checks bid extension b against imp extension imp (nil, if the impression does not support SKAdNetwork):
+// version must be supported (Imp.Versions or Imp.Version), network must be listed by Imp.SKADNetIDs (if any),
+// source app must match (since 2.0, which introduced it), and fields, required by the version, must be present.
+//
+// Ad networks of Imp.SKADNetList are not resolved, so networks of impressions without SKADNetIDs are accepted.
+func Validate(imp *Imp, b *Bid) error {
+	if imp == nil {
+		return invalid("", ErrNoImp)
+	}
+
+	v, ok := parseVersion(b.Version)
+	if !ok || !(containsFold(imp.Versions, b.Version) || strings.EqualFold(imp.Version, b.Version)) {
+		return invalid("version", ErrVersion)
+	}
+	if b.Network == "" {
+		return invalid("network", ErrMissing)
+	}
+	if len(imp.SKADNetIDs) != 0 && !containsFold(imp.SKADNetIDs, b.Network) {
+		return invalid("network", ErrNetwork)
+	}
+	if v >= v20 && b.SourceApp != imp.SourceApp {
+		return invalid("sourceapp", ErrSourceApp)
+	}
+	if b.ITunesItem == "" {
+		return invalid("itunesitem", ErrMissing)
+	}
+
+	switch {
+	case v >= v40 && b.SourceIdentifier == "":
+		return invalid("sourceidentifier", ErrMissing)
+	case v < v40 && b.Campaign == "":
+		return invalid("campaign", ErrMissing)
+	}
+
+	if v >= v22 {
+		if len(b.Fidelities) == 0 {
+			return invalid("fidelities", ErrMissing)
+		}
+		for _, f := range b.Fidelities {
+			if f.Nonce == "" || f.Timestamp == "" || f.Signature == "" {
+				return invalid("fidelities", ErrMissing)
+			}
+		}
+	} else if b.Nonce == "" || b.Timestamp == "" || b.Signature == "" {
+		return invalid("signature", ErrMissing)
+	}
+	return nil
+}
+
+// CheckBid validates SKAdNetwork extension of bid against the one of imp (see Validate), and, unless keys is nil,
+// verifies signatures of all its fidelity types (see VerifySignature); bids without the extension pass.
+//
+// *Error (with openrtb3.LossInvalidSKAdNetwork) is returned on failure, including malformed extensions.
+func CheckBid(imp *openrtb2.Imp, bid *openrtb2.Bid, keys Keys) error {
+	b, err := ReadBid(bid)
+	if err != nil {
+		return invalid("", err)
+	} else if b == nil {
+		return nil
+	}
+	s, err := ReadImp(imp)
+	if err != nil {
+		return invalid("", err)
+	}
+	if err := Validate(s, b); err != nil {
+		return err
+	}
+	if keys == nil {
+		return nil
+	}
+
+	key := keys.PublicKey(b.Network)
+	if key == nil {
+		return invalid("network", ErrNoKey)
+	}
+	if len(b.Fidelities) == 0 {
+		return VerifySignature(key, b, FidelityStoreKit)
+	}
+	for _, f := range b.Fidelities {
+		if err := VerifySignature(key, b, f.Fidelity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Versions, as parsed by parseVersion.
+const (
+	v20 = 2000
+	v22 = 2002
+	v40 = 4000
+)
+
+// parseVersion parses version "major.minor" as major*1000+minor.
+func parseVersion(s string) (int, bool) {
+	i := strings.IndexByte(s, '.')
+	if i == -1 {
+		return 0, false
+	}
+	major, err := strconv.Atoi(s[:i])
+	if err != nil || major < 1 {
+		return 0, false
+	}
+	minor, err := strconv.Atoi(s[i+1:])
+	if err != nil || minor < 0 || minor >= 1000 {
+		return 0, false
+	}
+	return major*1000 + minor, true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, e := range list {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
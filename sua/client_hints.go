@@ -0,0 +1,219 @@
+package sua
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// FromHeaders builds structured user agent from User-Agent Client Hints headers.
+//
+// Source is set to adcom1.UASourceHighEntropy if any of high entropy hints
+// (full version list, platform version, architecture, bitness or model) is present,
+// to adcom1.UASourceLowEntropy otherwise.
+// It returns nil if no client hints are present.
+func FromHeaders(h http.Header) *openrtb2.UserAgent {
+	sua := &openrtb2.UserAgent{Source: adcom1.UASourceLowEntropy}
+	found := false
+
+	if v, ok := header(h, HeaderFullVersionList); ok {
+		sua.Browsers = parseBrandList(v)
+		sua.Source = adcom1.UASourceHighEntropy
+		found = true
+	} else if v, ok := header(h, HeaderUA); ok {
+		sua.Browsers = parseBrandList(v)
+		found = true
+	}
+
+	if v, ok := header(h, HeaderPlatform); ok {
+		sua.Platform = &openrtb2.BrandVersion{Brand: parseString(v)}
+		found = true
+	}
+	if v, ok := header(h, HeaderPlatformVersion); ok {
+		if sua.Platform == nil {
+			sua.Platform = &openrtb2.BrandVersion{}
+		}
+		sua.Platform.Version = splitVersion(parseString(v))
+		sua.Source = adcom1.UASourceHighEntropy
+		found = true
+	}
+
+	if v, ok := header(h, HeaderMobile); ok {
+		switch strings.TrimSpace(v) {
+		case "?1":
+			sua.Mobile = openrtb2.Int8Ptr(1)
+		case "?0":
+			sua.Mobile = openrtb2.Int8Ptr(0)
+		}
+		found = true
+	}
+
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{HeaderArch, &sua.Architecture},
+		{HeaderBitness, &sua.Bitness},
+		{HeaderModel, &sua.Model},
+	} {
+		if v, ok := header(h, f.name); ok {
+			*f.dst = parseString(v)
+			sua.Source = adcom1.UASourceHighEntropy
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+	return sua
+}
+
+// ToHeaders renders structured user agent into User-Agent Client Hints headers (e.g., for server-to-server calls).
+//
+// Sec-CH-UA carries major versions only, while Sec-CH-UA-Full-Version-List carries full ones.
+func ToHeaders(sua *openrtb2.UserAgent) http.Header {
+	h := make(http.Header)
+	if sua == nil {
+		return h
+	}
+
+	if len(sua.Browsers) != 0 {
+		major := make([]string, 0, len(sua.Browsers))
+		full := make([]string, 0, len(sua.Browsers))
+		for _, b := range sua.Browsers {
+			v := ""
+			if len(b.Version) != 0 {
+				v = b.Version[0]
+			}
+			major = append(major, quote(b.Brand)+";v="+quote(v))
+			full = append(full, quote(b.Brand)+";v="+quote(strings.Join(b.Version, ".")))
+		}
+		h.Set(HeaderUA, strings.Join(major, ", "))
+		h.Set(HeaderFullVersionList, strings.Join(full, ", "))
+	}
+
+	if sua.Platform != nil {
+		h.Set(HeaderPlatform, quote(sua.Platform.Brand))
+		if len(sua.Platform.Version) != 0 {
+			h.Set(HeaderPlatformVersion, quote(strings.Join(sua.Platform.Version, ".")))
+		}
+	}
+
+	if sua.Mobile != nil {
+		if *sua.Mobile == 1 {
+			h.Set(HeaderMobile, "?1")
+		} else {
+			h.Set(HeaderMobile, "?0")
+		}
+	}
+
+	if sua.Architecture != "" {
+		h.Set(HeaderArch, quote(sua.Architecture))
+	}
+	if sua.Bitness != "" {
+		h.Set(HeaderBitness, quote(sua.Bitness))
+	}
+	if sua.Model != "" || sua.Source == adcom1.UASourceHighEntropy {
+		h.Set(HeaderModel, quote(sua.Model))
+	}
+
+	return h
+}
+
+func header(h http.Header, name string) (string, bool) {
+	vs := h.Values(name)
+	if len(vs) == 0 {
+		return "", false
+	}
+	return strings.Join(vs, ","), true
+}
+
+// parseBrandList parses structured field list of brands, e.g. `"Chromium";v="110", "Not A(Brand";v="24"`.
+func parseBrandList(s string) []openrtb2.BrandVersion {
+	var brands []openrtb2.BrandVersion
+	for _, item := range splitList(s) {
+		params := splitParams(item)
+		if len(params) == 0 {
+			continue
+		}
+
+		bv := openrtb2.BrandVersion{Brand: parseString(params[0])}
+		for _, p := range params[1:] {
+			if eq := strings.IndexByte(p, '='); eq != -1 && strings.TrimSpace(p[:eq]) == "v" {
+				bv.Version = splitVersion(parseString(p[eq+1:]))
+			}
+		}
+		brands = append(brands, bv)
+	}
+	return brands
+}
+
+// splitList splits structured field list by commas outside of quoted strings.
+func splitList(s string) []string {
+	return splitUnquoted(s, ',')
+}
+
+// splitParams splits structured field item into bare item and parameters.
+func splitParams(s string) []string {
+	return splitUnquoted(s, ';')
+}
+
+func splitUnquoted(s string, sep byte) []string {
+	var (
+		parts  []string
+		quoted bool
+		start  int
+	)
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && quoted:
+			i++
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(parts) != 0 {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// parseString parses structured field string (quoted, with backslash escapes); unquoted tokens are returned as is.
+func parseString(s string) string {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+
+	s = s[1 : len(s)-1]
+	if strings.IndexByte(s, '\\') == -1 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// quote renders structured field string.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func splitVersion(v string) []string {
+	if v == "" {
+		return nil
+	}
+	return strings.Split(v, ".")
+}
@@ -0,0 +1,48 @@
+// Package sua builds structured user agent (openrtb2.UserAgent, Device.SUA) from User-Agent Client Hints or legacy User-Agent strings
+//
+// https://wicg.github.io/ua-client-hints/
+// https://github.com/InteractiveAdvertisingBureau/openrtb2.x/blob/main/2.6.md#3229---object-useragent-
+package sua
+
+import (
+	"net/http"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// User-Agent Client Hints header names.
+const (
+	HeaderUA              = "Sec-CH-UA"
+	HeaderFullVersionList = "Sec-CH-UA-Full-Version-List"
+	HeaderPlatform        = "Sec-CH-UA-Platform"
+	HeaderPlatformVersion = "Sec-CH-UA-Platform-Version"
+	HeaderMobile          = "Sec-CH-UA-Mobile"
+	HeaderArch            = "Sec-CH-UA-Arch"
+	HeaderBitness         = "Sec-CH-UA-Bitness"
+	HeaderModel           = "Sec-CH-UA-Model"
+)
+
+// Parse builds structured user agent from HTTP request headers:
+// client hints are preferred, legacy User-Agent header is used as a fallback.
+// It returns nil if neither is available (or parsable).
+func Parse(h http.Header) *openrtb2.UserAgent {
+	if sua := FromHeaders(h); sua != nil {
+		return sua
+	}
+	return FromUA(h.Get("User-Agent"))
+}
+
+// Populate sets Device.SUA (if not set yet) from client hints in h, falling back to Device.UA string.
+// It reports whether Device.SUA was set.
+func Populate(d *openrtb2.Device, h http.Header) bool {
+	if d.SUA != nil {
+		return false
+	}
+	if h != nil {
+		d.SUA = FromHeaders(h)
+	}
+	if d.SUA == nil {
+		d.SUA = FromUA(d.UA)
+	}
+	return d.SUA != nil
+}
@@ -0,0 +1,13 @@
+package sua_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestSUA(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "SUA Suite")
+}
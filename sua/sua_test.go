@@ -0,0 +1,169 @@
+package sua_test
+
+import (
+	"net/http"
+
+	. "github.com/prebid/openrtb/v20/sua"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FromHeaders", func() {
+	It("should return nil without client hints", func() {
+		Expect(FromHeaders(http.Header{"User-Agent": {"Mozilla/5.0"}})).To(BeNil())
+	})
+
+	It("should parse low entropy hints", func() {
+		h := make(http.Header)
+		h.Set(HeaderUA, `"Chromium";v="110", "Not A(Brand";v="24", "Google Chrome";v="110"`)
+		h.Set(HeaderPlatform, `"Windows"`)
+		h.Set(HeaderMobile, "?0")
+
+		Expect(FromHeaders(h)).To(Equal(&openrtb2.UserAgent{
+			Browsers: []openrtb2.BrandVersion{
+				{Brand: "Chromium", Version: []string{"110"}},
+				{Brand: "Not A(Brand", Version: []string{"24"}},
+				{Brand: "Google Chrome", Version: []string{"110"}},
+			},
+			Platform: &openrtb2.BrandVersion{Brand: "Windows"},
+			Mobile:   openrtb2.Int8Ptr(0),
+			Source:   adcom1.UASourceLowEntropy,
+		}))
+	})
+
+	It("should parse high entropy hints", func() {
+		h := make(http.Header)
+		h.Set(HeaderUA, `"Chromium";v="110"`)
+		h.Set(HeaderFullVersionList, `"Chromium";v="110.0.5481.100", "Not;A\"Brand";v="24.0.0.0"`)
+		h.Set(HeaderPlatform, `"Android"`)
+		h.Set(HeaderPlatformVersion, `"13.0.0"`)
+		h.Set(HeaderMobile, "?1")
+		h.Set(HeaderArch, `""`)
+		h.Set(HeaderBitness, `"64"`)
+		h.Set(HeaderModel, `"Pixel 7"`)
+
+		Expect(FromHeaders(h)).To(Equal(&openrtb2.UserAgent{
+			Browsers: []openrtb2.BrandVersion{
+				{Brand: "Chromium", Version: []string{"110", "0", "5481", "100"}},
+				{Brand: `Not;A"Brand`, Version: []string{"24", "0", "0", "0"}},
+			},
+			Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13", "0", "0"}},
+			Mobile:   openrtb2.Int8Ptr(1),
+			Bitness:  "64",
+			Model:    "Pixel 7",
+			Source:   adcom1.UASourceHighEntropy,
+		}))
+	})
+})
+
+var _ = Describe("FromUA", func() {
+	It("should return nil for unrecognized strings", func() {
+		Expect(FromUA("")).To(BeNil())
+		Expect(FromUA("curl/7.88.1")).To(BeNil())
+	})
+
+	DescribeTable("should parse common user agents",
+		func(ua string, exp *openrtb2.UserAgent) {
+			exp.Source = adcom1.UASourceParsed
+			Expect(FromUA(ua)).To(Equal(exp))
+		},
+		Entry("Chrome on Windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+			&openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"120", "0", "0", "0"}},
+					{Brand: "Google Chrome", Version: []string{"120", "0", "0", "0"}},
+				},
+				Platform:     &openrtb2.BrandVersion{Brand: "Windows", Version: []string{"10", "0"}},
+				Mobile:       openrtb2.Int8Ptr(0),
+				Architecture: "x86",
+				Bitness:      "64",
+			}),
+		Entry("Edge on Windows",
+			"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.2210.91",
+			&openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"120", "0", "0", "0"}},
+					{Brand: "Microsoft Edge", Version: []string{"120", "0", "2210", "91"}},
+				},
+				Platform:     &openrtb2.BrandVersion{Brand: "Windows", Version: []string{"10", "0"}},
+				Mobile:       openrtb2.Int8Ptr(0),
+				Architecture: "x86",
+				Bitness:      "64",
+			}),
+		Entry("Chrome WebView on Android",
+			"Mozilla/5.0 (Linux; Android 13; Pixel 7 Build/TQ3A.230805.001; wv) AppleWebKit/537.36 (KHTML, like Gecko) Version/4.0 Chrome/116.0.0.0 Mobile Safari/537.36",
+			&openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{
+					{Brand: "Chromium", Version: []string{"116", "0", "0", "0"}},
+					{Brand: "Google Chrome", Version: []string{"116", "0", "0", "0"}},
+				},
+				Platform: &openrtb2.BrandVersion{Brand: "Android", Version: []string{"13"}},
+				Mobile:   openrtb2.Int8Ptr(1),
+				Model:    "Pixel 7",
+			}),
+		Entry("Safari on iPhone",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 17_1_2 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1.2 Mobile/15E148 Safari/604.1",
+			&openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Safari", Version: []string{"17", "1", "2"}}},
+				Platform: &openrtb2.BrandVersion{Brand: "iOS", Version: []string{"17", "1", "2"}},
+				Mobile:   openrtb2.Int8Ptr(1),
+			}),
+		Entry("Firefox on macOS",
+			"Mozilla/5.0 (Macintosh; Intel Mac OS X 10.15; rv:121.0) Gecko/20100101 Firefox/121.0",
+			&openrtb2.UserAgent{
+				Browsers: []openrtb2.BrandVersion{{Brand: "Firefox", Version: []string{"121", "0"}}},
+				Platform: &openrtb2.BrandVersion{Brand: "macOS", Version: []string{"10", "15"}},
+				Mobile:   openrtb2.Int8Ptr(0),
+			}),
+	)
+})
+
+var _ = Describe("ToHeaders", func() {
+	It("should render headers, parsed back to the same value", func() {
+		sua := &openrtb2.UserAgent{
+			Browsers: []openrtb2.BrandVersion{
+				{Brand: "Chromium", Version: []string{"110", "0", "5481", "100"}},
+				{Brand: `Not;A"Brand`, Version: []string{"24", "0", "0", "0"}},
+			},
+			Platform:     &openrtb2.BrandVersion{Brand: "macOS", Version: []string{"13", "2", "1"}},
+			Mobile:       openrtb2.Int8Ptr(0),
+			Architecture: "arm",
+			Bitness:      "64",
+			Source:       adcom1.UASourceHighEntropy,
+		}
+
+		h := ToHeaders(sua)
+		Expect(h.Get(HeaderUA)).To(Equal(`"Chromium";v="110", "Not;A\"Brand";v="24"`))
+		Expect(h.Get(HeaderFullVersionList)).To(Equal(`"Chromium";v="110.0.5481.100", "Not;A\"Brand";v="24.0.0.0"`))
+		Expect(h.Get(HeaderPlatform)).To(Equal(`"macOS"`))
+		Expect(h.Get(HeaderPlatformVersion)).To(Equal(`"13.2.1"`))
+		Expect(h.Get(HeaderMobile)).To(Equal("?0"))
+		Expect(h.Get(HeaderArch)).To(Equal(`"arm"`))
+		Expect(h.Get(HeaderBitness)).To(Equal(`"64"`))
+		Expect(h.Get(HeaderModel)).To(Equal(`""`))
+
+		Expect(FromHeaders(h)).To(Equal(sua))
+	})
+})
+
+var _ = Describe("Populate", func() {
+	It("should fall back to Device.UA", func() {
+		d := &openrtb2.Device{UA: "Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0"}
+		Expect(Populate(d, http.Header{})).To(BeTrue())
+		Expect(d.SUA.Source).To(Equal(adcom1.UASourceParsed))
+		Expect(d.SUA.Platform.Brand).To(Equal("Linux"))
+	})
+
+	It("should keep existing Device.SUA", func() {
+		sua := &openrtb2.UserAgent{Model: "model"}
+		d := &openrtb2.Device{SUA: sua}
+		Expect(Populate(d, nil)).To(BeFalse())
+		Expect(d.SUA).To(BeIdenticalTo(sua))
+	})
+})
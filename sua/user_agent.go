@@ -0,0 +1,163 @@
+package sua
+
+import (
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// browserTokens map User-Agent product tokens to brands, in detection order.
+// Chromium-based browsers report Chromium brand as well.
+var browserTokens = []struct {
+	token    string
+	brand    string
+	chromium bool
+}{
+	{"Edg/", "Microsoft Edge", true},
+	{"EdgA/", "Microsoft Edge", true},
+	{"EdgiOS/", "Microsoft Edge", false},
+	{"OPR/", "Opera", true},
+	{"SamsungBrowser/", "Samsung Internet", true},
+	{"YaBrowser/", "Yandex", true},
+	{"CriOS/", "Google Chrome", false},
+	{"Chrome/", "Google Chrome", true},
+	{"FxiOS/", "Firefox", false},
+	{"Firefox/", "Firefox", false},
+}
+
+// FromUA builds structured user agent by parsing legacy User-Agent string (e.g., Device.UA).
+//
+// Only the most common browsers and platforms are recognized;
+// Source is set to adcom1.UASourceParsed.
+// It returns nil if neither browser nor platform is recognized.
+func FromUA(ua string) *openrtb2.UserAgent {
+	if ua == "" {
+		return nil
+	}
+
+	sua := &openrtb2.UserAgent{
+		Browsers: parseBrowsers(ua),
+		Platform: parsePlatform(ua),
+		Source:   adcom1.UASourceParsed,
+	}
+	if sua.Browsers == nil && sua.Platform == nil {
+		return nil
+	}
+
+	if strings.Contains(ua, "Mobile") {
+		sua.Mobile = openrtb2.Int8Ptr(1)
+	} else {
+		sua.Mobile = openrtb2.Int8Ptr(0)
+	}
+	sua.Architecture, sua.Bitness = parseArch(ua)
+	if sua.Platform != nil && sua.Platform.Brand == "Android" {
+		sua.Model = parseAndroidModel(ua)
+	}
+	return sua
+}
+
+func parseBrowsers(ua string) []openrtb2.BrandVersion {
+	for _, bt := range browserTokens {
+		v, ok := tokenVersion(ua, bt.token)
+		if !ok {
+			continue
+		}
+
+		browsers := []openrtb2.BrandVersion{{Brand: bt.brand, Version: splitVersion(v)}}
+		if bt.chromium {
+			if cv, ok := tokenVersion(ua, "Chrome/"); ok {
+				browsers = append([]openrtb2.BrandVersion{{Brand: "Chromium", Version: splitVersion(cv)}}, browsers...)
+			}
+		}
+		return browsers
+	}
+
+	// Safari reports its version in "Version/" token
+	if strings.Contains(ua, "Safari/") {
+		if v, ok := tokenVersion(ua, "Version/"); ok {
+			return []openrtb2.BrandVersion{{Brand: "Safari", Version: splitVersion(v)}}
+		}
+	}
+	return nil
+}
+
+func parsePlatform(ua string) *openrtb2.BrandVersion {
+	switch {
+	case strings.Contains(ua, "Windows NT "):
+		v, _ := tokenVersion(ua, "Windows NT ")
+		return &openrtb2.BrandVersion{Brand: "Windows", Version: splitVersion(v)}
+	case strings.Contains(ua, "Android"):
+		v, _ := tokenVersion(ua, "Android ")
+		return &openrtb2.BrandVersion{Brand: "Android", Version: splitVersion(v)}
+	case strings.Contains(ua, "iPhone OS "):
+		v, _ := tokenVersion(ua, "iPhone OS ")
+		return &openrtb2.BrandVersion{Brand: "iOS", Version: splitVersion(strings.ReplaceAll(v, "_", "."))}
+	case strings.Contains(ua, "iPad") && strings.Contains(ua, "CPU OS "):
+		v, _ := tokenVersion(ua, "CPU OS ")
+		return &openrtb2.BrandVersion{Brand: "iOS", Version: splitVersion(strings.ReplaceAll(v, "_", "."))}
+	case strings.Contains(ua, "Mac OS X"):
+		v, _ := tokenVersion(ua, "Mac OS X ")
+		return &openrtb2.BrandVersion{Brand: "macOS", Version: splitVersion(strings.ReplaceAll(v, "_", "."))}
+	case strings.Contains(ua, "CrOS"):
+		return &openrtb2.BrandVersion{Brand: "Chrome OS"}
+	case strings.Contains(ua, "Linux"):
+		return &openrtb2.BrandVersion{Brand: "Linux"}
+	}
+	return nil
+}
+
+func parseArch(ua string) (arch, bitness string) {
+	switch {
+	case strings.Contains(ua, "x64"), strings.Contains(ua, "x86_64"), strings.Contains(ua, "Win64"), strings.Contains(ua, "WOW64"):
+		return "x86", "64"
+	case strings.Contains(ua, "i686"), strings.Contains(ua, "i386"):
+		return "x86", "32"
+	case strings.Contains(ua, "aarch64"), strings.Contains(ua, "arm64"):
+		return "arm", "64"
+	}
+	return "", ""
+}
+
+// parseAndroidModel extracts device model from "(Linux; Android 13; Pixel 7 Build/TQ3A)"-like comment.
+func parseAndroidModel(ua string) string {
+	i := strings.Index(ua, "Android")
+	if i == -1 {
+		return ""
+	}
+	comment := ua[i:]
+	if end := strings.IndexByte(comment, ')'); end != -1 {
+		comment = comment[:end]
+	}
+
+	parts := strings.Split(comment, ";")
+	if len(parts) > 2 && strings.TrimSpace(parts[len(parts)-1]) == "wv" {
+		parts = parts[:len(parts)-1]
+	}
+	if len(parts) < 2 {
+		return ""
+	}
+	model := strings.TrimSpace(parts[len(parts)-1])
+	if b := strings.Index(model, " Build/"); b != -1 {
+		model = model[:b]
+	}
+	if strings.HasPrefix(model, "Android") {
+		return ""
+	}
+	return model
+}
+
+// tokenVersion returns version, following token (e.g., "Chrome/" or "Android "), up to the first non-version character.
+func tokenVersion(ua, token string) (string, bool) {
+	i := strings.Index(ua, token)
+	if i == -1 {
+		return "", false
+	}
+
+	v := ua[i+len(token):]
+	end := 0
+	for end < len(v) && (v[end] >= '0' && v[end] <= '9' || v[end] == '.' || v[end] == '_') {
+		end++
+	}
+	return strings.Trim(v[:end], "._"), true
+}
@@ -0,0 +1,131 @@
+package taxonomy
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+)
+
+// tables map names of taxonomy files (without .tsv) to taxonomies.
+var tables = map[string]adcom1.CategoryTaxonomy{
+	"content-1.0":  adcom1.CatTaxIABContent10,
+	"content-2.0":  adcom1.CatTaxIABContent20,
+	"content-2.1":  adcom1.CatTaxIABContent21,
+	"content-2.2":  adcom1.CatTaxIABContent22,
+	"content-3.0":  adcom1.CatTaxIABContent30,
+	"product-1.0":  adcom1.CatTaxIABProduct10,
+	"product-2.0":  adcom1.CatTaxIABProduct20,
+	"audience-1.1": adcom1.CatTaxIABAudience11,
+}
+
+// data holds taxonomies and mappings, embedded in the package (see data/README.md).
+//
+//go:embed data/*.tsv
+var data embed.FS
+
+var embedded, embeddedMappings = mustLoadFS(data, "data")
+
+func mustLoadFS(fsys fs.FS, dir string) (map[adcom1.CategoryTaxonomy]*Taxonomy, []*Mapping) {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	taxonomies, mappings, err := LoadFS(sub)
+	if err != nil {
+		panic(err)
+	}
+
+	m := make(map[adcom1.CategoryTaxonomy]*Taxonomy, len(taxonomies))
+	for _, t := range taxonomies {
+		m[t.CatTax] = t
+	}
+	return m, mappings
+}
+
+// LoadFS reads taxonomies and mappings from tab-separated files in the root of fsys (see Load and LoadMapping),
+// which are named after taxonomies: "content-1.0", "content-2.0", "content-2.1", "content-2.2", "content-3.0",
+// "product-1.0", "product-2.0" and "audience-1.1", with ".tsv" extension
+// (e.g., "content-2.2.tsv" is a taxonomy, "content-2.2-to-content-1.0.tsv" is a mapping).
+//
+// Files are read in lexical order; other files are ignored.
+func LoadFS(fsys fs.FS) ([]*Taxonomy, []*Mapping, error) {
+	names, err := fs.Glob(fsys, "*.tsv")
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Strings(names)
+
+	var (
+		taxonomies []*Taxonomy
+		mappings   []*Mapping
+	)
+	for _, name := range names {
+		base := strings.TrimSuffix(name, ".tsv")
+		if catTax, ok := tables[base]; ok {
+			t, err := loadTaxonomy(fsys, name, catTax)
+			if err != nil {
+				return nil, nil, err
+			}
+			taxonomies = append(taxonomies, t)
+			continue
+		}
+
+		i := strings.Index(base, "-to-")
+		if i == -1 {
+			continue
+		}
+		from, okFrom := tables[base[:i]]
+		to, okTo := tables[base[i+len("-to-"):]]
+		if !okFrom || !okTo {
+			continue
+		}
+		m, err := loadMapping(fsys, name, from, to)
+		if err != nil {
+			return nil, nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	return taxonomies, mappings, nil
+}
+
+func loadTaxonomy(fsys fs.FS, name string, catTax adcom1.CategoryTaxonomy) (*Taxonomy, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	t, err := Load(catTax, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return t, nil
+}
+
+func loadMapping(fsys fs.FS, name string, from, to adcom1.CategoryTaxonomy) (*Mapping, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := LoadMapping(from, to, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return m, nil
+}
+
+// Embedded returns taxonomy catTax, embedded in the package; nil if it is not embedded.
+func Embedded(catTax adcom1.CategoryTaxonomy) *Taxonomy {
+	return embedded[catTax]
+}
+
+// EmbeddedMappings returns mappings between taxonomies, embedded in the package.
+func EmbeddedMappings() []*Mapping {
+	return append([]*Mapping(nil), embeddedMappings...)
+}
@@ -0,0 +1,193 @@
+package taxonomy
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+)
+
+// Mapping maps categories of one taxonomy to categories of another one.
+type Mapping struct {
+	From, To adcom1.CategoryTaxonomy
+
+	ids map[string][]string
+}
+
+// NewMapping returns mapping of ids (From category IDs to To category IDs).
+func NewMapping(from, to adcom1.CategoryTaxonomy, ids map[string][]string) *Mapping {
+	return &Mapping{From: from, To: to, ids: ids}
+}
+
+// LoadMapping reads mapping in tab-separated format: rows of From category ID and To category ID
+// (a From category, mapped to several To categories, takes several rows).
+//
+// Further columns are ignored, as are empty rows and the header: the first row, with whitespace in either ID.
+func LoadMapping(from, to adcom1.CategoryTaxonomy, r io.Reader) (*Mapping, error) {
+	ids := make(map[string][]string)
+	sc := bufio.NewScanner(r)
+	for first := true; sc.Scan(); first = false {
+		row := strings.Split(sc.Text(), "\t")
+		if len(row) < 2 {
+			continue
+		}
+		src, dst := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+		if first && (strings.ContainsAny(src, " \t") || strings.ContainsAny(dst, " \t")) {
+			continue
+		}
+		if src != "" && dst != "" {
+			ids[src] = append(ids[src], dst)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return NewMapping(from, to, ids), nil
+}
+
+// Map returns To category IDs of From category id.
+func (m *Mapping) Map(id string) []string {
+	return m.ids[id]
+}
+
+// inverse returns mapping in the opposite direction.
+func (m *Mapping) inverse() *Mapping {
+	ids := make(map[string][]string)
+	for src, dsts := range m.ids {
+		for _, dst := range dsts {
+			ids[dst] = append(ids[dst], src)
+		}
+	}
+	return NewMapping(m.To, m.From, ids)
+}
+
+// Registry holds taxonomies and mappings between them.
+//
+// Registry is not safe for concurrent modification; it is safe for concurrent use, once set up.
+type Registry struct {
+	taxonomies map[adcom1.CategoryTaxonomy]*Taxonomy
+	mappings   []*Mapping
+	edges      map[adcom1.CategoryTaxonomy][]*Mapping // mappings and their inverses by From
+}
+
+// NewRegistry returns registry of taxonomies and mappings, embedded in the package (see Embedded and EmbeddedMappings),
+// and taxonomies (which may replace embedded ones).
+func NewRegistry(taxonomies ...*Taxonomy) *Registry {
+	r := &Registry{
+		taxonomies: make(map[adcom1.CategoryTaxonomy]*Taxonomy),
+	}
+	for _, t := range embedded {
+		r.AddTaxonomy(t)
+	}
+	for _, m := range embeddedMappings {
+		r.AddMapping(m)
+	}
+	for _, t := range taxonomies {
+		r.AddTaxonomy(t)
+	}
+	return r
+}
+
+// AddTaxonomy adds (or replaces) taxonomy t.
+func (r *Registry) AddTaxonomy(t *Taxonomy) {
+	r.taxonomies[t.CatTax] = t
+}
+
+// AddMapping adds mapping m; it is used in the opposite direction too, unless a mapping in that direction is added.
+func (r *Registry) AddMapping(m *Mapping) {
+	r.mappings = append(r.mappings, m)
+
+	r.edges = make(map[adcom1.CategoryTaxonomy][]*Mapping)
+	direct := make(map[[2]adcom1.CategoryTaxonomy]bool)
+	for _, m := range r.mappings {
+		r.edges[m.From] = append(r.edges[m.From], m)
+		direct[[2]adcom1.CategoryTaxonomy{m.From, m.To}] = true
+	}
+	for _, m := range r.mappings {
+		if !direct[[2]adcom1.CategoryTaxonomy{m.To, m.From}] {
+			r.edges[m.To] = append(r.edges[m.To], m.inverse())
+		}
+	}
+}
+
+// Taxonomy returns taxonomy catTax; nil if not registered.
+func (r *Registry) Taxonomy(catTax adcom1.CategoryTaxonomy) *Taxonomy {
+	return r.taxonomies[catTax]
+}
+
+// Validate checks ids against taxonomy catTax (see Taxonomy.Validate); ErrUnknownTaxonomy is returned if it is not registered.
+func (r *Registry) Validate(catTax adcom1.CategoryTaxonomy, ids []string) error {
+	t := r.taxonomies[catTax]
+	if t == nil {
+		return ErrUnknownTaxonomy
+	}
+	return t.Validate(ids)
+}
+
+// Map maps ids of taxonomy from to taxonomy to, via the shortest chain of mappings (or their inverses).
+//
+// Categories without a mapping are mapped as their nearest mapped ancestor (if taxonomy from is registered), or skipped.
+// ErrNoMapping is returned if there is no chain of mappings between the taxonomies.
+func (r *Registry) Map(from, to adcom1.CategoryTaxonomy, ids []string) ([]string, error) {
+	if from == to {
+		return append([]string(nil), ids...), nil
+	}
+	chain := r.chain(from, to)
+	if chain == nil {
+		return nil, ErrNoMapping
+	}
+	for _, m := range chain {
+		ids = r.mapIDs(m, ids)
+	}
+	return ids, nil
+}
+
+// chain returns the shortest chain of mappings from taxonomy from to taxonomy to; direct mappings take precedence over inverse ones.
+func (r *Registry) chain(from, to adcom1.CategoryTaxonomy) []*Mapping {
+	via := map[adcom1.CategoryTaxonomy]*Mapping{from: nil}
+	for queue := []adcom1.CategoryTaxonomy{from}; len(queue) != 0; queue = queue[1:] {
+		for _, m := range r.edges[queue[0]] {
+			if _, ok := via[m.To]; ok {
+				continue
+			}
+			via[m.To] = m
+			if m.To != to {
+				queue = append(queue, m.To)
+				continue
+			}
+
+			var chain []*Mapping
+			for ct := to; ct != from; ct = via[ct].From {
+				chain = append([]*Mapping{via[ct]}, chain...)
+			}
+			return chain
+		}
+	}
+	return nil
+}
+
+// mapIDs maps ids with m, falling back to ancestors; results are deduplicated.
+func (r *Registry) mapIDs(m *Mapping, ids []string) []string {
+	var res []string
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		dsts := m.Map(id)
+		if dsts == nil {
+			if t := r.taxonomies[m.From]; t != nil {
+				for _, a := range t.Ancestors(id) {
+					if dsts = m.Map(a); dsts != nil {
+						break
+					}
+				}
+			}
+		}
+		for _, dst := range dsts {
+			if !seen[dst] {
+				seen[dst] = true
+				res = append(res, dst)
+			}
+		}
+	}
+	return res
+}
@@ -0,0 +1,202 @@
+// Package taxonomy provides IAB Tech Lab Content Taxonomy and Ad Product Taxonomy tables:
+// lookup of categories, validation of category IDs against the declared taxonomy (CatTax), and mapping between taxonomies
+//
+// https://iabtechlab.com/standards/content-taxonomy/
+package taxonomy
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+)
+
+// Errors.
+var (
+	ErrUnknownTaxonomy = errors.New("taxonomy: unknown taxonomy")
+	ErrNoMapping       = errors.New("taxonomy: no mapping between taxonomies")
+	ErrNoHeader        = errors.New("taxonomy: no header with Unique ID, Parent and Name columns")
+)
+
+// UnknownCategoryError reports category IDs, not found in the taxonomy.
+type UnknownCategoryError struct {
+	CatTax adcom1.CategoryTaxonomy
+	IDs    []string
+}
+
+// Error implements error.
+func (e *UnknownCategoryError) Error() string {
+	return fmt.Sprintf("taxonomy: unknown categories of taxonomy %d: %s", e.CatTax, strings.Join(e.IDs, ", "))
+}
+
+// Category is a category of a taxonomy.
+type Category struct {
+	ID     string
+	Name   string
+	Parent string // ID of the parent category; empty for Tier 1 categories
+	Tier   int    // 1 for top-level categories
+}
+
+// Taxonomy is a table of categories.
+type Taxonomy struct {
+	CatTax adcom1.CategoryTaxonomy
+
+	categories map[string]*Category
+	ids        []string // in order of the table
+}
+
+// New returns taxonomy of categories; their Tier is computed from Parent.
+func New(catTax adcom1.CategoryTaxonomy, categories []Category) (*Taxonomy, error) {
+	t := &Taxonomy{
+		CatTax:     catTax,
+		categories: make(map[string]*Category, len(categories)),
+		ids:        make([]string, 0, len(categories)),
+	}
+	for i := range categories {
+		c := categories[i]
+		if _, ok := t.categories[c.ID]; ok {
+			return nil, fmt.Errorf("taxonomy: duplicate category %q", c.ID)
+		}
+		t.categories[c.ID] = &c
+		t.ids = append(t.ids, c.ID)
+	}
+
+	for _, c := range t.categories {
+		c.Tier = 1
+		for p := c.Parent; p != ""; c.Tier++ {
+			parent, ok := t.categories[p]
+			if !ok {
+				return nil, fmt.Errorf("taxonomy: unknown parent %q of category %q", p, c.ID)
+			}
+			if c.Tier > len(t.categories) {
+				return nil, fmt.Errorf("taxonomy: cycle of parents of category %q", c.ID)
+			}
+			p = parent.Parent
+		}
+	}
+	return t, nil
+}
+
+// Load reads taxonomy in tab-separated format, as published by IAB Tech Lab.
+//
+// Rows preceding the header (the one with a "Unique ID" column) are skipped;
+// of other columns, only "Parent" (or "Parent ID") and "Name" are read.
+func Load(catTax adcom1.CategoryTaxonomy, r io.Reader) (*Taxonomy, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = '\t'
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+
+	id, parent, name := -1, -1, -1
+	var categories []Category
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if id == -1 {
+			for i, col := range row {
+				col = strings.ToLower(strings.TrimSpace(col))
+				switch {
+				case col == "unique id":
+					id = i
+				case strings.HasPrefix(col, "parent") && parent == -1:
+					parent = i
+				case col == "name" && name == -1:
+					name = i
+				}
+			}
+			if id == -1 || parent == -1 || name == -1 {
+				id, parent, name = -1, -1, -1
+			}
+			continue
+		}
+
+		c := Category{ID: column(row, id), Parent: column(row, parent), Name: column(row, name)}
+		if c.ID != "" {
+			categories = append(categories, c)
+		}
+	}
+	if id == -1 {
+		return nil, ErrNoHeader
+	}
+	return New(catTax, categories)
+}
+
+func column(row []string, i int) string {
+	if i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+// Content10 returns IAB Content Category Taxonomy 1.0 (as listed in OpenRTB 2.5, section 5.1), embedded in the package.
+func Content10() *Taxonomy {
+	return embedded[adcom1.CatTaxIABContent10]
+}
+
+// Len returns number of categories.
+func (t *Taxonomy) Len() int {
+	return len(t.ids)
+}
+
+// Categories returns all categories, in order of the table.
+func (t *Taxonomy) Categories() []Category {
+	res := make([]Category, len(t.ids))
+	for i, id := range t.ids {
+		res[i] = *t.categories[id]
+	}
+	return res
+}
+
+// Lookup returns category by ID.
+func (t *Taxonomy) Lookup(id string) (Category, bool) {
+	if c, ok := t.categories[id]; ok {
+		return *c, true
+	}
+	return Category{}, false
+}
+
+// Ancestors returns IDs of ancestors of category id, starting with its parent; nil for unknown categories.
+func (t *Taxonomy) Ancestors(id string) []string {
+	c, ok := t.categories[id]
+	if !ok {
+		return nil
+	}
+	res := make([]string, 0, c.Tier-1)
+	for c.Parent != "" {
+		res = append(res, c.Parent)
+		c = t.categories[c.Parent]
+	}
+	return res
+}
+
+// Contains reports, whether category id is category ancestor itself, or its descendant.
+func (t *Taxonomy) Contains(ancestor, id string) bool {
+	for c, ok := t.categories[id]; ok; c, ok = t.categories[c.Parent] {
+		if c.ID == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks, that all ids are categories of the taxonomy, returning *UnknownCategoryError otherwise.
+func (t *Taxonomy) Validate(ids []string) error {
+	var unknown []string
+	for _, id := range ids {
+		if _, ok := t.categories[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(unknown) != 0 {
+		return &UnknownCategoryError{CatTax: t.CatTax, IDs: unknown}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package taxonomy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+
+	. "github.com/prebid/openrtb/v20/taxonomy"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func load(catTax adcom1.CategoryTaxonomy, file string) *Taxonomy {
+	f, err := os.Open(filepath.Join("testdata", "sample", file))
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	t, err := Load(catTax, f)
+	Expect(err).NotTo(HaveOccurred())
+	return t
+}
+
+func loadMapping(from, to adcom1.CategoryTaxonomy, file string) *Mapping {
+	f, err := os.Open(filepath.Join("testdata", "sample", file))
+	Expect(err).NotTo(HaveOccurred())
+	defer f.Close()
+
+	m, err := LoadMapping(from, to, f)
+	Expect(err).NotTo(HaveOccurred())
+	return m
+}
+
+var _ = Describe("Content10", func() {
+	t := Content10()
+
+	It("should embed all categories", func() {
+		Expect(t.CatTax).To(Equal(adcom1.CatTaxIABContent10))
+		Expect(t.Len()).To(Equal(392))
+		Expect(t.Categories()[0]).To(Equal(Category{ID: "IAB1", Name: "Arts & Entertainment", Tier: 1}))
+	})
+
+	DescribeTable(
+		"Lookup",
+		func(id string, expected Category) {
+			c, ok := t.Lookup(id)
+			Expect(ok).To(BeTrue())
+			Expect(c).To(Equal(expected))
+		},
+		Entry("tier 1", "IAB7", Category{ID: "IAB7", Name: "Health & Fitness", Tier: 1}),
+		Entry("tier 2", "IAB7-45", Category{ID: "IAB7-45", Name: "Women's Health", Parent: "IAB7", Tier: 2}),
+		Entry("without children", "IAB24", Category{ID: "IAB24", Name: "Uncategorized", Tier: 1}),
+		Entry("last", "IAB26-4", Category{ID: "IAB26-4", Name: "Copyright Infringement", Parent: "IAB26", Tier: 2}),
+	)
+
+	It("should check hierarchy", func() {
+		Expect(t.Contains("IAB7", "IAB7-1")).To(BeTrue())
+		Expect(t.Contains("IAB7", "IAB7")).To(BeTrue())
+		Expect(t.Contains("IAB7-1", "IAB7")).To(BeFalse())
+		Expect(t.Contains("IAB7", "IAB17-1")).To(BeFalse())
+		Expect(t.Contains("IAB7", "IAB99")).To(BeFalse())
+		Expect(t.Ancestors("IAB7-1")).To(Equal([]string{"IAB7"}))
+		Expect(t.Ancestors("IAB7")).To(BeEmpty())
+	})
+
+	It("should validate", func() {
+		Expect(t.Validate([]string{"IAB1", "IAB25-3"})).To(Succeed())
+		Expect(t.Validate([]string{"IAB1", "IAB99", "IAB7-46"})).To(MatchError(&UnknownCategoryError{
+			CatTax: adcom1.CatTaxIABContent10,
+			IDs:    []string{"IAB99", "IAB7-46"},
+		}))
+	})
+})
+
+var _ = Describe("Load", func() {
+	It("should read IAB Tech Lab TSV", func() {
+		t := load(adcom1.CatTaxIABContent22, "content-2.2.tsv")
+		Expect(t.CatTax).To(Equal(adcom1.CatTaxIABContent22))
+		Expect(t.Len()).To(Equal(6))
+
+		c, ok := t.Lookup("3")
+		Expect(ok).To(BeTrue())
+		Expect(c).To(Equal(Category{ID: "3", Name: "Commercial Trucks", Parent: "2", Tier: 3}))
+		Expect(t.Ancestors("3")).To(Equal([]string{"2", "1"}))
+		Expect(t.Contains("1", "4")).To(BeTrue())
+	})
+
+	It("should read Parent ID column", func() {
+		t := load(adcom1.CatTaxIABProduct20, "product-2.0.tsv")
+		c, _ := t.Lookup("1101")
+		Expect(c).To(Equal(Category{ID: "1101", Name: "Gyms", Parent: "1100", Tier: 2}))
+	})
+
+	It("should fail without header", func() {
+		_, err := Load(adcom1.CatTaxIABContent22, strings.NewReader("1\t\tAutomotive\n"))
+		Expect(err).To(Equal(ErrNoHeader))
+	})
+
+	It("should fail on unknown parent", func() {
+		_, err := Load(adcom1.CatTaxIABContent22, strings.NewReader("Unique ID\tParent\tName\n2\t1\tAuto Body Styles\n"))
+		Expect(err).To(MatchError(`taxonomy: unknown parent "1" of category "2"`))
+	})
+
+	It("should fail on cycle", func() {
+		_, err := New(adcom1.CatTaxIABContent22, []Category{{ID: "1", Parent: "2"}, {ID: "2", Parent: "1"}})
+		Expect(err).To(MatchError(ContainSubstring("cycle")))
+	})
+})
+
+var _ = Describe("Registry", func() {
+	var r *Registry
+
+	BeforeEach(func() {
+		r = NewRegistry(
+			load(adcom1.CatTaxIABContent22, "content-2.2.tsv"),
+			load(adcom1.CatTaxIABProduct20, "product-2.0.tsv"),
+		)
+		r.AddMapping(loadMapping(adcom1.CatTaxIABContent22, adcom1.CatTaxIABContent10, "content-2.2-to-content-1.0.tsv"))
+		r.AddMapping(loadMapping(adcom1.CatTaxIABProduct20, adcom1.CatTaxIABContent22, "product-2.0-to-content-2.2.tsv"))
+	})
+
+	It("should validate against declared taxonomy", func() {
+		Expect(r.Validate(adcom1.CatTaxIABContent10, []string{"IAB2"})).To(Succeed())
+		Expect(r.Validate(adcom1.CatTaxIABContent22, []string{"IAB2"})).To(HaveOccurred())
+		Expect(r.Validate(adcom1.CategoryTaxonomy(500), []string{"IAB2"})).To(Equal(ErrUnknownTaxonomy))
+	})
+
+	DescribeTable(
+		"Map",
+		func(from, to adcom1.CategoryTaxonomy, ids, expected []string) {
+			Expect(r.Map(from, to, ids)).To(Equal(expected))
+		},
+		Entry("identity", adcom1.CatTaxIABContent10, adcom1.CatTaxIABContent10, []string{"IAB2"}, []string{"IAB2"}),
+		Entry("direct", adcom1.CatTaxIABContent22, adcom1.CatTaxIABContent10, []string{"3", "225"}, []string{"IAB2-21", "IAB7-1"}),
+		Entry("via ancestor", adcom1.CatTaxIABContent22, adcom1.CatTaxIABContent10, []string{"2"}, []string{"IAB2"}),
+		Entry("deduplicated", adcom1.CatTaxIABContent22, adcom1.CatTaxIABContent10, []string{"1", "2"}, []string{"IAB2"}),
+		Entry("inverse", adcom1.CatTaxIABContent10, adcom1.CatTaxIABContent22, []string{"IAB7-1"}, []string{"225"}),
+		Entry("chain", adcom1.CatTaxIABProduct20, adcom1.CatTaxIABContent10, []string{"1002", "1101"}, []string{"IAB2", "IAB7-1"}),
+		Entry("unmapped", adcom1.CatTaxIABContent10, adcom1.CatTaxIABContent22, []string{"IAB26"}, []string(nil)),
+	)
+
+	It("should fail without mapping", func() {
+		_, err := r.Map(adcom1.CatTaxIABContent10, adcom1.CategoryTaxonomy(500), []string{"IAB2"})
+		Expect(err).To(Equal(ErrNoMapping))
+	})
+
+	It("should register embedded taxonomies and mappings", func() {
+		r := NewRegistry()
+		Expect(r.Taxonomy(adcom1.CatTaxIABContent10)).To(BeIdenticalTo(Content10()))
+		for _, catTax := range []adcom1.CategoryTaxonomy{adcom1.CatTaxIABContent22, adcom1.CatTaxIABContent30, adcom1.CatTaxIABProduct20} {
+			Expect(r.Taxonomy(catTax)).To(BeIdenticalTo(Embedded(catTax)))
+		}
+		for _, m := range EmbeddedMappings() {
+			_, err := r.Map(m.From, m.To, nil)
+			Expect(err).NotTo(HaveOccurred())
+		}
+	})
+})
+
+var _ = Describe("LoadFS", func() {
+	It("should read taxonomies and mappings by file name", func() {
+		taxonomies, mappings, err := LoadFS(os.DirFS(filepath.Join("testdata", "sample")))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(taxonomies).To(HaveLen(2))
+		Expect(taxonomies[0].CatTax).To(Equal(adcom1.CatTaxIABContent22))
+		Expect(taxonomies[1].CatTax).To(Equal(adcom1.CatTaxIABProduct20))
+		Expect(mappings).To(HaveLen(2))
+		Expect(mappings[0].From).To(Equal(adcom1.CatTaxIABContent22))
+		Expect(mappings[0].To).To(Equal(adcom1.CatTaxIABContent10))
+
+		r := NewRegistry(taxonomies...)
+		for _, m := range mappings {
+			r.AddMapping(m)
+		}
+		Expect(r.Map(adcom1.CatTaxIABProduct20, adcom1.CatTaxIABContent10, []string{"1101"})).To(Equal([]string{"IAB7-1"}))
+	})
+
+	It("should report malformed files", func() {
+		_, _, err := LoadFS(fstest.MapFS{
+			"content-2.2.tsv": {Data: []byte("1\t\tAutomotive\n")},
+			"notes.tsv":       {Data: []byte("ignored")},
+		})
+		Expect(err).To(MatchError(ContainSubstring("content-2.2.tsv")))
+		Expect(errors.Is(err, ErrNoHeader)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Embedded", func() {
+	It("should embed every table of data directory", func() {
+		files, err := filepath.Glob(filepath.Join("data", "*.tsv"))
+		Expect(err).NotTo(HaveOccurred())
+
+		taxonomies, mappings, err := LoadFS(os.DirFS("data"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(len(taxonomies) + len(mappings)).To(Equal(len(files)))
+		for _, t := range taxonomies {
+			Expect(Embedded(t.CatTax)).NotTo(BeNil())
+			Expect(Embedded(t.CatTax).Len()).To(Equal(t.Len()))
+		}
+		Expect(EmbeddedMappings()).To(HaveLen(len(mappings)))
+	})
+
+	It("should look up Content Taxonomy 1.0", func() {
+		t := Embedded(adcom1.CatTaxIABContent10)
+		Expect(t).To(BeIdenticalTo(Content10()))
+		c, ok := t.Lookup("IAB7-1")
+		Expect(ok).To(BeTrue())
+		Expect(c).To(Equal(Category{ID: "IAB7-1", Name: "Exercise", Parent: "IAB7", Tier: 2}))
+		c, ok = t.Lookup("IAB26")
+		Expect(ok).To(BeTrue())
+		Expect(c).To(Equal(Category{ID: "IAB26", Name: "Illegal Content", Tier: 1}))
+		Expect(t.Ancestors("IAB26-3")).To(Equal([]string{"IAB26"}))
+	})
+})
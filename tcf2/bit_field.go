@@ -0,0 +1,12 @@
+package tcf2
+
+// BitField is a set of 1-based flags (e.g., purposes or special features), up to 64 entries.
+type BitField uint64
+
+// Has reports whether flag n (1-based) is set.
+func (f BitField) Has(n int) bool {
+	if n < 1 || n > 64 {
+		return false
+	}
+	return f&(1<<uint(n-1)) != 0
+}
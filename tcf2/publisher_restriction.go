@@ -0,0 +1,24 @@
+package tcf2
+
+// RestrictionType defines publisher restriction type.
+type RestrictionType int8
+
+// RestrictionType options.
+const (
+	RestrictionNotAllowed                RestrictionType = 0 // Purpose Flatly Not Allowed by Publisher
+	RestrictionRequireConsent            RestrictionType = 1 // Require Consent
+	RestrictionRequireLegitimateInterest RestrictionType = 2 // Require Legitimate Interest
+	RestrictionUndefined                 RestrictionType = 3 // Undefined
+)
+
+// PublisherRestriction restricts legal basis of processing for the purpose by listed vendors.
+type PublisherRestriction struct {
+	// PurposeID is the purpose the restriction applies to.
+	PurposeID int
+
+	// Type of the restriction.
+	Type RestrictionType
+
+	// Vendors the restriction applies to.
+	Vendors VendorSet
+}
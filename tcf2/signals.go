@@ -0,0 +1,75 @@
+package tcf2
+
+import (
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// Signals are GDPR signals carried by a bid request.
+type Signals struct {
+	// GDPR is the GDPR applicability flag, where 0 = no, 1 = yes; nil means unknown.
+	GDPR *int8
+
+	// Consent is the decoded TC string; nil if absent.
+	Consent *TCString
+}
+
+// FromBidRequest reads Regs.GDPR and User.Consent of OpenRTB 2.x bid request.
+//
+// If consent string is malformed, non-nil Signals (with nil Consent) are returned along with error.
+func FromBidRequest(req *openrtb2.BidRequest) (*Signals, error) {
+	s := new(Signals)
+	if req.Regs != nil {
+		s.GDPR = req.Regs.GDPR
+	}
+	if req.User == nil || req.User.Consent == "" {
+		return s, nil
+	}
+
+	tc, err := Parse(req.User.Consent)
+	if err != nil {
+		return s, err
+	}
+	s.Consent = tc
+	return s, nil
+}
+
+// FromAdCOM reads Regs.GDPR and User.Consent of AdCOM context (e.g., openrtb3 Request.Context).
+//
+// If consent string is malformed, non-nil Signals (with nil Consent) are returned along with error.
+func FromAdCOM(regs *adcom1.Regs, user *adcom1.User) (*Signals, error) {
+	s := new(Signals)
+	if regs != nil {
+		s.GDPR = regs.GDPR
+	}
+	if user == nil || user.Consent == "" {
+		return s, nil
+	}
+
+	tc, err := Parse(user.Consent)
+	if err != nil {
+		return s, err
+	}
+	s.Consent = tc
+	return s, nil
+}
+
+// Applies reports whether GDPR applies: either flagged explicitly or, when unknown, a consent string is present.
+func (s *Signals) Applies() bool {
+	if s.GDPR != nil {
+		return *s.GDPR == 1
+	}
+	return s.Consent != nil
+}
+
+// PersonalDataAllowed reports whether personal data may be forwarded to vendor (e.g., a bidder),
+// i.e., GDPR does not apply or vendor is allowed storage and access (purpose 1) and all of given purposes.
+func (s *Signals) PersonalDataAllowed(vendor int, purposes ...int) bool {
+	if !s.Applies() {
+		return true
+	}
+	if s.Consent == nil {
+		return false
+	}
+	return s.Consent.Allows(vendor, append([]int{1}, purposes...)...)
+}
@@ -0,0 +1,291 @@
+// Package tcf2 provides IAB Europe Transparency & Consent Framework (TCF) v2 consent string decoder
+//
+// It is intended to interpret User.Consent of openrtb2 and adcom1 bid requests.
+//
+// https://github.com/InteractiveAdvertisingBureau/GDPR-Transparency-and-Consent-Framework/blob/master/TCFv2/IAB%20Tech%20Lab%20-%20Consent%20string%20and%20vendor%20list%20formats%20v2.md
+package tcf2
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prebid/openrtb/v20/internal/bitstr"
+)
+
+// Version is the only supported TC string version.
+const Version = 2
+
+// Errors, reported by Parse.
+var (
+	ErrEmpty              = errors.New("tcf2: empty consent string")
+	ErrUnsupportedVersion = errors.New("tcf2: unsupported consent string version")
+)
+
+// TCString is a decoded TC string.
+type TCString struct {
+	// Core segment.
+
+	// Version of the TC string format.
+	Version int
+
+	// Created is the time of TC string creation (with decisecond precision).
+	Created time.Time
+
+	// LastUpdated is the time of TC string last update (with decisecond precision).
+	LastUpdated time.Time
+
+	// CMPID is Consent Management Platform ID that last updated the TC string.
+	CMPID int
+
+	// CMPVersion is Consent Management Platform version.
+	CMPVersion int
+
+	// ConsentScreen is CMP screen number at which consent was given.
+	ConsentScreen int
+
+	// ConsentLanguage is two-letter ISO 639-1 language code (upper case) in which the CMP UI was presented.
+	ConsentLanguage string
+
+	// VendorListVersion is the version of the Global Vendor List used in the most recent update.
+	VendorListVersion int
+
+	// TCFPolicyVersion is the version of policy used within GVL.
+	TCFPolicyVersion int
+
+	// IsServiceSpecific indicates whether the signals are service-specific (not global).
+	IsServiceSpecific bool
+
+	// UseNonStandardTexts indicates that publisher customized stack descriptions and/or modified or supplemented standard illustrations.
+	UseNonStandardTexts bool
+
+	// SpecialFeatureOptIns holds user opt-ins for special features.
+	SpecialFeatureOptIns BitField
+
+	// PurposesConsent holds user consents for purposes.
+	PurposesConsent BitField
+
+	// PurposesLITransparency holds purposes, for which legitimate interest was established (and not objected to by user).
+	PurposesLITransparency BitField
+
+	// PurposeOneTreatment indicates that purpose 1 was not disclosed (e.g., because of country-specific rules).
+	PurposeOneTreatment bool
+
+	// PublisherCC is two-letter ISO 3166-1 alpha-2 country code of the publisher, determining legislation of reference.
+	PublisherCC string
+
+	// VendorConsents holds vendors, which received user consent.
+	VendorConsents VendorSet
+
+	// VendorLegitimateInterests holds vendors, for which legitimate interest was established (and not objected to by user).
+	VendorLegitimateInterests VendorSet
+
+	// PublisherRestrictions holds publisher restrictions of vendor processing.
+	PublisherRestrictions []PublisherRestriction
+
+	// Optional segments.
+
+	// DisclosedVendors holds vendors disclosed to the user; nil if segment is absent.
+	DisclosedVendors *VendorSet
+
+	// AllowedVendors holds vendors, the publisher allows to use OOB signals; nil if segment is absent.
+	// Deprecated by TCF v2.2, but still can be found in traffic.
+	AllowedVendors *VendorSet
+
+	// PublisherTC holds publisher purposes transparency and consent; nil if segment is absent.
+	PublisherTC *PublisherTC
+}
+
+// PublisherTC is a decoded publisher purposes transparency and consent segment.
+type PublisherTC struct {
+	// PurposesConsent holds user consents for publisher purposes.
+	PurposesConsent BitField
+
+	// PurposesLITransparency holds publisher purposes, for which legitimate interest was established.
+	PurposesLITransparency BitField
+
+	// NumCustomPurposes is the number of custom purposes.
+	NumCustomPurposes int
+
+	// CustomPurposesConsent holds user consents for custom purposes.
+	CustomPurposesConsent BitField
+
+	// CustomPurposesLITransparency holds custom purposes, for which legitimate interest was established.
+	CustomPurposesLITransparency BitField
+}
+
+// Segment types of optional segments.
+const (
+	segmentDisclosedVendors = 1
+	segmentAllowedVendors   = 2
+	segmentPublisherTC      = 3
+)
+
+// Parse decodes TC string.
+func Parse(s string) (*TCString, error) {
+	if s == "" {
+		return nil, ErrEmpty
+	}
+
+	segments := strings.Split(s, ".")
+	tc := new(TCString)
+
+	for i, segment := range segments {
+		buf, err := bitstr.Decode(segment)
+		if err != nil {
+			return nil, fmt.Errorf("tcf2: segment %d: %w", i, err)
+		}
+		r := bitstr.NewReader(buf)
+
+		if i == 0 {
+			if err := tc.decodeCore(r); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		switch typ := r.Int(3); typ {
+		case segmentDisclosedVendors:
+			vs := readVendorSet(r)
+			tc.DisclosedVendors = &vs
+		case segmentAllowedVendors:
+			vs := readVendorSet(r)
+			tc.AllowedVendors = &vs
+		case segmentPublisherTC:
+			tc.PublisherTC = readPublisherTC(r)
+		default:
+			return nil, fmt.Errorf("tcf2: segment %d: unexpected segment type %d", i, typ)
+		}
+		if err := r.Err(); err != nil {
+			return nil, fmt.Errorf("tcf2: segment %d: %w", i, err)
+		}
+	}
+
+	return tc, nil
+}
+
+func (tc *TCString) decodeCore(r *bitstr.Reader) error {
+	tc.Version = int(r.Int(6))
+	if r.Err() == nil && tc.Version != Version {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, tc.Version)
+	}
+
+	tc.Created = r.Time()
+	tc.LastUpdated = r.Time()
+	tc.CMPID = int(r.Int(12))
+	tc.CMPVersion = int(r.Int(12))
+	tc.ConsentScreen = int(r.Int(6))
+	tc.ConsentLanguage = r.Letters(2)
+	tc.VendorListVersion = int(r.Int(12))
+	tc.TCFPolicyVersion = int(r.Int(6))
+	tc.IsServiceSpecific = r.Bool()
+	tc.UseNonStandardTexts = r.Bool()
+	tc.SpecialFeatureOptIns = BitField(r.Flags(12))
+	tc.PurposesConsent = BitField(r.Flags(24))
+	tc.PurposesLITransparency = BitField(r.Flags(24))
+	tc.PurposeOneTreatment = r.Bool()
+	tc.PublisherCC = r.Letters(2)
+	tc.VendorConsents = readVendorSet(r)
+	tc.VendorLegitimateInterests = readVendorSet(r)
+
+	n := int(r.Int(12))
+	for i := 0; i < n && r.Err() == nil; i++ {
+		pr := PublisherRestriction{
+			PurposeID: int(r.Int(6)),
+			Type:      RestrictionType(r.Int(2)),
+		}
+		pr.Vendors = readRanges(r)
+		tc.PublisherRestrictions = append(tc.PublisherRestrictions, pr)
+	}
+
+	if err := r.Err(); err != nil {
+		return fmt.Errorf("tcf2: core segment: %w", err)
+	}
+	return nil
+}
+
+func readPublisherTC(r *bitstr.Reader) *PublisherTC {
+	p := &PublisherTC{
+		PurposesConsent:        BitField(r.Flags(24)),
+		PurposesLITransparency: BitField(r.Flags(24)),
+		NumCustomPurposes:      int(r.Int(6)),
+	}
+	p.CustomPurposesConsent = BitField(r.Flags(p.NumCustomPurposes))
+	p.CustomPurposesLITransparency = BitField(r.Flags(p.NumCustomPurposes))
+	return p
+}
+
+// VendorConsent reports whether vendor received user consent.
+func (tc *TCString) VendorConsent(id int) bool {
+	return tc.VendorConsents.Has(id)
+}
+
+// VendorLegitimateInterest reports whether vendor legitimate interest was established.
+func (tc *TCString) VendorLegitimateInterest(id int) bool {
+	return tc.VendorLegitimateInterests.Has(id)
+}
+
+// PurposeConsent reports whether purpose n (1-based) received user consent.
+func (tc *TCString) PurposeConsent(n int) bool {
+	return tc.PurposesConsent.Has(n)
+}
+
+// PurposeLITransparency reports whether legitimate interest for purpose n (1-based) was established.
+func (tc *TCString) PurposeLITransparency(n int) bool {
+	return tc.PurposesLITransparency.Has(n)
+}
+
+// SpecialFeatureOptIn reports whether user opted in special feature n (1-based).
+func (tc *TCString) SpecialFeatureOptIn(n int) bool {
+	return tc.SpecialFeatureOptIns.Has(n)
+}
+
+// Restriction returns publisher restriction type for vendor processing for purpose, if any.
+func (tc *TCString) Restriction(vendor, purpose int) (RestrictionType, bool) {
+	for _, pr := range tc.PublisherRestrictions {
+		if pr.PurposeID == purpose && pr.Vendors.Has(vendor) {
+			return pr.Type, true
+		}
+	}
+	return 0, false
+}
+
+// Allows reports whether vendor may process personal data for all of given purposes (1-based).
+//
+// Each purpose is allowed either by consent (both purpose and vendor consent given)
+// or by legitimate interest (both purpose and vendor legitimate interest established),
+// taking publisher restrictions into account.
+// Purposes 1, 3, 4, 5 and 6 can only be allowed by consent, as per TCF policy.
+//
+// Global Vendor List is not consulted, so purposes and legal bases declared by vendor are not verified.
+func (tc *TCString) Allows(vendor int, purposes ...int) bool {
+	for _, p := range purposes {
+		byConsent := tc.PurposeConsent(p) && tc.VendorConsent(vendor)
+		byLI := !consentOnlyPurpose(p) && tc.PurposeLITransparency(p) && tc.VendorLegitimateInterest(vendor)
+
+		if rt, ok := tc.Restriction(vendor, p); ok {
+			switch rt {
+			case RestrictionNotAllowed:
+				return false
+			case RestrictionRequireConsent:
+				byLI = false
+			case RestrictionRequireLegitimateInterest:
+				byConsent = false
+			}
+		}
+
+		if !byConsent && !byLI {
+			return false
+		}
+	}
+	return true
+}
+
+func consentOnlyPurpose(p int) bool {
+	switch p {
+	case 1, 3, 4, 5, 6:
+		return true
+	}
+	return false
+}
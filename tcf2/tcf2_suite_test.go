@@ -0,0 +1,13 @@
+package tcf2_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTcf2(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Tcf2 Suite")
+}
@@ -0,0 +1,197 @@
+package tcf2_test
+
+import (
+	"encoding/base64"
+	"time"
+
+	. "github.com/prebid/openrtb/v20/tcf2"
+
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// bitWriter builds bit strings for test fixtures.
+type bitWriter []bool
+
+func (w *bitWriter) int(v uint64, n int) *bitWriter {
+	for i := n - 1; i >= 0; i-- {
+		*w = append(*w, v>>uint(i)&1 == 1)
+	}
+	return w
+}
+
+func (w *bitWriter) flags(n int, set ...int) *bitWriter {
+	for i := 1; i <= n; i++ {
+		on := false
+		for _, s := range set {
+			on = on || s == i
+		}
+		*w = append(*w, on)
+	}
+	return w
+}
+
+func (w *bitWriter) letters(s string) *bitWriter {
+	for _, c := range s {
+		w.int(uint64(c-'A'), 6)
+	}
+	return w
+}
+
+func (w *bitWriter) String() string {
+	buf := make([]byte, (len(*w)+7)/8)
+	for i, b := range *w {
+		if b {
+			buf[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// core builds core segment with vendor consents {1..3, 10} (range-encoded), vendor LI {2} (bit field)
+// and single publisher restriction: purpose 2, require consent, vendors 5-7.
+func core() *bitWriter {
+	w := new(bitWriter)
+	w.int(2, 6)            // Version
+	w.int(16000000000, 36) // Created
+	w.int(16000000010, 36) // LastUpdated
+	w.int(7, 12)           // CmpId
+	w.int(3, 12)           // CmpVersion
+	w.int(1, 6)            // ConsentScreen
+	w.letters("EN")        // ConsentLanguage
+	w.int(150, 12)         // VendorListVersion
+	w.int(4, 6)            // TcfPolicyVersion
+	w.int(0, 1)            // IsServiceSpecific
+	w.int(0, 1)            // UseNonStandardTexts
+	w.flags(12, 1)         // SpecialFeatureOptIns
+	w.flags(24, 1, 2, 3)   // PurposesConsent
+	w.flags(24, 2, 7)      // PurposesLITransparency
+	w.int(0, 1)            // PurposeOneTreatment
+	w.letters("DE")        // PublisherCC
+
+	// Vendor Consent Section (range)
+	w.int(10, 16).int(1, 1).int(2, 12)
+	w.int(1, 1).int(1, 16).int(3, 16)
+	w.int(0, 1).int(10, 16)
+
+	// Vendor Legitimate Interest Section (bit field)
+	w.int(3, 16).int(0, 1).flags(3, 2)
+
+	// Publisher Restrictions Section
+	w.int(1, 12)
+	w.int(2, 6).int(1, 2).int(1, 12).int(1, 1).int(5, 16).int(7, 16)
+
+	return w
+}
+
+var _ = Describe("Parse", func() {
+	It("should decode core segment", func() {
+		tc, err := Parse(core().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tc.Version).To(Equal(2))
+		Expect(tc.Created).To(Equal(time.Unix(1600000000, 0).UTC()))
+		Expect(tc.LastUpdated).To(Equal(time.Unix(1600000001, 0).UTC()))
+		Expect(tc.CMPID).To(Equal(7))
+		Expect(tc.CMPVersion).To(Equal(3))
+		Expect(tc.ConsentScreen).To(Equal(1))
+		Expect(tc.ConsentLanguage).To(Equal("EN"))
+		Expect(tc.VendorListVersion).To(Equal(150))
+		Expect(tc.TCFPolicyVersion).To(Equal(4))
+		Expect(tc.PublisherCC).To(Equal("DE"))
+		Expect(tc.SpecialFeatureOptIn(1)).To(BeTrue())
+		Expect(tc.SpecialFeatureOptIn(2)).To(BeFalse())
+
+		Expect(tc.PurposeConsent(1)).To(BeTrue())
+		Expect(tc.PurposeConsent(3)).To(BeTrue())
+		Expect(tc.PurposeConsent(4)).To(BeFalse())
+		Expect(tc.PurposeLITransparency(7)).To(BeTrue())
+
+		Expect(tc.VendorConsents.IDs()).To(Equal([]int{1, 2, 3, 10}))
+		Expect(tc.VendorConsent(10)).To(BeTrue())
+		Expect(tc.VendorConsent(11)).To(BeFalse())
+		Expect(tc.VendorLegitimateInterests.IDs()).To(Equal([]int{2}))
+
+		rt, ok := tc.Restriction(6, 2)
+		Expect(ok).To(BeTrue())
+		Expect(rt).To(Equal(RestrictionRequireConsent))
+		_, ok = tc.Restriction(8, 2)
+		Expect(ok).To(BeFalse())
+
+		Expect(tc.DisclosedVendors).To(BeNil())
+		Expect(tc.PublisherTC).To(BeNil())
+	})
+
+	It("should decode optional segments", func() {
+		disclosed := new(bitWriter).int(1, 3).int(5, 16).int(0, 1).flags(5, 1, 5)
+		pubTC := new(bitWriter).int(3, 3).flags(24, 1).flags(24).int(2, 6).flags(2, 2).flags(2)
+
+		tc, err := Parse(core().String() + "." + disclosed.String() + "." + pubTC.String())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tc.DisclosedVendors).NotTo(BeNil())
+		Expect(tc.DisclosedVendors.IDs()).To(Equal([]int{1, 5}))
+		Expect(tc.PublisherTC).NotTo(BeNil())
+		Expect(tc.PublisherTC.PurposesConsent.Has(1)).To(BeTrue())
+		Expect(tc.PublisherTC.NumCustomPurposes).To(Equal(2))
+		Expect(tc.PublisherTC.CustomPurposesConsent.Has(2)).To(BeTrue())
+		Expect(tc.PublisherTC.CustomPurposesConsent.Has(1)).To(BeFalse())
+	})
+
+	It("should reject malformed strings", func() {
+		_, err := Parse("")
+		Expect(err).To(MatchError(ErrEmpty))
+
+		_, err = Parse(new(bitWriter).int(1, 6).int(0, 100).String())
+		Expect(err).To(MatchError(ContainSubstring("unsupported")))
+
+		_, err = Parse(core().String()[:20])
+		Expect(err).To(HaveOccurred())
+
+		_, err = Parse("!!!")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should evaluate purposes with publisher restrictions", func() {
+		tc, err := Parse(core().String())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(tc.Allows(1, 1, 2, 3)).To(BeTrue())
+		Expect(tc.Allows(1, 4)).To(BeFalse())
+		Expect(tc.Allows(2, 7)).To(BeTrue())  // legitimate interest
+		Expect(tc.Allows(2, 1)).To(BeTrue())  // consent
+		Expect(tc.Allows(4, 1)).To(BeFalse()) // no vendor consent
+	})
+})
+
+var _ = Describe("Signals", func() {
+	It("should read bid request", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1)},
+			User: &openrtb2.User{Consent: core().String()},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Applies()).To(BeTrue())
+		Expect(s.PersonalDataAllowed(1)).To(BeTrue())
+		Expect(s.PersonalDataAllowed(1, 4)).To(BeFalse())
+		Expect(s.PersonalDataAllowed(4)).To(BeFalse())
+	})
+
+	It("should allow when GDPR does not apply", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s.Applies()).To(BeFalse())
+		Expect(s.PersonalDataAllowed(4)).To(BeTrue())
+	})
+
+	It("should deny when GDPR applies without consent", func() {
+		s, err := FromBidRequest(&openrtb2.BidRequest{
+			Regs: &openrtb2.Regs{GDPR: openrtb2.Int8Ptr(1)},
+			User: &openrtb2.User{Consent: "!!!"},
+		})
+		Expect(err).To(HaveOccurred())
+		Expect(s.PersonalDataAllowed(1)).To(BeFalse())
+	})
+})
@@ -0,0 +1,80 @@
+package tcf2
+
+import "github.com/prebid/openrtb/v20/internal/bitstr"
+
+// VendorSet is a set of vendor IDs, decoded from either bit field or range encoding.
+type VendorSet struct {
+	// MaxVendorID is the maximum vendor ID, that is represented in the set.
+	MaxVendorID int
+
+	// IsRangeEncoding indicates that set was range-encoded.
+	IsRangeEncoding bool
+
+	bits   []bool
+	ranges []vendorRange
+}
+
+type vendorRange struct {
+	start, end int
+}
+
+// Has reports whether vendor belongs to the set.
+func (vs *VendorSet) Has(id int) bool {
+	if vs == nil || id < 1 || id > vs.MaxVendorID {
+		return false
+	}
+	if !vs.IsRangeEncoding {
+		return id <= len(vs.bits) && vs.bits[id-1]
+	}
+	for _, r := range vs.ranges {
+		if id >= r.start && id <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// IDs returns all vendor IDs of the set in ascending order.
+func (vs *VendorSet) IDs() []int {
+	var ids []int
+	for id := 1; id <= vs.MaxVendorID; id++ {
+		if vs.Has(id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// readVendorSet reads vendor section: MaxVendorId, IsRangeEncoding, then either bit field or ranges.
+func readVendorSet(r *bitstr.Reader) VendorSet {
+	vs := VendorSet{
+		MaxVendorID:     int(r.Int(16)),
+		IsRangeEncoding: r.Bool(),
+	}
+	if vs.IsRangeEncoding {
+		vs.ranges = readRanges(r).ranges
+	} else {
+		vs.bits = r.Bools(vs.MaxVendorID)
+	}
+	return vs
+}
+
+// readRanges reads range entries: NumEntries, then IsARange, StartOrOnlyVendorId and optional EndVendorId per entry.
+func readRanges(r *bitstr.Reader) VendorSet {
+	vs := VendorSet{IsRangeEncoding: true}
+
+	n := int(r.Int(12))
+	for i := 0; i < n && r.Err() == nil; i++ {
+		isRange := r.Bool()
+		vr := vendorRange{start: int(r.Int(16))}
+		vr.end = vr.start
+		if isRange {
+			vr.end = int(r.Int(16))
+		}
+		if vr.end > vs.MaxVendorID {
+			vs.MaxVendorID = vr.end
+		}
+		vs.ranges = append(vs.ranges, vr)
+	}
+	return vs
+}
@@ -0,0 +1,144 @@
+package trafficgen
+
+import "github.com/prebid/openrtb/v20/adcom1"
+
+// deviceProfile is a plausible combination of device attributes.
+type deviceProfile struct {
+	ua         string
+	make       string
+	model      string
+	os         string
+	osv        string
+	deviceType adcom1.DeviceType
+	w, h       int64
+	pxRatio    float64
+	browser    string // client hints brand
+	browserVer string
+	platform   string // client hints platform
+	mobile     int8
+	app        bool // whether the profile is used for app traffic
+}
+
+var devices = []deviceProfile{
+	{
+		ua:   "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Mobile/15E148 Safari/604.1",
+		make: "Apple", model: "iPhone", os: "iOS", osv: "17.4", deviceType: adcom1.DevicePhone,
+		w: 390, h: 844, pxRatio: 3, mobile: 1, app: true,
+	},
+	{
+		ua:   "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Mobile Safari/537.36",
+		make: "Google", model: "Pixel 8", os: "Android", osv: "14", deviceType: adcom1.DevicePhone,
+		w: 412, h: 915, pxRatio: 2.625, browser: "Google Chrome", browserVer: "124.0.6367.82", platform: "Android", mobile: 1, app: true,
+	},
+	{
+		ua:   "Mozilla/5.0 (Linux; Android 13; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Mobile Safari/537.36",
+		make: "Samsung", model: "SM-S911B", os: "Android", osv: "13", deviceType: adcom1.DevicePhone,
+		w: 360, h: 780, pxRatio: 3, browser: "Google Chrome", browserVer: "123.0.6312.99", platform: "Android", mobile: 1, app: true,
+	},
+	{
+		ua:   "Mozilla/5.0 (iPad; CPU OS 16_6 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.6 Mobile/15E148 Safari/604.1",
+		make: "Apple", model: "iPad", os: "iOS", osv: "16.6", deviceType: adcom1.DeviceTablet,
+		w: 820, h: 1180, pxRatio: 2, mobile: 1, app: true,
+	},
+	{
+		ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36",
+		make: "", model: "", os: "Windows", osv: "10", deviceType: adcom1.DevicePC,
+		w: 1920, h: 1080, pxRatio: 1, browser: "Google Chrome", browserVer: "124.0.6367.91", platform: "Windows", mobile: 0,
+	},
+	{
+		ua:   "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36 Edg/124.0.0.0",
+		make: "", model: "", os: "Windows", osv: "10", deviceType: adcom1.DevicePC,
+		w: 1536, h: 864, pxRatio: 1.25, browser: "Microsoft Edge", browserVer: "124.0.2478.67", platform: "Windows", mobile: 0,
+	},
+	{
+		ua:   "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.4 Safari/605.1.15",
+		make: "Apple", model: "", os: "macOS", osv: "14.4", deviceType: adcom1.DevicePC,
+		w: 1440, h: 900, pxRatio: 2, mobile: 0,
+	},
+	{
+		ua:   "Roku/DVP-12.5 (12.5.0.4178-46)",
+		make: "Roku", model: "4850X", os: "Roku OS", osv: "12.5", deviceType: adcom1.DeviceConnected,
+		w: 1920, h: 1080, pxRatio: 1, mobile: 0, app: true,
+	},
+	{
+		ua:   "Mozilla/5.0 (SMART-TV; LINUX; Tizen 7.0) AppleWebKit/537.36 (KHTML, like Gecko) 94.0.4606.31/7.0 TV Safari/537.36",
+		make: "Samsung", model: "QN65Q80C", os: "Tizen", osv: "7.0", deviceType: adcom1.DeviceConnected,
+		w: 1920, h: 1080, pxRatio: 1, mobile: 0, app: true,
+	},
+}
+
+// location is a plausible geo location.
+type location struct {
+	country, region, city, metro, zip string
+	lat, lon                          float64
+	utcOffset                         int64
+	lang                              string
+	gdpr                              bool
+}
+
+var locations = []location{
+	{country: "USA", region: "NY", city: "New York", metro: "501", zip: "10001", lat: 40.7506, lon: -73.9971, utcOffset: -240, lang: "en"},
+	{country: "USA", region: "CA", city: "Los Angeles", metro: "803", zip: "90012", lat: 34.0614, lon: -118.2385, utcOffset: -420, lang: "en"},
+	{country: "USA", region: "IL", city: "Chicago", metro: "602", zip: "60601", lat: 41.8858, lon: -87.6181, utcOffset: -300, lang: "en"},
+	{country: "USA", region: "TX", city: "Houston", metro: "618", zip: "77002", lat: 29.7569, lon: -95.3625, utcOffset: -300, lang: "en"},
+	{country: "CAN", region: "ON", city: "Toronto", zip: "M5H", lat: 43.6511, lon: -79.3832, utcOffset: -240, lang: "en"},
+	{country: "GBR", region: "ENG", city: "London", zip: "EC1A", lat: 51.5155, lon: -0.0922, utcOffset: 60, lang: "en", gdpr: true},
+	{country: "DEU", region: "BE", city: "Berlin", zip: "10117", lat: 52.5163, lon: 13.3777, utcOffset: 120, lang: "de", gdpr: true},
+	{country: "FRA", region: "IDF", city: "Paris", zip: "75001", lat: 48.8606, lon: 2.3376, utcOffset: 120, lang: "fr", gdpr: true},
+	{country: "ESP", region: "MD", city: "Madrid", zip: "28013", lat: 40.4168, lon: -3.7038, utcOffset: 120, lang: "es", gdpr: true},
+	{country: "BRA", region: "SP", city: "São Paulo", zip: "01310", lat: -23.5613, lon: -46.6565, utcOffset: -180, lang: "pt"},
+	{country: "JPN", region: "13", city: "Tokyo", zip: "100-0005", lat: 35.6812, lon: 139.7671, utcOffset: 540, lang: "ja"},
+	{country: "AUS", region: "NSW", city: "Sydney", zip: "2000", lat: -33.8688, lon: 151.2093, utcOffset: 600, lang: "en"},
+}
+
+// publisherProfile is a plausible site/app publisher.
+type publisherProfile struct {
+	id, name, domain string
+	cat              []string
+	sections         []string
+	bundle           string // app bundle; empty for sites
+	storeURL         string
+}
+
+var sites = []publisherProfile{
+	{id: "pub-1001", name: "Daily Metro News", domain: "dailymetronews.com", cat: []string{"IAB12"}, sections: []string{"politics", "world", "local"}},
+	{id: "pub-1002", name: "Kitchen Stories", domain: "kitchenstories.net", cat: []string{"IAB8"}, sections: []string{"recipes", "reviews"}},
+	{id: "pub-1003", name: "Gadget Digest", domain: "gadgetdigest.io", cat: []string{"IAB19"}, sections: []string{"phones", "laptops", "deals"}},
+	{id: "pub-1004", name: "Trail & Summit", domain: "trailandsummit.com", cat: []string{"IAB17", "IAB20"}, sections: []string{"hiking", "gear"}},
+	{id: "pub-1005", name: "Weekend Score", domain: "weekendscore.co.uk", cat: []string{"IAB17"}, sections: []string{"football", "tennis"}},
+	{id: "pub-1006", name: "Money Matters", domain: "moneymatters.finance", cat: []string{"IAB13"}, sections: []string{"markets", "retirement"}},
+}
+
+var apps = []publisherProfile{
+	{id: "pub-2001", name: "Block Puzzle Saga", domain: "blockpuzzlesaga.com", cat: []string{"IAB9-30"}, bundle: "com.bps.blockpuzzle", storeURL: "https://play.google.com/store/apps/details?id=com.bps.blockpuzzle"},
+	{id: "pub-2002", name: "Weather Now", domain: "weathernow.app", cat: []string{"IAB15-10"}, bundle: "1436789012", storeURL: "https://apps.apple.com/app/id1436789012"},
+	{id: "pub-2003", name: "Run Tracker", domain: "runtracker.fit", cat: []string{"IAB7-44"}, bundle: "fit.runtracker.android", storeURL: "https://play.google.com/store/apps/details?id=fit.runtracker.android"},
+	{id: "pub-2004", name: "StreamBox TV", domain: "streamboxtv.com", cat: []string{"IAB1-7"}, bundle: "com.streambox.tv", storeURL: "https://channelstore.roku.com/details/streambox"},
+	{id: "pub-2005", name: "Word Master", domain: "wordmaster.games", cat: []string{"IAB9-30"}, bundle: "1523456789", storeURL: "https://apps.apple.com/app/id1523456789"},
+}
+
+var screens = []publisherProfile{
+	{id: "pub-3001", name: "Metro Transit Screens", domain: "metrotransitmedia.com", sections: []string{"transit.subway", "transit.bus"}},
+	{id: "pub-3002", name: "Mall Media Network", domain: "mallmedianet.com", sections: []string{"retail.malls"}},
+	{id: "pub-3003", name: "Roadside Digital", domain: "roadsidedigital.com", sections: []string{"outdoor.billboards"}},
+}
+
+var bannerSizes = [][2]int64{
+	{300, 250}, {728, 90}, {320, 50}, {160, 600}, {300, 600}, {970, 250}, {320, 100}, {336, 280},
+}
+
+var videoMIMEs = []string{"video/mp4", "video/webm", "application/javascript"}
+
+var audioMIMEs = []string{"audio/mp4", "audio/mpeg", "audio/ogg"}
+
+var eidSources = []string{"liveramp.com", "id5-sync.com", "uidapi.com", "criteo.com", "pubcid.org"}
+
+var schainNodes = []string{"exchange-one.com", "ssp-two.net", "reseller-three.com"}
+
+var seats = []string{"seat-101", "seat-102", "seat-103", "seat-104", "seat-105", "seat-106"}
+
+var advertisers = []string{"shoesandmore.com", "fastcars.example", "greenbank.com", "sunnytravel.com", "brightphone.com", "freshgrocer.com"}
+
+var blockedCategories = []string{"IAB7-39", "IAB8-18", "IAB8-5", "IAB9-9", "IAB25", "IAB26"}
+
+var carriers = []string{"Verizon", "T-Mobile", "AT&T", "Vodafone", "Orange", "Movistar"}
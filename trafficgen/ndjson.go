@@ -0,0 +1,46 @@
+package trafficgen
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// WriteNDJSON writes n generated bid requests to requests, and, unless responses is nil, bid responses to them to responses,
+// as newline-delimited JSON.
+// Lines of responses match lines of requests; no bid is written as null.
+func (g *Generator) WriteNDJSON(requests, responses io.Writer, n int) error {
+	reqw := bufio.NewWriter(requests)
+	reqe := json.NewEncoder(reqw)
+	reqe.SetEscapeHTML(false)
+
+	var (
+		respw *bufio.Writer
+		respe *json.Encoder
+	)
+	if responses != nil {
+		respw = bufio.NewWriter(responses)
+		respe = json.NewEncoder(respw)
+		respe.SetEscapeHTML(false)
+	}
+
+	for i := 0; i < n; i++ {
+		req := g.Request()
+		if err := reqe.Encode(req); err != nil {
+			return err
+		}
+		if respe != nil {
+			if err := respe.Encode(g.Response(req)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := reqw.Flush(); err != nil {
+		return err
+	}
+	if respw != nil {
+		return respw.Flush()
+	}
+	return nil
+}
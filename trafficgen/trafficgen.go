@@ -0,0 +1,733 @@
+// Package trafficgen provides generation of synthetic, but plausible OpenRTB 2.6 bid requests and bid responses,
+// for load tests, benchmarks and fuzzing
+//
+// Generation is deterministic: generators, created with the same Config (including Seed),
+// produce the same sequence of requests and responses.
+//
+// https://github.com/InteractiveAdvertisingBureau/openrtb2.x/blob/main/2.6.md
+package trafficgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/prebid/openrtb/v20/adcom1"
+	"github.com/prebid/openrtb/v20/native1"
+	nativeRequest "github.com/prebid/openrtb/v20/native1/request"
+	nativeResponse "github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+)
+
+// MediaWeights are relative weights of media types of generated impressions.
+type MediaWeights struct {
+	Banner float64 `json:"banner"`
+	Video  float64 `json:"video"`
+	Native float64 `json:"native"`
+	Audio  float64 `json:"audio"`
+}
+
+// ChannelWeights are relative weights of distribution channels of generated requests.
+type ChannelWeights struct {
+	Site float64 `json:"site"`
+	App  float64 `json:"app"`
+	DOOH float64 `json:"dooh"`
+}
+
+// Config configures Generator.
+//
+// Rates are probabilities in [0, 1]; floors are CPMs in Currency.
+type Config struct {
+	Seed     int64          `json:"seed"`
+	Media    MediaWeights   `json:"media"`
+	Channels ChannelWeights `json:"channels"`
+
+	MaxImps    int     `json:"maximps"`    // maximum number of impressions per request
+	DealRate   float64 `json:"dealrate"`   // rate of impressions with PMP deals
+	SChainRate float64 `json:"schainrate"` // rate of requests with supply chain
+	EIDRate    float64 `json:"eidrate"`    // rate of requests with extended identifiers
+	SUARate    float64 `json:"suarate"`    // rate of requests with structured user agent (where client hints are available)
+
+	FloorMin float64 `json:"floormin"`
+	FloorMax float64 `json:"floormax"`
+	Currency string  `json:"currency"`
+
+	BidRate float64 `json:"bidrate"` // rate of impressions, bid on by Response
+}
+
+// DefaultConfig returns Config with a traffic mix, typical of an exchange.
+func DefaultConfig() Config {
+	return Config{
+		Seed:       1,
+		Media:      MediaWeights{Banner: 60, Video: 20, Native: 15, Audio: 5},
+		Channels:   ChannelWeights{Site: 55, App: 40, DOOH: 5},
+		MaxImps:    3,
+		DealRate:   0.15,
+		SChainRate: 0.8,
+		EIDRate:    0.5,
+		SUARate:    0.7,
+		FloorMin:   0.05,
+		FloorMax:   5,
+		Currency:   "USD",
+		BidRate:    0.4,
+	}
+}
+
+// Generator generates bid requests and bid responses.
+//
+// Generator is not safe for concurrent use.
+type Generator struct {
+	cfg Config
+	rnd *rand.Rand
+}
+
+// New returns a new Generator, seeded with cfg.Seed.
+func New(cfg Config) *Generator {
+	if cfg.MaxImps < 1 {
+		cfg.MaxImps = 1
+	}
+	if cfg.Currency == "" {
+		cfg.Currency = "USD"
+	}
+	if cfg.FloorMax < cfg.FloorMin {
+		cfg.FloorMax = cfg.FloorMin
+	}
+	return &Generator{cfg: cfg, rnd: rand.New(rand.NewSource(cfg.Seed))}
+}
+
+type channel int
+
+const (
+	channelSite channel = iota
+	channelApp
+	channelDOOH
+)
+
+// Request returns a new bid request.
+func (g *Generator) Request() *openrtb2.BidRequest {
+	ch := channel(g.weighted(g.cfg.Channels.Site, g.cfg.Channels.App, g.cfg.Channels.DOOH))
+	loc := locations[g.rnd.Intn(len(locations))]
+
+	req := &openrtb2.BidRequest{
+		ID:   g.uuid(),
+		AT:   1,
+		TMax: []int64{120, 200, 300, 500}[g.rnd.Intn(4)],
+		Cur:  []string{g.cfg.Currency},
+		BCat: g.sample(blockedCategories, 3),
+		BAdv: g.sample(advertisers, 1),
+		Source: &openrtb2.Source{
+			FD:  openrtb2.Int8Ptr(int8(g.rnd.Intn(2))),
+			TID: g.uuid(),
+		},
+		Regs: &openrtb2.Regs{},
+	}
+	if g.chance(0.2) {
+		req.AT = 2
+	}
+	if g.chance(g.cfg.SChainRate) {
+		req.Source.SChain = g.schain()
+	}
+
+	var profile deviceProfile
+	switch ch {
+	case channelSite:
+		profile = g.device(func(d deviceProfile) bool { return d.deviceType != adcom1.DeviceConnected })
+		req.Site = g.site(profile)
+	case channelApp:
+		profile = g.device(func(d deviceProfile) bool { return d.app })
+		req.App = g.app()
+	case channelDOOH:
+		req.DOOH = g.dooh()
+	}
+	req.Device = g.deviceFor(ch, profile, loc)
+	req.User = g.user(ch)
+
+	if loc.gdpr {
+		req.Regs.GDPR = openrtb2.Int8Ptr(1)
+		if req.User == nil {
+			req.User = &openrtb2.User{}
+		}
+		req.User.Consent = consent
+	} else if loc.country == "USA" {
+		req.Regs.USPrivacy = "1YNN"
+	}
+
+	n := 1
+	if g.cfg.MaxImps > 1 && g.chance(0.3) {
+		n += g.rnd.Intn(g.cfg.MaxImps)
+	}
+	for i := 0; i < n; i++ {
+		req.Imp = append(req.Imp, g.imp(ch, profile, i+1))
+	}
+	return req
+}
+
+// consent is the TCF 2.0 consent string of GDPR requests.
+const consent = "CPXxRfAPXxRfAAfKABENB-CgAAAAAAAAAAYgAAAAAAAA"
+
+func (g *Generator) imp(ch channel, profile deviceProfile, n int) openrtb2.Imp {
+	imp := openrtb2.Imp{
+		ID:          strconv.Itoa(n),
+		TagID:       fmt.Sprintf("tag-%d", 1000+g.rnd.Intn(9000)),
+		BidFloorCur: g.cfg.Currency,
+		Secure:      openrtb2.Int8Ptr(1),
+	}
+
+	media := g.cfg.Media
+	switch ch {
+	case channelDOOH:
+		media.Native, media.Audio = 0, 0
+	case channelSite:
+		if profile.deviceType == adcom1.DevicePC {
+			media.Audio = 0
+		}
+	}
+	if profile.deviceType == adcom1.DeviceConnected {
+		media.Banner, media.Native, media.Audio = 0, 0, 0
+	}
+
+	floor := g.cfg.FloorMin + g.rnd.Float64()*(g.cfg.FloorMax-g.cfg.FloorMin)
+	switch g.weighted(media.Banner, media.Video, media.Native, media.Audio) {
+	case 0:
+		imp.Banner = g.banner(ch)
+		if ch == channelApp && g.chance(0.2) {
+			imp.Instl = 1
+			imp.Banner.Format = []openrtb2.Format{{W: profile.w, H: profile.h}}
+			imp.Banner.W, imp.Banner.H = openrtb2.Int64Ptr(profile.w), openrtb2.Int64Ptr(profile.h)
+		}
+	case 1:
+		imp.Video = g.video(ch, profile)
+		floor *= 3
+	case 2:
+		imp.Native = g.native(ch)
+	case 3:
+		imp.Audio = g.audio()
+		floor *= 2
+	}
+	floor = math.Round(floor*100) / 100
+	imp.BidFloor = openrtb2.Float64Ptr(floor)
+
+	if ch == channelDOOH {
+		imp.Qty = &openrtb2.Qty{
+			Multiplier: float64(5 + g.rnd.Intn(46)),
+			SourceType: adcom1.MultiplierMeasurementVendorProvided,
+			Vendor:     "geopath.org",
+		}
+	}
+	if ch == channelApp {
+		imp.DisplayManager, imp.DisplayManagerVer = "GoogleMobileAds", "23.0.0"
+		if g.chance(0.1) {
+			imp.Rwdd = 1
+		}
+	}
+	if g.chance(g.cfg.DealRate) {
+		imp.PMP = g.pmp(floor)
+	}
+	return imp
+}
+
+func (g *Generator) banner(ch channel) *openrtb2.Banner {
+	banner := &openrtb2.Banner{Pos: adcom1.PositionAboveFold.Ptr()}
+	if g.chance(0.5) {
+		banner.Pos = adcom1.PositionBelowFold.Ptr()
+	}
+	sizes := bannerSizes
+	if ch == channelDOOH {
+		sizes = [][2]int64{{1920, 1080}, {1080, 1920}}
+	}
+	for _, i := range g.rnd.Perm(len(sizes))[:1+g.rnd.Intn(2)] {
+		banner.Format = append(banner.Format, openrtb2.Format{W: sizes[i][0], H: sizes[i][1]})
+	}
+	banner.W, banner.H = openrtb2.Int64Ptr(banner.Format[0].W), openrtb2.Int64Ptr(banner.Format[0].H)
+	if ch == channelApp {
+		banner.API = []adcom1.APIFramework{adcom1.APIMRAID20, adcom1.APIMRAID30, adcom1.APIOMID10}
+	}
+	return banner
+}
+
+func (g *Generator) video(ch channel, profile deviceProfile) *openrtb2.Video {
+	video := &openrtb2.Video{
+		MIMEs:       videoMIMEs[:1+g.rnd.Intn(len(videoMIMEs))],
+		MinDuration: 5,
+		MaxDuration: []int64{15, 30, 60}[g.rnd.Intn(3)],
+		Protocols: []adcom1.MediaCreativeSubtype{
+			adcom1.CreativeVAST30, adcom1.CreativeVAST30Wrapper,
+			adcom1.CreativeVAST40, adcom1.CreativeVAST40Wrapper,
+			adcom1.CreativeVAST42, adcom1.CreativeVAST42Wrapper,
+		},
+		W:              openrtb2.Int64Ptr(640),
+		H:              openrtb2.Int64Ptr(360),
+		StartDelay:     adcom1.StartPreRoll.Ptr(),
+		Plcmt:          adcom1.VideoPlcmtInstream,
+		Linearity:      adcom1.LinearityLinear,
+		Skip:           openrtb2.Int8Ptr(0),
+		PlaybackMethod: []adcom1.PlaybackMethod{adcom1.PlaybackPageLoadSoundOff},
+		API:            []adcom1.APIFramework{adcom1.APIOMID10},
+	}
+	switch {
+	case ch == channelDOOH || profile.deviceType == adcom1.DeviceConnected:
+		video.W, video.H = openrtb2.Int64Ptr(1920), openrtb2.Int64Ptr(1080)
+		video.PlaybackMethod = []adcom1.PlaybackMethod{adcom1.PlaybackContinuous}
+	case ch == channelApp:
+		video.W, video.H = openrtb2.Int64Ptr(profile.w), openrtb2.Int64Ptr(profile.h)
+		video.Plcmt = adcom1.VideoPlcmtInterstitial
+		video.PlaybackMethod = []adcom1.PlaybackMethod{adcom1.PlaybackPageLoadSoundOn}
+	default:
+		if g.chance(0.4) {
+			video.Plcmt = adcom1.VideoPlcmtNoContent
+			video.PlaybackMethod = []adcom1.PlaybackMethod{adcom1.PlaybackViewportSoundOff}
+		}
+		if g.chance(0.5) {
+			video.Skip, video.SkipAfter = openrtb2.Int8Ptr(1), 5
+		}
+	}
+	return video
+}
+
+func (g *Generator) audio() *openrtb2.Audio {
+	return &openrtb2.Audio{
+		MIMEs:       audioMIMEs[:1+g.rnd.Intn(len(audioMIMEs))],
+		MinDuration: 5,
+		MaxDuration: []int64{15, 30}[g.rnd.Intn(2)],
+		Protocols:   []adcom1.MediaCreativeSubtype{adcom1.CreativeVAST30, adcom1.CreativeVAST40, adcom1.CreativeVAST42},
+		StartDelay:  adcom1.StartPreRoll.Ptr(),
+		Feed:        []adcom1.FeedType{adcom1.FeedMusicService, adcom1.FeedPodcast, adcom1.FeedWebRadio}[g.rnd.Intn(3)],
+		Stitched:    openrtb2.Int8Ptr(int8(g.rnd.Intn(2))),
+	}
+}
+
+func (g *Generator) native(ch channel) *openrtb2.Native {
+	r := nativeRequest.Request{
+		Ver:       "1.2",
+		Context:   native1.ContextTypeContent,
+		PlcmtType: native1.PlacementTypeFeed,
+		PlcmtCnt:  1,
+		Assets: []nativeRequest.Asset{
+			{ID: 1, Required: 1, Title: &nativeRequest.Title{Len: 90}},
+			{ID: 2, Required: 1, Img: &nativeRequest.Image{Type: native1.ImageAssetTypeMain, WMin: 600, HMin: 314}},
+			{ID: 3, Img: &nativeRequest.Image{Type: native1.ImageAssetTypeIcon, W: 80, H: 80}},
+			{ID: 4, Required: 1, Data: &nativeRequest.Data{Type: native1.DataAssetTypeSponsored, Len: 25}},
+			{ID: 5, Data: &nativeRequest.Data{Type: native1.DataAssetTypeDesc, Len: 140}},
+		},
+		EventTrackers: []nativeRequest.EventTracker{
+			{Event: native1.EventTypeImpression, Methods: []native1.EventTrackingMethod{native1.EventTrackingMethodImage}},
+		},
+		Privacy: 1,
+	}
+	if ch == channelApp {
+		r.Context = native1.ContextTypeSocial
+		r.Assets = append(r.Assets, nativeRequest.Asset{ID: 6, Data: &nativeRequest.Data{Type: native1.DataAssetTypeCTAText, Len: 15}})
+	}
+	buf, _ := json.Marshal(&r)
+	return &openrtb2.Native{Request: string(buf), Ver: "1.2"}
+}
+
+func (g *Generator) pmp(floor float64) *openrtb2.PMP {
+	pmp := &openrtb2.PMP{}
+	if g.chance(0.3) {
+		pmp.PrivateAuction = 1
+	}
+	for i := 0; i < 1+g.rnd.Intn(2); i++ {
+		pmp.Deals = append(pmp.Deals, openrtb2.Deal{
+			ID:          fmt.Sprintf("deal-%05d", g.rnd.Intn(100000)),
+			BidFloor:    math.Round(floor*(1+g.rnd.Float64())*100) / 100,
+			BidFloorCur: g.cfg.Currency,
+			AT:          1,
+			WSeat:       g.sample(seats, 2),
+		})
+	}
+	return pmp
+}
+
+func (g *Generator) site(profile deviceProfile) *openrtb2.Site {
+	p := sites[g.rnd.Intn(len(sites))]
+	section := p.sections[g.rnd.Intn(len(p.sections))]
+	site := &openrtb2.Site{
+		ID:        p.id + "-web",
+		Name:      p.name,
+		Domain:    p.domain,
+		Cat:       p.cat,
+		Page:      fmt.Sprintf("https://www.%s/%s/article-%d", p.domain, section, g.rnd.Intn(100000)),
+		Publisher: &openrtb2.Publisher{ID: p.id, Name: p.name, Domain: p.domain},
+	}
+	if g.chance(0.6) {
+		site.Ref = []string{"https://www.google.com/", "https://www.facebook.com/", "https://t.co/"}[g.rnd.Intn(3)]
+	}
+	if profile.mobile == 1 {
+		site.Mobile = openrtb2.Int8Ptr(1)
+	}
+	return site
+}
+
+func (g *Generator) app() *openrtb2.App {
+	p := apps[g.rnd.Intn(len(apps))]
+	return &openrtb2.App{
+		ID:        p.id + "-app",
+		Name:      p.name,
+		Bundle:    p.bundle,
+		Domain:    p.domain,
+		StoreURL:  p.storeURL,
+		Cat:       p.cat,
+		Ver:       fmt.Sprintf("%d.%d.%d", 1+g.rnd.Intn(5), g.rnd.Intn(20), g.rnd.Intn(10)),
+		Publisher: &openrtb2.Publisher{ID: p.id, Name: p.name, Domain: p.domain},
+	}
+}
+
+func (g *Generator) dooh() *openrtb2.DOOH {
+	p := screens[g.rnd.Intn(len(screens))]
+	return &openrtb2.DOOH{
+		ID:           p.id + "-screens",
+		Name:         p.name,
+		VenueType:    []string{p.sections[g.rnd.Intn(len(p.sections))]},
+		VenueTypeTax: adcom1.VenueTaxonomyOpenOOH11.Ptr(),
+		Domain:       p.domain,
+		Publisher:    &openrtb2.Publisher{ID: p.id, Name: p.name, Domain: p.domain},
+	}
+}
+
+// device returns a random device profile, satisfying ok.
+func (g *Generator) device(ok func(deviceProfile) bool) deviceProfile {
+	for {
+		if d := devices[g.rnd.Intn(len(devices))]; ok(d) {
+			return d
+		}
+	}
+}
+
+func (g *Generator) deviceFor(ch channel, profile deviceProfile, loc location) *openrtb2.Device {
+	geo := &openrtb2.Geo{
+		Lat:       floatPtr(math.Round((loc.lat+g.rnd.NormFloat64()*0.05)*1e4) / 1e4),
+		Lon:       floatPtr(math.Round((loc.lon+g.rnd.NormFloat64()*0.05)*1e4) / 1e4),
+		Type:      adcom1.LocationIP,
+		IPService: adcom1.LocationServiceMaxMind,
+		Country:   loc.country,
+		Region:    loc.region,
+		Metro:     loc.metro,
+		City:      loc.city,
+		ZIP:       loc.zip,
+		UTCOffset: loc.utcOffset,
+	}
+
+	if ch == channelDOOH {
+		geo.Type, geo.IPService, geo.Accuracy = adcom1.LocationUserProvided, 0, 10
+		return &openrtb2.Device{
+			Geo:            geo,
+			DeviceType:     adcom1.DeviceOOH,
+			Make:           "Samsung",
+			Model:          "QM55R",
+			W:              1920,
+			H:              1080,
+			IP:             g.ip(),
+			ConnectionType: adcom1.ConnectionEthernet.Ptr(),
+		}
+	}
+
+	d := &openrtb2.Device{
+		Geo:        geo,
+		UA:         profile.ua,
+		IP:         g.ip(),
+		DeviceType: profile.deviceType,
+		Make:       profile.make,
+		Model:      profile.model,
+		OS:         profile.os,
+		OSV:        profile.osv,
+		W:          profile.w,
+		H:          profile.h,
+		PxRatio:    profile.pxRatio,
+		JS:         openrtb2.Int8Ptr(1),
+		Language:   loc.lang,
+	}
+	switch {
+	case profile.mobile == 1 && g.chance(0.5):
+		d.ConnectionType = []adcom1.ConnectionType{adcom1.Connection4G, adcom1.Connection5G}[g.rnd.Intn(2)].Ptr()
+		d.Carrier = carriers[g.rnd.Intn(len(carriers))]
+	case profile.mobile == 1 || profile.deviceType == adcom1.DeviceConnected:
+		d.ConnectionType = adcom1.ConnectionWIFI.Ptr()
+	default:
+		d.ConnectionType = []adcom1.ConnectionType{adcom1.ConnectionEthernet, adcom1.ConnectionWIFI}[g.rnd.Intn(2)].Ptr()
+	}
+	if ch == channelApp {
+		geo.Type = adcom1.LocationGPS
+		d.IFA = g.uuid()
+		d.Lmt = openrtb2.Int8Ptr(0)
+		if g.chance(0.25) {
+			d.IFA, d.Lmt = "00000000-0000-0000-0000-000000000000", openrtb2.Int8Ptr(1)
+		}
+	}
+	if profile.browser != "" && g.chance(g.cfg.SUARate) {
+		d.SUA = &openrtb2.UserAgent{
+			Browsers: []openrtb2.BrandVersion{
+				{Brand: "Not-A.Brand", Version: []string{"99"}},
+				{Brand: "Chromium", Version: strings.Split(profile.browserVer, ".")},
+				{Brand: profile.browser, Version: strings.Split(profile.browserVer, ".")},
+			},
+			Platform: &openrtb2.BrandVersion{Brand: profile.platform, Version: strings.Split(profile.osv, ".")},
+			Mobile:   openrtb2.Int8Ptr(profile.mobile),
+			Model:    profile.model,
+			Source:   adcom1.UASourceHighEntropy,
+		}
+	}
+	return d
+}
+
+func (g *Generator) user(ch channel) *openrtb2.User {
+	if ch == channelDOOH {
+		return nil
+	}
+	user := &openrtb2.User{}
+	if ch == channelSite {
+		user.ID = g.uuid()
+	}
+	if g.chance(g.cfg.EIDRate) {
+		for _, source := range g.sample(eidSources, 3) {
+			eid := openrtb2.EID{Source: source, UIDs: []openrtb2.UID{{ID: g.uuid(), AType: adcom1.AgentTypeWeb}}}
+			switch source {
+			case "liveramp.com", "uidapi.com":
+				eid.UIDs[0].AType, eid.MM = adcom1.AgentTypePerson, adcom1.MatchMethodAuthenticated
+			default:
+				if ch == channelApp {
+					eid.UIDs[0].AType = adcom1.AgentTypeApp
+				}
+			}
+			user.EIDs = append(user.EIDs, eid)
+		}
+	}
+	if user.ID == "" && len(user.EIDs) == 0 {
+		return nil
+	}
+	return user
+}
+
+func (g *Generator) schain() *openrtb2.SupplyChain {
+	schain := &openrtb2.SupplyChain{Complete: 1, Ver: "1.0"}
+	for _, i := range g.rnd.Perm(len(schainNodes))[:1+g.rnd.Intn(2)] {
+		schain.Nodes = append(schain.Nodes, openrtb2.SupplyChainNode{
+			ASI: schainNodes[i],
+			SID: strconv.Itoa(10000 + g.rnd.Intn(90000)),
+			HP:  openrtb2.Int8Ptr(1),
+		})
+	}
+	return schain
+}
+
+// Response returns a bid response to req, bidding at or above floors of (about BidRate of) impressions;
+// nil is returned for no bid.
+func (g *Generator) Response(req *openrtb2.BidRequest) *openrtb2.BidResponse {
+	cur := g.cfg.Currency
+	if len(req.Cur) != 0 {
+		cur = req.Cur[0]
+	}
+
+	bySeat := make(map[string][]openrtb2.Bid)
+	for i := range req.Imp {
+		if !g.chance(g.cfg.BidRate) {
+			continue
+		}
+		seat, bid, ok := g.bid(req, &req.Imp[i])
+		if ok {
+			bySeat[seat] = append(bySeat[seat], bid)
+		}
+	}
+	if len(bySeat) == 0 {
+		return nil
+	}
+
+	resp := &openrtb2.BidResponse{ID: req.ID, BidID: g.uuid(), Cur: cur}
+	for seat, bids := range bySeat {
+		resp.SeatBid = append(resp.SeatBid, openrtb2.SeatBid{Seat: seat, Bid: bids})
+	}
+	sort.Slice(resp.SeatBid, func(i, j int) bool { return resp.SeatBid[i].Seat < resp.SeatBid[j].Seat })
+	return resp
+}
+
+// minPrice is the base price of bids on imps without floors.
+const minPrice = 0.01
+
+// bid returns a bid on imp, with seat of bidder.
+func (g *Generator) bid(req *openrtb2.BidRequest, imp *openrtb2.Imp) (string, openrtb2.Bid, bool) {
+	seat, floor := seats[g.rnd.Intn(len(seats))], 0.0
+	if imp.BidFloor != nil {
+		floor = *imp.BidFloor
+	}
+	bid := openrtb2.Bid{ImpID: imp.ID}
+
+	if imp.PMP != nil && len(imp.PMP.Deals) != 0 && (imp.PMP.PrivateAuction == 1 || g.chance(0.7)) {
+		deal := imp.PMP.Deals[g.rnd.Intn(len(imp.PMP.Deals))]
+		if len(deal.WSeat) != 0 {
+			seat = deal.WSeat[g.rnd.Intn(len(deal.WSeat))]
+		}
+		bid.DealID = deal.ID
+		if deal.BidFloor > floor {
+			floor = deal.BidFloor
+		}
+	} else if imp.PMP != nil && imp.PMP.PrivateAuction == 1 {
+		return "", bid, false
+	}
+
+	crid := g.rnd.Intn(100000)
+	bid.ID = g.uuid()
+	bid.Price = math.Ceil(math.Max(floor, minPrice)*(1+g.rnd.Float64()*1.5)*100) / 100
+	bid.AdID = fmt.Sprintf("ad-%05d", crid)
+	bid.CrID = fmt.Sprintf("cr-%05d", crid)
+	bid.CID = fmt.Sprintf("camp-%03d", crid%1000)
+	bid.BURL = fmt.Sprintf("https://%s.example/billing?bid=%s&price=${AUCTION_PRICE}", seat, bid.ID)
+	bid.ADomain = []string{g.advertiser(req.BAdv)}
+	bid.Cat = []string{[]string{"IAB2", "IAB3", "IAB18", "IAB20", "IAB22"}[g.rnd.Intn(5)]}
+
+	switch {
+	case imp.Banner != nil:
+		bid.MType = openrtb2.MarkupBanner
+		if len(imp.Banner.Format) != 0 {
+			f := imp.Banner.Format[g.rnd.Intn(len(imp.Banner.Format))]
+			bid.W, bid.H = f.W, f.H
+		}
+		bid.AdM = fmt.Sprintf(`<a href="https://%s/?cr=%s"><img src="https://cdn.%s.example/%s.jpg" width="%d" height="%d"></a>`,
+			bid.ADomain[0], bid.CrID, seat, bid.CrID, bid.W, bid.H)
+	case imp.Video != nil:
+		bid.MType = openrtb2.MarkupVideo
+		bid.Dur = imp.Video.MaxDuration
+		bid.AdM = vast(seat, bid.CrID, bid.Dur)
+		if imp.Video.W != nil && imp.Video.H != nil {
+			bid.W, bid.H = *imp.Video.W, *imp.Video.H
+		}
+	case imp.Audio != nil:
+		bid.MType = openrtb2.MarkupAudio
+		bid.Dur = imp.Audio.MaxDuration
+		bid.AdM = vast(seat, bid.CrID, bid.Dur)
+	case imp.Native != nil:
+		bid.MType = openrtb2.MarkupNative
+		bid.AdM = nativeMarkup(imp.Native.Request, bid.ADomain[0], bid.CrID)
+	}
+	return seat, bid, true
+}
+
+// advertiser returns a random advertiser domain, not blocked by badv.
+func (g *Generator) advertiser(badv []string) string {
+	for {
+		adv := advertisers[g.rnd.Intn(len(advertisers))]
+		blocked := false
+		for _, b := range badv {
+			blocked = blocked || b == adv
+		}
+		if !blocked {
+			return adv
+		}
+	}
+}
+
+func vast(seat, crid string, dur int64) string {
+	return fmt.Sprintf(`<VAST version="4.2"><Ad id="%[2]s"><InLine><AdSystem>%[1]s</AdSystem><AdTitle>%[2]s</AdTitle>`+
+		`<Impression><![CDATA[https://%[1]s.example/imp?cr=%[2]s]]></Impression><Creatives><Creative id="%[2]s"><Linear>`+
+		`<Duration>00:%02[3]d:%02[4]d</Duration><MediaFiles><MediaFile delivery="progressive" type="video/mp4" width="1280" height="720">`+
+		`<![CDATA[https://cdn.%[1]s.example/%[2]s.mp4]]></MediaFile></MediaFiles></Linear></Creative></Creatives></InLine></Ad></VAST>`,
+		seat, crid, dur/60, dur%60)
+}
+
+// nativeMarkup returns native response markup, filling assets of native request markup.
+func nativeMarkup(markup, adomain, crid string) string {
+	var req nativeRequest.Request
+	_ = json.Unmarshal([]byte(markup), &req)
+
+	resp := nativeResponse.Response{
+		Ver:  req.Ver,
+		Link: nativeResponse.Link{URL: fmt.Sprintf("https://%s/?cr=%s", adomain, crid)},
+		EventTrackers: []nativeResponse.EventTracker{
+			{Event: native1.EventTypeImpression, Method: native1.EventTrackingMethodImage, URL: "https://" + adomain + "/imp?cr=" + crid},
+		},
+	}
+	for _, a := range req.Assets {
+		id := a.ID
+		asset := nativeResponse.Asset{ID: &id}
+		switch {
+		case a.Title != nil:
+			asset.Title = &nativeResponse.Title{Text: "Discover what's new at " + adomain}
+		case a.Img != nil:
+			w, h := a.Img.W, a.Img.H
+			if w == 0 {
+				w, h = a.Img.WMin, a.Img.HMin
+			}
+			asset.Img = &nativeResponse.Image{Type: a.Img.Type, URL: fmt.Sprintf("https://cdn.%s/%s-%d.jpg", adomain, crid, id), W: w, H: h}
+		case a.Data != nil:
+			asset.Data = &nativeResponse.Data{Type: a.Data.Type, Value: dataValue(a.Data.Type, adomain)}
+		default:
+			continue
+		}
+		resp.Assets = append(resp.Assets, asset)
+	}
+	buf, _ := json.Marshal(&resp)
+	return string(buf)
+}
+
+func dataValue(t native1.DataAssetType, adomain string) string {
+	switch t {
+	case native1.DataAssetTypeSponsored:
+		return adomain
+	case native1.DataAssetTypeCTAText:
+		return "Learn more"
+	}
+	return "The best deals of the season, only this week."
+}
+
+// weighted returns index of a weight, picked with probability, proportional to it.
+func (g *Generator) weighted(weights ...float64) int {
+	var total float64
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	x := g.rnd.Float64() * total
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return len(weights) - 1
+}
+
+func (g *Generator) chance(p float64) bool {
+	return g.rnd.Float64() < p
+}
+
+// sample returns up to max distinct random elements of list (nil, if none).
+func (g *Generator) sample(list []string, max int) []string {
+	n := g.rnd.Intn(max + 1)
+	if n == 0 {
+		return nil
+	}
+	res := make([]string, n)
+	for i, j := range g.rnd.Perm(len(list))[:n] {
+		res[i] = list[j]
+	}
+	return res
+}
+
+// uuid returns a random (version 4) UUID.
+func (g *Generator) uuid() string {
+	var b [16]byte
+	g.rnd.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// ip returns a random IPv4 address, truncated to /24.
+func (g *Generator) ip() string {
+	first := []int{24, 47, 68, 73, 81, 85, 92, 176, 189, 201}[g.rnd.Intn(10)]
+	return fmt.Sprintf("%d.%d.%d.0", first, g.rnd.Intn(256), g.rnd.Intn(256))
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
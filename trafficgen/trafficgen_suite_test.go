@@ -0,0 +1,13 @@
+package trafficgen_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestTrafficgen(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Trafficgen Suite")
+}
@@ -0,0 +1,192 @@
+package trafficgen_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+
+	. "github.com/prebid/openrtb/v20/trafficgen"
+
+	"github.com/prebid/openrtb/v20/native1/response"
+	"github.com/prebid/openrtb/v20/openrtb2"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generator", func() {
+	It("should be deterministic", func() {
+		var a, b bytes.Buffer
+		Expect(New(DefaultConfig()).WriteNDJSON(&a, &b, 50)).To(Succeed())
+
+		var c, d bytes.Buffer
+		Expect(New(DefaultConfig()).WriteNDJSON(&c, &d, 50)).To(Succeed())
+		Expect(c.String()).To(Equal(a.String()))
+		Expect(d.String()).To(Equal(b.String()))
+
+		cfg := DefaultConfig()
+		cfg.Seed = 2
+		var e bytes.Buffer
+		Expect(New(cfg).WriteNDJSON(&e, nil, 50)).To(Succeed())
+		Expect(e.String()).NotTo(Equal(a.String()))
+	})
+
+	It("should follow weights", func() {
+		cfg := DefaultConfig()
+		cfg.Media = MediaWeights{Video: 1}
+		cfg.Channels = ChannelWeights{App: 1}
+		g := New(cfg)
+		for i := 0; i < 100; i++ {
+			req := g.Request()
+			Expect(req.App).NotTo(BeNil())
+			Expect(req.Site).To(BeNil())
+			Expect(req.DOOH).To(BeNil())
+			for _, imp := range req.Imp {
+				Expect(imp.Video).NotTo(BeNil())
+				Expect(imp.Banner).To(BeNil())
+			}
+		}
+	})
+
+	It("should generate a mix of traffic", func() {
+		g := New(DefaultConfig())
+		var site, app, dooh, deals, schains int
+		media := make(map[string]int)
+		for i := 0; i < 2000; i++ {
+			req := g.Request()
+			switch {
+			case req.Site != nil:
+				site++
+			case req.App != nil:
+				app++
+			case req.DOOH != nil:
+				dooh++
+			}
+			if req.Source.SChain != nil {
+				schains++
+			}
+			Expect(req.Imp).NotTo(BeEmpty())
+			Expect(len(req.Imp)).To(BeNumerically("<=", 3))
+			for _, imp := range req.Imp {
+				switch {
+				case imp.Banner != nil:
+					media["banner"]++
+				case imp.Video != nil:
+					media["video"]++
+				case imp.Native != nil:
+					media["native"]++
+				case imp.Audio != nil:
+					media["audio"]++
+				}
+				if imp.PMP != nil {
+					deals++
+				}
+			}
+		}
+		Expect(site).To(BeNumerically("~", 1100, 150))
+		Expect(app).To(BeNumerically("~", 800, 150))
+		Expect(dooh).To(BeNumerically("~", 100, 50))
+		Expect(schains).To(BeNumerically("~", 1600, 150))
+		Expect(deals).To(BeNumerically(">", 0))
+		Expect(media).To(HaveLen(4))
+		Expect(media["banner"]).To(BeNumerically(">", media["video"]))
+	})
+
+	It("should bid above zero on imps without floors", func() {
+		cfg := DefaultConfig()
+		cfg.BidRate = 1
+		g := New(cfg)
+		for i := 0; i < 100; i++ {
+			req := g.Request()
+			for j := range req.Imp {
+				req.Imp[j].BidFloor, req.Imp[j].PMP = nil, nil
+			}
+			resp := g.Response(req)
+			Expect(resp).NotTo(BeNil())
+			for _, sb := range resp.SeatBid {
+				for _, bid := range sb.Bid {
+					Expect(bid.Price).To(BeNumerically(">", 0))
+				}
+			}
+		}
+	})
+
+	It("should bid on imps of request, at or above floors", func() {
+		cfg := DefaultConfig()
+		cfg.BidRate = 1
+		cfg.DealRate = 0.5
+		g := New(cfg)
+		for i := 0; i < 500; i++ {
+			req := g.Request()
+			resp := g.Response(req)
+			Expect(resp).NotTo(BeNil())
+			Expect(resp.ID).To(Equal(req.ID))
+			Expect(resp.Cur).To(Equal(req.Cur[0]))
+
+			imps := make(map[string]openrtb2.Imp)
+			for _, imp := range req.Imp {
+				imps[imp.ID] = imp
+			}
+			for _, sb := range resp.SeatBid {
+				for _, bid := range sb.Bid {
+					imp, ok := imps[bid.ImpID]
+					Expect(ok).To(BeTrue(), bid.ImpID)
+					Expect(imp.BidFloor).NotTo(BeNil())
+					Expect(bid.Price).To(BeNumerically(">", 0))
+					Expect(bid.Price).To(BeNumerically(">=", *imp.BidFloor))
+					Expect(bid.ADomain).NotTo(ContainElement(BeElementOf(req.BAdv)))
+					if bid.DealID != "" {
+						Expect(imp.PMP).NotTo(BeNil())
+						for _, deal := range imp.PMP.Deals {
+							if deal.ID == bid.DealID {
+								Expect(bid.Price).To(BeNumerically(">=", deal.BidFloor))
+								if len(deal.WSeat) != 0 {
+									Expect(deal.WSeat).To(ContainElement(sb.Seat))
+								}
+							}
+						}
+					} else if imp.PMP != nil {
+						Expect(imp.PMP.PrivateAuction).To(BeZero())
+					}
+					if imp.Native != nil {
+						Expect(bid.MType).To(Equal(openrtb2.MarkupNative))
+						var r response.Response
+						Expect(json.Unmarshal([]byte(bid.AdM), &r)).To(Succeed())
+						Expect(r.Assets).NotTo(BeEmpty())
+					}
+				}
+			}
+		}
+	})
+
+	It("should not bid at zero bid rate", func() {
+		cfg := DefaultConfig()
+		cfg.BidRate = 0
+		g := New(cfg)
+		Expect(g.Response(g.Request())).To(BeNil())
+	})
+
+	It("should write NDJSON", func() {
+		var reqs, resps bytes.Buffer
+		Expect(New(DefaultConfig()).WriteNDJSON(&reqs, &resps, 20)).To(Succeed())
+
+		reqLines, respLines := bufio.NewScanner(&reqs), bufio.NewScanner(&resps)
+		reqLines.Buffer(nil, 1<<20)
+		respLines.Buffer(nil, 1<<20)
+		n := 0
+		for reqLines.Scan() {
+			Expect(respLines.Scan()).To(BeTrue())
+
+			var req openrtb2.BidRequest
+			Expect(json.Unmarshal(reqLines.Bytes(), &req)).To(Succeed())
+			var resp *openrtb2.BidResponse
+			Expect(json.Unmarshal(respLines.Bytes(), &resp)).To(Succeed())
+			if resp != nil {
+				Expect(resp.ID).To(Equal(req.ID))
+			}
+			n++
+		}
+		Expect(respLines.Scan()).To(BeFalse())
+		Expect(n).To(Equal(20))
+	})
+})